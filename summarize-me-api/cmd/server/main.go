@@ -5,18 +5,30 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"summarize-me-api/internal/actions"
 	"summarize-me-api/internal/api/router"
+	"summarize-me-api/internal/auth"
 	"summarize-me-api/internal/config"
+	"summarize-me-api/internal/embedding"
+	"summarize-me-api/internal/feedback"
+	"summarize-me-api/internal/fulltext"
+	"summarize-me-api/internal/health"
+	"summarize-me-api/internal/mail"
+	"summarize-me-api/internal/media"
 	"summarize-me-api/internal/platform"
+	"summarize-me-api/internal/remote"
+	"summarize-me-api/internal/resilience"
 	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+	"summarize-me-api/internal/vector"
 
-	"cloud.google.com/go/storage" // <-- TAMBAHKAN IMPORT INI
+	"cloud.google.com/go/firestore"
+	"cloud.google.com/go/storage"
+	"github.com/google/generative-ai-go/genai"
 	"github.com/joho/godotenv"
 )
 
-// !!! PENTING: Ganti nilai ini dengan nama GCS Bucket Anda !!!
-const GCS_BUCKET_NAME = "summarizeme_bucket"
-
 func main() {
 	// Muat variabel dari .env HANYA jika file ada (untuk development lokal)
 	// Di Cloud Run, file .env tidak akan ada, jadi ini akan di-skip.
@@ -27,48 +39,209 @@ func main() {
 		log.Println("File .env ditemukan dan dimuat untuk development lokal.")
 	}
 
-	// Load Konfigurasi
-	cfg := config.LoadConfig()
+	// Load Konfigurasi (default -> file YAML -> env -> flag)
+	cfg, err := config.LoadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Konfigurasi tidak valid:\n%v", err)
+	}
+	log.Printf("Konfigurasi efektif:\n%s", cfg.Redacted())
 
 	ctx := context.Background()
 
+	// --- Readiness Probe ---
+	healthChecker := health.NewChecker(cfg.Health.ProbeTimeout, cfg.Health.CacheTTL)
+
 	// --- Inisialisasi Klien Eksternal ---
-	authClient, err := platform.InitFirebaseAuth(ctx, cfg.FirebaseProjectID)
+	authenticator, err := newAuthenticator(ctx, cfg, healthChecker)
 	if err != nil {
-		log.Fatalf("Gagal inisialisasi Firebase Auth: %v", err)
+		log.Fatalf("Gagal inisialisasi autentikasi (%s): %v", cfg.Auth.Provider, err)
 	}
-	
+
 	speechClient, err := platform.InitSpeechClient(ctx)
-    if err != nil {
-        log.Fatalf("Gagal inisialisasi Speech Client: %v", err)
-    }
-    defer speechClient.Close()
+	if err != nil {
+		log.Fatalf("Gagal inisialisasi Speech Client: %v", err)
+	}
+	defer speechClient.Close()
 
-    geminiClient, err := platform.InitGeminiClient(ctx, cfg.GeminiAPIKey)
-    if err != nil {
-        log.Fatalf("Gagal inisialisasi Gemini Client: %v", err)
-    }
-    defer geminiClient.Close()
+	geminiClient, err := platform.InitGeminiClient(ctx, cfg.GeminiAPIKey)
+	if err != nil {
+		log.Fatalf("Gagal inisialisasi Gemini Client: %v", err)
+	}
+	defer geminiClient.Close()
 
-    geminiModel := geminiClient.GenerativeModel("gemini-2.5-flash") // Anda bisa ganti modelnya jika perlu
+	geminiModel := geminiClient.GenerativeModel(cfg.Gemini.Model)
 
-	// --- Inisialisasi Storage Client (BARU) ---
+	// --- Inisialisasi Storage Client ---
 	storageClient, err := storage.NewClient(ctx)
 	if err != nil {
 		log.Fatalf("Gagal inisialisasi Storage Client: %v", err)
 	}
-	// ----------------------------------------
 
-	// --- Inisialisasi Service (DIPERBARUI) ---
+	// --- Ekstraksi audio dari video ---
+	transcoder := media.NewTranscoder(media.Options{
+		FFmpegPath:       cfg.Media.FFmpegPath,
+		FFprobePath:      cfg.Media.FFprobePath,
+		SampleRateHertz:  cfg.Speech.SampleRateHertz,
+		Keyframes:        cfg.Media.Keyframes,
+		KeyframeInterval: cfg.Media.KeyframeInterval,
+	})
+
+	// --- Inisialisasi Service ---
 	summarizeService := services.NewSummarizeService(
 		speechClient,
 		geminiModel,
+		cfg.Gemini.Model,
 		storageClient,
-		GCS_BUCKET_NAME,
+		cfg.GCSBucketName,
+		cfg.Speech.LanguageCode,
+		int32(cfg.Speech.SampleRateHertz),
+		transcoder,
+	)
+	summarizeService.AllowModels(geminiClient, cfg.Gemini.AllowedModels)
+	if err := summarizeService.UseModelChain(geminiClient, cfg.Gemini.Fallbacks, modelRoutes(cfg)); err != nil {
+		log.Fatalf("Konfigurasi routing model tidak valid: %v", err)
+	}
+	providers := resilience.NewRegistry(resilience.BreakerOptions{
+		Threshold: cfg.Resilience.BreakerThreshold,
+		Cooldown:  cfg.Resilience.BreakerCooldown,
+	})
+	geminiPolicy := resilience.Policy(cfg.Resilience.Gemini)
+	summarizeService.UseResilience(
+		func(model string) *resilience.Executor { return providers.Executor("gemini/"+model, geminiPolicy) },
+		providers.Executor("speech", resilience.Policy(cfg.Resilience.Speech)),
+		providers.Executor("storage", resilience.Policy(cfg.Resilience.Storage)),
 	)
 
+	healthChecker.Register("speech", health.SpeechProbe(speechClient))
+	healthChecker.Register("gemini", health.GeminiProbe(geminiModel))
+	healthChecker.Register("bucket", health.BucketProbe(storageClient, cfg.GCSBucketName))
+	healthChecker.Register("ffmpeg", health.FFmpegProbe(transcoder))
+
+	// --- Penyimpanan Lokal ---
+	tokenStore := mustOpenCollection[services.AccessToken](cfg, "access_tokens")
+	userStore := mustOpenCollection[services.User](cfg, "users")
+	jobStore := mustOpenCollection[services.Job](cfg, "jobs")
+	summaryStore := mustOpenCollection[services.Summary](cfg, "summaries")
+	ratingStore := mustOpenCollection[services.Rating](cfg, "ratings")
+	webhookStore := mustOpenCollection[services.Webhook](cfg, "webhooks")
+	deliveryStore := mustOpenCollection[services.WebhookDelivery](cfg, "webhook_deliveries")
+	batchStore := mustOpenCollection[services.Batch](cfg, "batches")
+	conversationStore := mustOpenCollection[services.Conversation](cfg, "conversations")
+	actionExportStore := mustOpenCollection[services.ActionExport](cfg, "action_exports")
+	usageStore := mustOpenCollection[services.UsageRecord](cfg, "usage")
+	glossaryStore := mustOpenCollection[services.Glossary](cfg, "glossaries")
+
+	jobService := services.NewJobService(jobStore)
+	if n := jobService.FailStale(); n > 0 {
+		log.Printf("Menandai %d job yang tertinggal dari proses sebelumnya sebagai gagal", n)
+	}
+	summaryService := services.NewSummaryService(summaryStore, ratingStore)
+	glossaryService := services.NewGlossaryService(glossaryStore)
+	jobRunner := services.NewJobRunner(summarizeService, jobService, summaryService, cfg.Jobs.Workers, cfg.Jobs.QueueSize)
+	jobRunner.UseGlossaries(glossaryService)
+	liveService := services.NewLiveService(services.NewSpeechStreamTranscriber(speechClient), summarizeService, jobService, jobRunner, services.LiveOptions{
+		SummaryInterval: cfg.Live.SummaryInterval,
+		MaxDuration:     cfg.Live.MaxDuration,
+		LanguageCode:    cfg.Speech.LanguageCode,
+	})
+	batchService := services.NewBatchService(batchStore, jobService, summaryService, jobRunner, services.BatchLimits{
+		MaxFiles: cfg.Jobs.MaxInputFiles,
+		MaxBytes: int64(cfg.Jobs.MaxBatchMB) << 20,
+	})
+	webhookService := services.NewWebhookService(webhookStore, deliveryStore, services.WebhookOptions{
+		MaxAttempts:    cfg.Webhooks.MaxAttempts,
+		InitialBackoff: cfg.Webhooks.InitialBackoff,
+		MaxBackoff:     cfg.Webhooks.MaxBackoff,
+		Timeout:        cfg.Webhooks.Timeout,
+		AllowPrivate:   cfg.Webhooks.AllowPrivate,
+	})
+	jobService.Subscribe(webhookService.HandleJobFinished)
+	go webhookService.Run(ctx)
+	usageService := services.NewUsageService(usageStore, priceTable(cfg))
+	jobService.Subscribe(usageService.HandleJobFinished)
+	regenerateService := services.NewRegenerateService(summarizeService, summaryService)
+	regenerateService.TrackUsage(usageService)
+	liveService.TrackUsage(usageService)
+	qaService := services.NewQAService(geminiModel, cfg.Gemini.Model, summaryService, conversationStore)
+	qaService.TrackUsage(usageService)
+	regenerateService.UseGlossaries(glossaryService, jobService)
+	fullTextPath := ""
+	if cfg.Storage.DataDir != "" {
+		fullTextPath = filepath.Join(cfg.Storage.DataDir, "fulltext.bleve")
+	}
+	fullTextIndex, err := fulltext.Open(fullTextPath)
+	if err != nil {
+		log.Fatalf("Gagal membuka index full-text: %v", err)
+	}
+	defer fullTextIndex.Close()
+	fullTextService := services.NewFullTextService(fullTextIndex, summaryService)
+	summaryService.Subscribe(fullTextService.HandleSummarySaved)
+	go fullTextService.Backfill()
+
+	var searchService *services.SearchService
+	if embedder := newEmbedder(cfg, geminiClient); embedder != nil {
+		searchStore := mustOpenCollection[services.SearchDocument](cfg, "search_index")
+		vectorDir := ""
+		if cfg.Storage.DataDir != "" {
+			vectorDir = filepath.Join(cfg.Storage.DataDir, "search_vectors")
+		}
+		vectorStore, err := vector.OpenStore(vectorDir)
+		if err != nil {
+			log.Fatalf("Gagal membuka penyimpanan vektor pencarian: %v", err)
+		}
+		searchService = services.NewSearchService(embedder, summaryService, searchStore, vectorStore)
+		summaryService.Subscribe(searchService.HandleSummarySaved)
+		go searchService.Backfill(ctx)
+	}
+	if cfg.Email.SMTPHost != "" {
+		mailer := mail.NewMailer(mail.Options{
+			Host:     cfg.Email.SMTPHost,
+			Port:     cfg.Email.SMTPPort,
+			Username: cfg.Email.Username,
+			Password: cfg.Email.Password,
+			From:     cfg.Email.From,
+		})
+		jobService.Subscribe(services.NewEmailNotifier(mailer, userStore).HandleJobFinished)
+	}
+
+	actionConnectors, err := newActionConnectors(cfg)
+	if err != nil {
+		log.Fatalf("Gagal inisialisasi connector action item: %v", err)
+	}
+
+	feedbackSink, closeFeedback, err := newFeedbackSink(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Gagal inisialisasi feedback sink: %v", err)
+	}
+	defer closeFeedback()
+
 	// --- Setup Router ---
-	r := router.SetupRouter(cfg, authClient, summarizeService)
+	r := router.SetupRouter(cfg, router.Dependencies{
+		Authenticator:  authenticator,
+		HealthChecker:  healthChecker,
+		JobRunner:      jobRunner,
+		TokenService:   services.NewTokenService(tokenStore),
+		UserService:    services.NewUserService(userStore, cfg.Auth.Admins),
+		JobService:     jobService,
+		SummaryService: summaryService,
+		QAService:      qaService,
+		Regenerate:     regenerateService,
+		SearchService:  searchService,
+		FullText:       fullTextService,
+		ActionService:  services.NewActionService(summaryService, actionExportStore, actionConnectors, cfg.Actions.LinkURL),
+		BatchService:   batchService,
+		LiveService:    liveService,
+		WebhookService: webhookService,
+		FeedbackSink:   feedbackSink,
+		RemoteFetcher: remote.NewFetcher(remote.Options{
+			MaxBytes:     int64(cfg.Remote.MaxDownloadMB) << 20,
+			Timeout:      cfg.Remote.Timeout,
+			AllowPrivate: cfg.Remote.AllowPrivate,
+		}),
+		Providers:       providers,
+		UsageService:    usageService,
+		GlossaryService: glossaryService,
+	})
 
 	// --- Jalankan Server ---
 	serverAddr := fmt.Sprintf(":%s", cfg.Port)
@@ -76,4 +249,154 @@ func main() {
 	if err := r.Run(serverAddr); err != nil {
 		log.Fatalf("Gagal menjalankan server: %v", err)
 	}
-}
\ No newline at end of file
+}
+
+// modelRoutes mengubah aturan routing model dari konfigurasi.
+func modelRoutes(cfg *config.Config) []services.ModelRoute {
+	routes := make([]services.ModelRoute, 0, len(cfg.Gemini.Routes))
+	for _, r := range cfg.Gemini.Routes {
+		routes = append(routes, services.ModelRoute(r))
+	}
+	return routes
+}
+
+// priceTable mengubah tabel harga pemakaian dari konfigurasi.
+func priceTable(cfg *config.Config) services.PriceTable {
+	prices := services.PriceTable{
+		Currency:        cfg.Usage.Currency,
+		SpeechPerMinute: cfg.Usage.SpeechPerMinute,
+		StoragePerGB:    cfg.Usage.StoragePerGB,
+	}
+	for _, m := range cfg.Usage.Models {
+		prices.Models = append(prices.Models, services.ModelPrice(m))
+	}
+	return prices
+}
+
+// mustOpenCollection membuka collection di direktori data, atau menghentikan
+// proses jika gagal (mis. file rusak atau direktori tidak bisa ditulis).
+func mustOpenCollection[T any](cfg *config.Config, name string) *store.Collection[T] {
+	c, err := store.OpenCollection[T](cfg.Storage.DataDir, name)
+	if err != nil {
+		log.Fatalf("Gagal membuka penyimpanan %s: %v", name, err)
+	}
+	return c
+}
+
+// newAuthenticator membuat Authenticator sesuai cfg.Auth.Provider.
+func newAuthenticator(ctx context.Context, cfg *config.Config, healthChecker *health.Checker) (auth.Authenticator, error) {
+	switch cfg.Auth.Provider {
+	case config.AuthProviderOIDC:
+		return auth.NewOIDCAuthenticator(ctx, auth.OIDCOptions{
+			Issuer:     cfg.Auth.OIDC.Issuer,
+			Audience:   cfg.Auth.OIDC.Audience,
+			JWKSURL:    cfg.Auth.OIDC.JWKSURL,
+			JWKSFile:   cfg.Auth.OIDC.JWKSFile,
+			UIDClaim:   cfg.Auth.OIDC.UIDClaim,
+			EmailClaim: cfg.Auth.OIDC.EmailClaim,
+		})
+	case config.AuthProviderStatic:
+		tokens := make([]auth.StaticToken, 0, len(cfg.Auth.StaticTokens))
+		for _, t := range cfg.Auth.StaticTokens {
+			tokens = append(tokens, auth.StaticToken{Token: t.Token, UID: t.UID, Email: t.Email})
+		}
+		return auth.NewStaticAuthenticator(tokens), nil
+	default:
+		authClient, err := platform.InitFirebaseAuth(ctx, cfg.FirebaseProjectID)
+		if err != nil {
+			return nil, err
+		}
+		healthChecker.Register("firebaseAuth", health.FirebaseAuthProbe(authClient))
+		return auth.NewFirebaseAuthenticator(authClient), nil
+	}
+}
+
+// newEmbedder memilih embedder pencarian semantik; nil jika dimatikan.
+func newEmbedder(cfg *config.Config, geminiClient *genai.Client) embedding.Embedder {
+	switch cfg.Search.Embedder {
+	case config.EmbedderGemini:
+		return embedding.NewGeminiEmbedder(geminiClient, cfg.Search.Model)
+	case config.EmbedderHTTP:
+		return embedding.NewHTTPEmbedder(cfg.Search.URL, cfg.Search.Model, cfg.Search.APIKey, cfg.Search.Timeout)
+	default:
+		return nil
+	}
+}
+
+// newActionConnectors membuat connector action item dari konfigurasi.
+// Token boleh diambil dari env (tokenEnv) agar tidak tertulis di file YAML.
+func newActionConnectors(cfg *config.Config) ([]actions.Connector, error) {
+	connectors := make([]actions.Connector, 0, len(cfg.Actions.Connectors))
+	for _, cc := range cfg.Actions.Connectors {
+		token := cc.Token
+		if token == "" && cc.TokenEnv != "" {
+			token = os.Getenv(cc.TokenEnv)
+		}
+		conn, err := actions.NewConnector(actions.Options{
+			Name:      cc.Name,
+			Type:      cc.Type,
+			URL:       cc.URL,
+			Project:   cc.Project,
+			IssueType: cc.IssueType,
+			Username:  cc.Username,
+			Token:     token,
+			Secret:    cc.Secret,
+			Labels:    cc.Labels,
+			Fields:    cc.Fields,
+			Assignees: cc.Assignees,
+			Timeout:   cc.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		connectors = append(connectors, conn)
+	}
+	return connectors, nil
+}
+
+// newFeedbackSink membuat fan-out ke semua sink di cfg.Feedback.Sinks.
+// Client eksternal dibuat sekali di sini dan ditutup lewat fungsi close.
+func newFeedbackSink(ctx context.Context, cfg *config.Config) (feedback.Sink, func(), error) {
+	var sinks []feedback.Sink
+	var closers []func()
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+
+	for _, name := range cfg.Feedback.Sinks {
+		switch name {
+		case config.FeedbackSinkSheets:
+			sink, err := feedback.NewSheetsSink(ctx, cfg.Feedback.SheetID, cfg.Feedback.SheetRange)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			sinks = append(sinks, sink)
+		case config.FeedbackSinkFirestore:
+			client, err := firestore.NewClient(ctx, cfg.FirebaseProjectID)
+			if err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("gagal membuat Firestore client: %w", err)
+			}
+			closers = append(closers, func() { client.Close() })
+			sinks = append(sinks, feedback.NewFirestoreSink(client, cfg.Feedback.FirestoreCollection))
+		case config.FeedbackSinkNDJSON:
+			path := cfg.Feedback.NDJSONPath
+			if path == "" {
+				path = filepath.Join(cfg.Storage.DataDir, "feedback.ndjson")
+			}
+			sink, err := feedback.NewNDJSONSink(path)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			sinks = append(sinks, sink)
+		case config.FeedbackSinkWebhook:
+			sinks = append(sinks, feedback.NewWebhookSink(cfg.Feedback.WebhookURL))
+		}
+	}
+	log.Printf("Feedback sink aktif: %v", cfg.Feedback.Sinks)
+	return feedback.NewMultiSink(sinks...), closeAll, nil
+}
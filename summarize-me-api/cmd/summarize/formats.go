@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"summarize-me-api/internal/services"
+)
+
+// Format output yang didukung.
+const (
+	formatTxt        = "txt"
+	formatMD         = "md"
+	formatJSON       = "json"
+	formatTranscript = "transcript"
+)
+
+var allFormats = []string{formatTxt, formatMD, formatJSON, formatTranscript}
+
+// suffixes menentukan nama file output per format, ditempel ke nama input
+// tanpa ekstensi. Mis. rapat.mp3 -> rapat.summary.md.
+var suffixes = map[string]string{
+	formatTxt:        ".summary.txt",
+	formatMD:         ".summary.md",
+	formatJSON:       ".summary.json",
+	formatTranscript: ".transcript.txt",
+}
+
+func parseFormats(raw string) ([]string, error) {
+	var out []string
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(strings.ToLower(f))
+		if f == "" {
+			continue
+		}
+		if _, ok := suffixes[f]; !ok {
+			return nil, fmt.Errorf("format tidak dikenal: %q (pilihan: %s)", f, strings.Join(allFormats, ", "))
+		}
+		out = append(out, f)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("-formats minimal berisi satu format")
+	}
+	return out, nil
+}
+
+// outputPaths mengembalikan path output untuk setiap format yang dipilih.
+// Dengan -out, subdirektori input relatif terhadap argumennya dipertahankan
+// agar a/rapat.mp3 dan b/rapat.mp3 tidak saling menimpa.
+func outputPaths(in input, opts options) map[string]string {
+	dir := filepath.Dir(in.Path)
+	if opts.OutDir != "" {
+		dir = filepath.Join(opts.OutDir, filepath.Dir(in.Rel))
+	}
+	base := filepath.Base(in.Path)
+	if !in.KeepExt {
+		base = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+	paths := make(map[string]string, len(opts.Formats))
+	for _, f := range opts.Formats {
+		paths[f] = filepath.Join(dir, base+suffixes[f])
+	}
+	return paths
+}
+
+// isGeneratedOutput mengecek apakah file adalah output CLI dari run sebelumnya.
+func isGeneratedOutput(path string) bool {
+	name := strings.ToLower(filepath.Base(path))
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func render(format, source string, result *services.SummarizeResult) ([]byte, error) {
+	switch format {
+	case formatTxt:
+		return []byte(result.Summary + "\n"), nil
+	case formatTranscript:
+		return []byte(result.Transcript + "\n"), nil
+	case formatMD:
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Ringkasan: %s\n\n", source)
+		b.WriteString(strings.TrimSpace(result.Summary))
+		b.WriteString("\n\n## Transkrip\n\n")
+		b.WriteString(strings.TrimSpace(result.Transcript))
+		b.WriteString("\n")
+		return []byte(b.String()), nil
+	case formatJSON:
+		data, err := json.MarshalIndent(struct {
+			Source string `json:"source"`
+			*services.SummarizeResult
+		}{source, result}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("format tidak dikenal: %q", format)
+	}
+}
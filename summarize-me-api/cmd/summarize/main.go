@@ -0,0 +1,159 @@
+// Command summarize mentranskrip dan meringkas file audio/video lokal (atau
+// meringkas transkrip VTT/SRT/DOCX/TXT) tanpa melewati server HTTP. Hasil
+// ditulis di samping file input (atau di -out, dengan struktur subdirektori
+// yang sama), dan file yang semua output-nya sudah ada dilewati saat
+// dijalankan ulang.
+//
+// Contoh:
+//
+//	summarize -formats md,json -concurrency 3 -recursive ./wawancara
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"summarize-me-api/internal/config"
+	"summarize-me-api/internal/media"
+	"summarize-me-api/internal/platform"
+	"summarize-me-api/internal/resilience"
+	"summarize-me-api/internal/services"
+	"syscall"
+
+	"cloud.google.com/go/storage"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+		log.Printf("Peringatan: Tidak dapat memuat file .env: %v", err)
+	}
+
+	fs := flag.NewFlagSet("summarize", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Pemakaian: summarize [flag] <file atau direktori>...\n\n")
+		fs.PrintDefaults()
+	}
+	formats := fs.String("formats", "txt,md", "format output, dipisah koma: "+strings.Join(allFormats, ", "))
+	outDir := fs.String("out", "", "direktori output; kosong berarti di samping file input")
+	concurrency := fs.Int("concurrency", 2, "jumlah file yang diproses bersamaan")
+	recursive := fs.Bool("recursive", false, "telusuri subdirektori")
+	force := fs.Bool("force", false, "proses ulang walaupun output sudah ada")
+
+	cfg, err := config.Load(fs, os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Konfigurasi tidak valid:\n%v", err)
+	}
+	if err := cfg.ValidateSummarizer(); err != nil {
+		log.Fatalf("Konfigurasi tidak valid:\n%v", err)
+	}
+
+	opts := options{OutDir: *outDir, Concurrency: *concurrency, Force: *force}
+	if opts.Formats, err = parseFormats(*formats); err != nil {
+		log.Fatal(err)
+	}
+	if opts.Concurrency < 1 {
+		log.Fatal("-concurrency minimal 1")
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	inputs, err := collectInputs(fs.Args(), *recursive)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		log.Fatal("Tidak ada file audio, video atau transkrip yang ditemukan")
+	}
+	resolveCollisions(inputs, opts)
+
+	todo, skipped := pending(inputs, opts)
+	if len(todo) == 0 {
+		log.Printf("Semua %d file sudah diproses, gunakan -force untuk mengulang", skipped)
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	service, closeClients, err := newSummarizeService(ctx, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer closeClients()
+
+	report := run(ctx, service, todo, opts)
+	report.Skipped = skipped
+	log.Printf("Selesai: %d diproses, %d dilewati, %d gagal", report.Done, report.Skipped, report.Failed)
+	if report.Failed > 0 {
+		closeClients()
+		os.Exit(1)
+	}
+}
+
+// newSummarizeService menyiapkan klien Speech, Gemini dan GCS seperti server.
+func newSummarizeService(ctx context.Context, cfg *config.Config) (*services.SummarizeService, func(), error) {
+	speechClient, err := platform.InitSpeechClient(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal inisialisasi Speech Client: %w", err)
+	}
+	geminiClient, err := platform.InitGeminiClient(ctx, cfg.GeminiAPIKey)
+	if err != nil {
+		speechClient.Close()
+		return nil, nil, fmt.Errorf("gagal inisialisasi Gemini Client: %w", err)
+	}
+	storageClient, err := storage.NewClient(ctx)
+	if err != nil {
+		speechClient.Close()
+		geminiClient.Close()
+		return nil, nil, fmt.Errorf("gagal inisialisasi Storage Client: %w", err)
+	}
+
+	service := services.NewSummarizeService(
+		speechClient,
+		geminiClient.GenerativeModel(cfg.Gemini.Model),
+		cfg.Gemini.Model,
+		storageClient,
+		cfg.GCSBucketName,
+		cfg.Speech.LanguageCode,
+		int32(cfg.Speech.SampleRateHertz),
+		media.NewTranscoder(media.Options{
+			FFmpegPath:       cfg.Media.FFmpegPath,
+			FFprobePath:      cfg.Media.FFprobePath,
+			SampleRateHertz:  cfg.Speech.SampleRateHertz,
+			Keyframes:        cfg.Media.Keyframes,
+			KeyframeInterval: cfg.Media.KeyframeInterval,
+		}),
+	)
+	closeClients := func() {
+		speechClient.Close()
+		geminiClient.Close()
+		storageClient.Close()
+	}
+	routes := make([]services.ModelRoute, 0, len(cfg.Gemini.Routes))
+	for _, r := range cfg.Gemini.Routes {
+		routes = append(routes, services.ModelRoute(r))
+	}
+	if err := service.UseModelChain(geminiClient, cfg.Gemini.Fallbacks, routes); err != nil {
+		closeClients()
+		return nil, nil, fmt.Errorf("konfigurasi routing model tidak valid: %w", err)
+	}
+	providers := resilience.NewRegistry(resilience.BreakerOptions{Threshold: cfg.Resilience.BreakerThreshold, Cooldown: cfg.Resilience.BreakerCooldown})
+	geminiPolicy := resilience.Policy(cfg.Resilience.Gemini)
+	service.UseResilience(
+		func(model string) *resilience.Executor { return providers.Executor("gemini/"+model, geminiPolicy) },
+		providers.Executor("speech", resilience.Policy(cfg.Resilience.Speech)),
+		providers.Executor("storage", resilience.Policy(cfg.Resilience.Storage)),
+	)
+	return service, closeClients, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"summarize-me-api/internal/services"
+	"sync"
+)
+
+// options adalah pengaturan satu kali jalan CLI.
+type options struct {
+	Formats     []string
+	OutDir      string
+	Concurrency int
+	Force       bool
+}
+
+// report menghitung hasil pemrosesan semua file.
+type report struct {
+	Done    int
+	Skipped int
+	Failed  int
+}
+
+// input adalah satu file yang akan diproses.
+type input struct {
+	Path string
+	// Rel adalah path relatif terhadap direktori argumen asalnya, dipakai
+	// untuk mempertahankan struktur subdirektori di bawah -out.
+	Rel string
+	// KeepExt menyertakan ekstensi input di nama output, diisi
+	// resolveCollisions jika nama tanpa ekstensi bentrok (rapat.mp3 dan
+	// rapat.txt di direktori yang sama).
+	KeepExt bool
+}
+
+// collectInputs mengubah argumen file/direktori menjadi daftar file audio.
+// File yang disebut langsung selalu diproses; isi direktori disaring
+// berdasarkan ekstensi audio, dan output CLI sendiri (*.summary.txt,
+// *.transcript.txt) dilewati agar tidak diringkas ulang.
+func collectInputs(args []string, recursive bool) ([]input, error) {
+	var inputs []input
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			inputs = append(inputs, input{Path: arg, Rel: filepath.Base(arg)})
+			continue
+		}
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != arg && !recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if services.IsSupportedFile(path) && !isGeneratedOutput(path) {
+				rel, err := filepath.Rel(arg, path)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input{Path: path, Rel: rel})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Path < inputs[j].Path })
+	return inputs, nil
+}
+
+// resolveCollisions menandai input yang nama output-nya bentrok dengan input
+// lain, agar ekstensinya ikut disertakan di nama output.
+func resolveCollisions(inputs []input, opts options) {
+	groups := make(map[string][]int)
+	for i, in := range inputs {
+		for _, path := range outputPaths(in, opts) {
+			groups[path] = append(groups[path], i)
+			break
+		}
+	}
+	for _, idx := range groups {
+		if len(idx) < 2 {
+			continue
+		}
+		for _, i := range idx {
+			inputs[i].KeepExt = true
+		}
+	}
+}
+
+// pending memisahkan input yang masih perlu diproses dari yang semua
+// output-nya sudah ada (hasil run sebelumnya).
+func pending(inputs []input, opts options) (todo []input, skipped int) {
+	for _, in := range inputs {
+		if !opts.Force && allExist(outputPaths(in, opts)) {
+			log.Printf("Lewati %s (output sudah ada)", in.Path)
+			skipped++
+			continue
+		}
+		todo = append(todo, in)
+	}
+	return todo, skipped
+}
+
+// run memproses semua input dengan paling banyak opts.Concurrency file
+// sekaligus. Pembatalan ctx menghentikan file yang belum dimulai.
+func run(ctx context.Context, service *services.SummarizeService, inputs []input, opts options) report {
+	var (
+		mu  sync.Mutex
+		rep report
+		wg  sync.WaitGroup
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+
+	for _, in := range inputs {
+		input, outputs := in.Path, outputPaths(in, opts)
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			log.Printf("Dibatalkan, %s tidak diproses", input)
+			rep.Failed++
+			continue
+		}
+		wg.Add(1)
+		go func(input string, outputs map[string]string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := processFile(ctx, service, input, outputs)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("ERROR: %s: %v", input, err)
+				rep.Failed++
+				return
+			}
+			log.Printf("OK: %s", input)
+			rep.Done++
+		}(input, outputs)
+	}
+	wg.Wait()
+	return rep
+}
+
+func processFile(ctx context.Context, service *services.SummarizeService, input string, outputs map[string]string) error {
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+	log.Printf("Memproses %s (%d bytes)", input, len(data))
+	result, err := service.TranscribeAndSummarize(ctx, data, filepath.Base(input))
+	if err != nil {
+		return err
+	}
+
+	for format, path := range outputs {
+		content, err := render(format, filepath.Base(input), result)
+		if err != nil {
+			return err
+		}
+		if err := writeAtomic(path, content); err != nil {
+			return fmt.Errorf("gagal menulis %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func allExist(paths map[string]string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// writeAtomic menulis ke file sementara lalu me-rename, agar file output
+// yang terpotong (mis. proses dihentikan) tidak dianggap selesai.
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".summarize-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,268 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Jenis connector yang didukung.
+const (
+	TypeGitHub  = "github"
+	TypeJira    = "jira"
+	TypeWebhook = "webhook"
+)
+
+// Ref menunjuk ke issue atau task yang dibuat di sistem tujuan.
+type Ref struct {
+	ExternalID string `json:"externalId"`
+	URL        string `json:"url,omitempty"`
+}
+
+// Connector mengirim satu action item ke sistem eksternal.
+type Connector interface {
+	Name() string
+	Type() string
+	Push(ctx context.Context, item Item, meeting Meeting) (Ref, error)
+}
+
+// Options adalah konfigurasi satu connector.
+type Options struct {
+	Name string
+	Type string
+	// URL adalah base URL API (GitHub Enterprise, Jira) atau URL tujuan webhook.
+	URL string
+	// Project adalah "owner/repo" untuk GitHub atau project key untuk Jira.
+	Project   string
+	IssueType string
+	// Username diisi untuk Jira Cloud (email + API token, Basic auth).
+	// Kosong berarti Token dikirim sebagai Bearer.
+	Username string
+	Token    string
+	// Secret menandatangani payload webhook, sama seperti webhook job.
+	Secret string
+	Labels []string
+	// Fields adalah template text/template per field tujuan.
+	Fields map[string]string
+	// Assignees memetakan nama di ringkasan ke akun di sistem tujuan.
+	Assignees map[string]string
+	Timeout   time.Duration
+}
+
+// TemplateData adalah data yang tersedia untuk template Fields, mis.
+// "{{.Title}}", "{{.Assignee}}", "{{.Meeting.FileName}}", "{{.Meeting.Date}}".
+type TemplateData struct {
+	Index       int
+	Title       string
+	Description string
+	// Assignee adalah akun tujuan hasil pemetaan; Speaker nama aslinya.
+	Assignee string
+	Speaker  string
+	Due      string
+	Meeting  TemplateMeeting
+}
+
+// TemplateMeeting adalah info rapat untuk template, tanggal sebagai YYYY-MM-DD.
+type TemplateMeeting struct {
+	SummaryID string
+	FileName  string
+	Date      string
+	URL       string
+}
+
+// Template bawaan per jenis connector jika Fields tidak mengisinya.
+var defaultFields = map[string]map[string]string{
+	TypeGitHub: {
+		"title": "{{.Title}}",
+		"body":  defaultBody,
+	},
+	TypeJira: {
+		"summary":     "{{.Title}}",
+		"description": defaultBody,
+	},
+	TypeWebhook: {},
+}
+
+const defaultBody = `{{if .Description}}{{.Description}}
+
+{{end}}{{if .Speaker}}Penanggung jawab: {{.Speaker}}
+{{end}}{{if .Due}}Tenggat: {{.Due}}
+{{end}}Dari rapat: {{.Meeting.FileName}} ({{.Meeting.Date}}){{if .Meeting.URL}}
+{{.Meeting.URL}}{{end}}`
+
+// NewConnector membuat connector sesuai opts.Type dan mem-parse template
+// Fields sekaligus agar kesalahan konfigurasi ketahuan saat startup.
+func NewConnector(opts Options) (Connector, error) {
+	if opts.Name == "" {
+		return nil, errors.New("nama connector wajib diisi")
+	}
+	defaults, ok := defaultFields[opts.Type]
+	if !ok {
+		return nil, fmt.Errorf("connector %s: tipe tidak dikenal: %q", opts.Name, opts.Type)
+	}
+	m, err := newMapper(opts, defaults)
+	if err != nil {
+		return nil, fmt.Errorf("connector %s: %w", opts.Name, err)
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 15 * time.Second
+	}
+	base := baseConnector{opts: opts, mapper: m, client: &http.Client{Timeout: opts.Timeout}}
+
+	switch opts.Type {
+	case TypeGitHub:
+		if !strings.Contains(opts.Project, "/") || opts.Token == "" {
+			return nil, fmt.Errorf("connector %s: project (owner/repo) dan token wajib diisi untuk github", opts.Name)
+		}
+		if base.opts.URL == "" {
+			base.opts.URL = "https://api.github.com"
+		}
+		return &gitHubConnector{baseConnector: base}, nil
+	case TypeJira:
+		if opts.URL == "" || opts.Project == "" || opts.Token == "" {
+			return nil, fmt.Errorf("connector %s: url, project dan token wajib diisi untuk jira", opts.Name)
+		}
+		if base.opts.IssueType == "" {
+			base.opts.IssueType = "Task"
+		}
+		return &jiraConnector{baseConnector: base}, nil
+	default:
+		if opts.URL == "" {
+			return nil, fmt.Errorf("connector %s: url wajib diisi untuk webhook", opts.Name)
+		}
+		return &webhookConnector{baseConnector: base}, nil
+	}
+}
+
+type baseConnector struct {
+	opts   Options
+	mapper *mapper
+	client *http.Client
+}
+
+func (c *baseConnector) Name() string { return c.opts.Name }
+func (c *baseConnector) Type() string { return c.opts.Type }
+
+// doJSON mengirim body sebagai JSON dan men-decode respons 2xx ke out
+// (boleh nil). setHeaders (boleh nil) menambah header seperti auth atau
+// tanda tangan atas payload final. Respons non-2xx menjadi error berisi
+// potongan body.
+func (c *baseConnector) doJSON(ctx context.Context, method, url string, body any, setHeaders func(h http.Header, payload []byte), out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("gagal membuat request ke %s: %w", c.opts.Name, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "SummarizeMe-Actions/1")
+	if setHeaders != nil {
+		setHeaders(req.Header, payload)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("gagal memanggil %s: %w", c.opts.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s membalas %s: %s", c.opts.Name, resp.Status, bytes.TrimSpace(msg))
+	}
+	if out == nil {
+		return nil
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil || len(bytes.TrimSpace(raw)) == 0 {
+		return err
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("respons %s tidak valid: %w", c.opts.Name, err)
+	}
+	return nil
+}
+
+// mapper merender template Fields untuk satu item.
+type mapper struct {
+	fields    map[string]*template.Template
+	assignees map[string]string
+}
+
+func newMapper(opts Options, defaults map[string]string) (*mapper, error) {
+	m := &mapper{fields: make(map[string]*template.Template), assignees: make(map[string]string)}
+	for name, text := range defaults {
+		if _, ok := opts.Fields[name]; !ok {
+			opts.Fields = withField(opts.Fields, name, text)
+		}
+	}
+	for name, text := range opts.Fields {
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("template field %q tidak valid: %w", name, err)
+		}
+		// Eksekusi dengan data kosong agar field yang salah ketik
+		// (mis. {{.Judul}}) juga ketahuan saat startup.
+		if err := tmpl.Execute(io.Discard, TemplateData{}); err != nil {
+			return nil, fmt.Errorf("template field %q tidak valid: %w", name, err)
+		}
+		m.fields[name] = tmpl
+	}
+	for speaker, account := range opts.Assignees {
+		m.assignees[strings.ToLower(strings.TrimSpace(speaker))] = account
+	}
+	return m, nil
+}
+
+func withField(fields map[string]string, name, text string) map[string]string {
+	out := make(map[string]string, len(fields)+1)
+	for k, v := range fields {
+		out[k] = v
+	}
+	out[name] = text
+	return out
+}
+
+// data menyiapkan TemplateData untuk item. Assignee kosong jika nama di
+// ringkasan tidak ada di pemetaan Assignees.
+func (m *mapper) data(item Item, meeting Meeting) TemplateData {
+	d := TemplateData{
+		Index:       item.Index,
+		Title:       item.Title,
+		Description: item.Description,
+		Assignee:    m.assignees[strings.ToLower(item.Assignee)],
+		Speaker:     item.Assignee,
+		Due:         item.Due,
+		Meeting: TemplateMeeting{
+			SummaryID: meeting.SummaryID,
+			FileName:  meeting.FileName,
+			URL:       meeting.URL,
+		},
+	}
+	if !meeting.Date.IsZero() {
+		d.Meeting.Date = meeting.Date.Format(dateLayout)
+	}
+	return d
+}
+
+// render mengembalikan semua field hasil template, tanpa spasi di tepi.
+func (m *mapper) render(data TemplateData) (map[string]string, error) {
+	out := make(map[string]string, len(m.fields))
+	for name, tmpl := range m.fields {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("gagal merender field %q: %w", name, err)
+		}
+		out[name] = strings.TrimSpace(b.String())
+	}
+	return out, nil
+}
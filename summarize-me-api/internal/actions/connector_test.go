@@ -0,0 +1,288 @@
+package actions
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testMeeting = Meeting{
+	SummaryID: "sum-1",
+	FileName:  "rapat-mingguan.mp3",
+	Date:      time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC),
+	URL:       "https://app.example.com/summaries/sum-1",
+}
+
+var testItem = Item{
+	Index:       2,
+	Title:       "Kirim draf proposal",
+	Description: "Sertakan anggaran",
+	Assignee:    "Budi",
+	Due:         "2024-05-10",
+}
+
+// captured adalah request yang diterima server uji.
+type captured struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// newServer menjalankan server uji yang mencatat request lalu membalas
+// dengan status dan body yang diberikan.
+func newServer(t *testing.T, status int, response string) (*httptest.Server, *captured) {
+	t.Helper()
+	got := &captured{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*got = captured{method: r.Method, path: r.URL.Path, header: r.Header.Clone(), body: body}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func mustConnector(t *testing.T, opts Options) Connector {
+	t.Helper()
+	conn, err := NewConnector(opts)
+	if err != nil {
+		t.Fatalf("NewConnector: %v", err)
+	}
+	return conn
+}
+
+func decode(t *testing.T, body []byte) map[string]any {
+	t.Helper()
+	var out map[string]any
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("payload bukan JSON: %v\n%s", err, body)
+	}
+	return out
+}
+
+func TestGitHubConnectorPush(t *testing.T) {
+	srv, got := newServer(t, http.StatusCreated, `{"number":42,"html_url":"https://github.com/acme/rapat/issues/42"}`)
+	conn := mustConnector(t, Options{
+		Name:      "github",
+		Type:      TypeGitHub,
+		URL:       srv.URL,
+		Project:   "acme/rapat",
+		Token:     "gh-token",
+		Labels:    []string{"rapat"},
+		Assignees: map[string]string{"budi": "budi-gh"},
+	})
+
+	ref, err := conn.Push(context.Background(), testItem, testMeeting)
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if want := (Ref{ExternalID: "acme/rapat#42", URL: "https://github.com/acme/rapat/issues/42"}); ref != want {
+		t.Errorf("ref = %+v, want %+v", ref, want)
+	}
+	if got.method != http.MethodPost || got.path != "/repos/acme/rapat/issues" {
+		t.Errorf("request = %s %s", got.method, got.path)
+	}
+	if h := got.header.Get("Authorization"); h != "Bearer gh-token" {
+		t.Errorf("Authorization = %q", h)
+	}
+	if h := got.header.Get("Accept"); h != "application/vnd.github+json" {
+		t.Errorf("Accept = %q", h)
+	}
+
+	var issue gitHubIssue
+	if err := json.Unmarshal(got.body, &issue); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if issue.Title != "Kirim draf proposal" {
+		t.Errorf("title = %q", issue.Title)
+	}
+	for _, want := range []string{"Sertakan anggaran", "Penanggung jawab: Budi", "Tenggat: 2024-05-10", "Dari rapat: rapat-mingguan.mp3 (2024-05-06)", testMeeting.URL} {
+		if !strings.Contains(issue.Body, want) {
+			t.Errorf("body tidak berisi %q:\n%s", want, issue.Body)
+		}
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0] != "rapat" {
+		t.Errorf("labels = %v", issue.Labels)
+	}
+	if len(issue.Assignees) != 1 || issue.Assignees[0] != "budi-gh" {
+		t.Errorf("assignees = %v", issue.Assignees)
+	}
+}
+
+func TestJiraConnectorPush(t *testing.T) {
+	tests := []struct {
+		name       string
+		username   string
+		assignee   string
+		wantAuth   string
+		wantPerson map[string]any
+	}{
+		{
+			name:       "cloud",
+			username:   "pm@acme.id",
+			assignee:   "5b10ac8d82e05b22cc7d4ef5",
+			wantAuth:   "Basic " + base64.StdEncoding.EncodeToString([]byte("pm@acme.id:jira-token")),
+			wantPerson: map[string]any{"accountId": "5b10ac8d82e05b22cc7d4ef5"},
+		},
+		{
+			name:       "server",
+			assignee:   "name:budi",
+			wantAuth:   "Bearer jira-token",
+			wantPerson: map[string]any{"name": "budi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, got := newServer(t, http.StatusCreated, `{"id":"10001","key":"OPS-7"}`)
+			conn := mustConnector(t, Options{
+				Name:      "jira",
+				Type:      TypeJira,
+				URL:       srv.URL + "/",
+				Project:   "OPS",
+				Username:  tt.username,
+				Token:     "jira-token",
+				Fields:    map[string]string{"summary": "[Rapat] {{.Title}}", "customfield_10010": "{{.Meeting.URL}}"},
+				Assignees: map[string]string{"Budi": tt.assignee},
+			})
+
+			ref, err := conn.Push(context.Background(), testItem, testMeeting)
+			if err != nil {
+				t.Fatalf("Push: %v", err)
+			}
+			if want := (Ref{ExternalID: "OPS-7", URL: srv.URL + "/browse/OPS-7"}); ref != want {
+				t.Errorf("ref = %+v, want %+v", ref, want)
+			}
+			if got.path != "/rest/api/2/issue" {
+				t.Errorf("path = %s", got.path)
+			}
+			if h := got.header.Get("Authorization"); h != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", h, tt.wantAuth)
+			}
+
+			fields, _ := decode(t, got.body)["fields"].(map[string]any)
+			checks := map[string]any{
+				"summary":           "[Rapat] Kirim draf proposal",
+				"customfield_10010": testMeeting.URL,
+				"duedate":           "2024-05-10",
+			}
+			for key, want := range checks {
+				if fields[key] != want {
+					t.Errorf("fields[%s] = %v, want %v", key, fields[key], want)
+				}
+			}
+			if p, _ := fields["project"].(map[string]any); p["key"] != "OPS" {
+				t.Errorf("project = %v", fields["project"])
+			}
+			if it, _ := fields["issuetype"].(map[string]any); it["name"] != "Task" {
+				t.Errorf("issuetype = %v", fields["issuetype"])
+			}
+			person, _ := fields["assignee"].(map[string]any)
+			if len(person) != len(tt.wantPerson) {
+				t.Fatalf("assignee = %v, want %v", person, tt.wantPerson)
+			}
+			for k, v := range tt.wantPerson {
+				if person[k] != v {
+					t.Errorf("assignee = %v, want %v", person, tt.wantPerson)
+				}
+			}
+		})
+	}
+}
+
+func TestJiraConnectorRequiresKey(t *testing.T) {
+	srv, _ := newServer(t, http.StatusCreated, `{}`)
+	conn := mustConnector(t, Options{Name: "jira", Type: TypeJira, URL: srv.URL, Project: "OPS", Token: "t"})
+	if _, err := conn.Push(context.Background(), testItem, testMeeting); err == nil {
+		t.Fatal("Push tanpa key issue seharusnya error")
+	}
+}
+
+func TestWebhookConnectorPush(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK, `{"id":123,"url":"https://n8n.example.com/tasks/123"}`)
+	conn := mustConnector(t, Options{
+		Name:   "n8n",
+		Type:   TypeWebhook,
+		URL:    srv.URL + "/hook",
+		Token:  "hook-token",
+		Secret: "rahasia",
+	})
+
+	ref, err := conn.Push(context.Background(), testItem, testMeeting)
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if want := (Ref{ExternalID: "123", URL: "https://n8n.example.com/tasks/123"}); ref != want {
+		t.Errorf("ref = %+v, want %+v", ref, want)
+	}
+	if got.path != "/hook" {
+		t.Errorf("path = %s", got.path)
+	}
+	if h := got.header.Get("Authorization"); h != "Bearer hook-token" {
+		t.Errorf("Authorization = %q", h)
+	}
+	if h := got.header.Get(EventHeader); h != EventActionItem {
+		t.Errorf("%s = %q", EventHeader, h)
+	}
+
+	// Tanda tangan harus cocok dengan body yang benar-benar dikirim.
+	sig := got.header.Get(SignatureHeader)
+	ts, mac, ok := strings.Cut(strings.TrimPrefix(sig, "t="), ",v1=")
+	if !ok {
+		t.Fatalf("%s = %q", SignatureHeader, sig)
+	}
+	if _, err := strconv.ParseInt(ts, 10, 64); err != nil {
+		t.Errorf("timestamp tidak valid: %q", ts)
+	}
+	if want := sign("rahasia", ts, got.body); mac != want {
+		t.Errorf("signature = %s, want %s", mac, want)
+	}
+
+	var payload webhookPayload
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Event != EventActionItem || payload.Item != testItem || payload.Meeting.SummaryID != "sum-1" {
+		t.Errorf("payload = %+v", payload)
+	}
+	if payload.Fields != nil {
+		t.Errorf("fields = %v, want kosong tanpa template", payload.Fields)
+	}
+}
+
+func TestConnectorErrorResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"github", Options{Name: "github", Type: TypeGitHub, Project: "acme/rapat", Token: "t"}},
+		{"jira", Options{Name: "jira", Type: TypeJira, Project: "OPS", Token: "t"}},
+		{"webhook", Options{Name: "n8n", Type: TypeWebhook}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newServer(t, http.StatusUnprocessableEntity, `{"message":"Validation Failed"}`)
+			tt.opts.URL = srv.URL
+			conn := mustConnector(t, tt.opts)
+
+			_, err := conn.Push(context.Background(), testItem, testMeeting)
+			if err == nil {
+				t.Fatal("Push seharusnya error untuk respons 422")
+			}
+			for _, want := range []string{tt.opts.Name, "422", "Validation Failed"} {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q tidak berisi %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package actions mengambil action item dari ringkasan Markdown lalu
+// mengekspornya sebagai file kalender (ICS) atau mengirimnya ke issue
+// tracker lewat connector (GitHub Issues, Jira, webhook generik).
+package actions
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Item adalah satu action item hasil ekstraksi. Index dimulai dari 1 dan
+// stabil selama teks ringkasan tidak berubah.
+type Item struct {
+	Index       int    `json:"index"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Assignee adalah nama yang disebut di ringkasan, mis. "Pembicara 1".
+	Assignee string `json:"assignee,omitempty"`
+	// Due adalah tenggat dalam format YYYY-MM-DD jika bisa dikenali.
+	Due  string `json:"due,omitempty"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// DueDate mengembalikan tenggat sebagai time.Time (UTC, tengah malam).
+func (i Item) DueDate() (time.Time, bool) {
+	if i.Due == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(dateLayout, i.Due)
+	return t, err == nil
+}
+
+const dateLayout = "2006-01-02"
+
+var (
+	headingPattern  = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.+?)\s*#*\s*$`)
+	boldHeadPattern = regexp.MustCompile(`^\s*\*\*([^*]+)\*\*\s*:?\s*$`)
+	listPattern     = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(?:\[([ xX])\]\s+)?(.+)$`)
+	bracketAssignee = regexp.MustCompile(`\[([^\]]{1,40})\]`)
+	labelAssignee   = regexp.MustCompile(`(?i)\(?\b(?:pic|penanggung\s*jawab|assignee|owner|oleh)\s*:\s*([^,;()\n]+?)\s*(?:\)|[,;]|$)`)
+	leadingAssignee = regexp.MustCompile(`^([\p{L}][\p{L}\d .'-]{0,38}?)\s*:\s+(.+)$`)
+	dueLabel        = regexp.MustCompile(`(?i)\(?\b(?:tenggat(?:\s*waktu)?|deadline|due(?:\s*date)?|batas\s*waktu|target)\s*:?\s*([^,;()\n]+?)\s*(?:\)|[,;]|$)`)
+	isoDate         = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	slashDate       = regexp.MustCompile(`\b(\d{1,2})[/.](\d{1,2})[/.](\d{4})\b`)
+	wordDate        = regexp.MustCompile(`(?i)\b(\d{1,2})\s+([a-z]+)\.?\s+(\d{4})\b`)
+	emphasis        = strings.NewReplacer("**", "", "__", "", "`", "")
+)
+
+// actionHeadings adalah kata kunci judul bagian yang berisi action item.
+var actionHeadings = []string{"action item", "action-item", "tindak lanjut", "tindaklanjut", "rencana aksi", "to-do", "todo", "tugas"}
+
+var months = map[string]time.Month{
+	"januari": time.January, "jan": time.January, "january": time.January,
+	"februari": time.February, "feb": time.February, "pebruari": time.February, "february": time.February,
+	"maret": time.March, "mar": time.March, "march": time.March,
+	"april": time.April, "apr": time.April,
+	"mei": time.May, "may": time.May,
+	"juni": time.June, "jun": time.June, "june": time.June,
+	"juli": time.July, "jul": time.July, "july": time.July,
+	"agustus": time.August, "agu": time.August, "agt": time.August, "aug": time.August, "august": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"oktober": time.October, "okt": time.October, "oct": time.October, "october": time.October,
+	"november": time.November, "nov": time.November, "nop": time.November,
+	"desember": time.December, "des": time.December, "dec": time.December, "december": time.December,
+}
+
+// Extract mengambil action item dari bagian ringkasan yang berjudul
+// "Action Items", "Tindak Lanjut" dan sejenisnya. Setiap butir list menjadi
+// satu item; sub-butir yang lebih menjorok digabung ke Description.
+// Penanggung jawab dikenali dari "[Pembicara 1]", "PIC: Budi" atau awalan
+// "Budi: ...", dan tenggat dari "Tenggat: 31 Januari 2025" atau "Deadline: 2025-01-31".
+func Extract(markdown string) []Item {
+	var (
+		items      []Item
+		inSection  bool
+		baseIndent = -1
+	)
+	for _, line := range strings.Split(markdown, "\n") {
+		if heading, ok := headingText(line); ok {
+			inSection = isActionHeading(heading)
+			baseIndent = -1
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		m := listPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+		if baseIndent < 0 {
+			baseIndent = indent
+		}
+		text := strings.TrimSpace(m[3])
+		if indent > baseIndent && len(items) > 0 {
+			last := &items[len(items)-1]
+			if last.Description != "" {
+				last.Description += "\n"
+			}
+			last.Description += "- " + emphasis.Replace(text)
+			continue
+		}
+		item := parseItem(text)
+		if item.Title == "" {
+			continue
+		}
+		item.Index = len(items) + 1
+		item.Done = m[2] == "x" || m[2] == "X"
+		items = append(items, item)
+	}
+	return items
+}
+
+// headingText mengenali judul Markdown ("## Judul") maupun baris tebal yang
+// berdiri sendiri ("**Judul:**") yang sering dipakai model sebagai judul.
+func headingText(line string) (string, bool) {
+	if m := headingPattern.FindStringSubmatch(line); m != nil {
+		return emphasis.Replace(m[1]), true
+	}
+	if m := boldHeadPattern.FindStringSubmatch(line); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+func isActionHeading(heading string) bool {
+	h := strings.ToLower(heading)
+	for _, k := range actionHeadings {
+		if strings.Contains(h, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseItem memisahkan penanggung jawab dan tenggat dari teks satu butir.
+func parseItem(text string) Item {
+	var item Item
+	text = emphasis.Replace(text)
+
+	if m := dueLabel.FindStringSubmatchIndex(text); m != nil {
+		if due, ok := parseDate(text[m[2]:m[3]]); ok {
+			item.Due = due
+			text = text[:m[0]] + " " + text[m[1]:]
+		}
+	} else if due, loc, ok := findDate(text); ok {
+		item.Due = due
+		text = text[:loc[0]] + text[loc[1]:]
+	}
+
+	if m := labelAssignee.FindStringSubmatchIndex(text); m != nil {
+		item.Assignee = strings.TrimSpace(text[m[2]:m[3]])
+		text = text[:m[0]] + " " + text[m[1]:]
+	} else if m := bracketAssignee.FindStringSubmatchIndex(text); m != nil {
+		item.Assignee = strings.TrimSpace(text[m[2]:m[3]])
+		text = text[:m[0]] + text[m[1]:]
+	} else if m := leadingAssignee.FindStringSubmatch(strings.TrimSpace(text)); m != nil && len(strings.Fields(m[1])) <= 3 {
+		item.Assignee = strings.TrimSpace(m[1])
+		text = m[2]
+	}
+
+	item.Title = cleanTitle(text)
+	return item
+}
+
+// cleanTitle merapikan spasi dan tanda baca sisa setelah bagian
+// penanggung jawab atau tenggat dibuang.
+func cleanTitle(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.ReplaceAll(text, "()", "")
+	text = strings.ReplaceAll(text, " ,", ",")
+	text = strings.ReplaceAll(text, " .", ".")
+	text = strings.Trim(text, " -–—:;,")
+	return strings.TrimSpace(text)
+}
+
+// findDate mencari tanggal tanpa label, mis. "kirim laporan 2025-01-31".
+func findDate(text string) (string, []int, bool) {
+	for _, re := range []*regexp.Regexp{isoDate, slashDate, wordDate} {
+		if loc := re.FindStringIndex(text); loc != nil {
+			if due, ok := parseDate(text[loc[0]:loc[1]]); ok {
+				return due, loc, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// parseDate mengenali YYYY-MM-DD, DD/MM/YYYY dan "31 Januari 2025".
+func parseDate(raw string) (string, bool) {
+	raw = strings.TrimSpace(raw)
+	var (
+		y, d int
+		mon  time.Month
+	)
+	if m := isoDate.FindStringSubmatch(raw); m != nil {
+		y, _ = strconv.Atoi(m[1])
+		mi, _ := strconv.Atoi(m[2])
+		d, _ = strconv.Atoi(m[3])
+		mon = time.Month(mi)
+	} else if m := slashDate.FindStringSubmatch(raw); m != nil {
+		d, _ = strconv.Atoi(m[1])
+		mi, _ := strconv.Atoi(m[2])
+		y, _ = strconv.Atoi(m[3])
+		mon = time.Month(mi)
+	} else if m := wordDate.FindStringSubmatch(raw); m != nil {
+		d, _ = strconv.Atoi(m[1])
+		mon = months[strings.ToLower(m[2])]
+		y, _ = strconv.Atoi(m[3])
+	} else {
+		return "", false
+	}
+	if mon < time.January || mon > time.December || d < 1 {
+		return "", false
+	}
+	t := time.Date(y, mon, d, 0, 0, 0, 0, time.UTC)
+	if t.Day() != d || t.Month() != mon {
+		return "", false
+	}
+	return t.Format(dateLayout), true
+}
@@ -0,0 +1,124 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Jenis komponen kalender untuk ekspor ICS.
+const (
+	KindTodo  = "todo"
+	KindEvent = "event"
+)
+
+// Meeting adalah informasi rapat asal action item, dipakai di deskripsi
+// ICS dan template connector.
+type Meeting struct {
+	SummaryID string    `json:"summaryId"`
+	FileName  string    `json:"fileName"`
+	Date      time.Time `json:"date"`
+	URL       string    `json:"url"`
+}
+
+// icsLineLimit adalah panjang maksimal satu baris ICS dalam oktet (RFC 5545).
+const icsLineLimit = 75
+
+// ICS membuat file iCalendar berisi satu VTODO per item (kind "todo") atau
+// satu VEVENT sepanjang hari pada tanggal tenggat (kind "event"). Item tanpa
+// tenggat tidak punya tanggal untuk acara sehingga dilewati pada kind event.
+func ICS(items []Item, kind string, meeting Meeting, now time.Time) []byte {
+	w := &icsWriter{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//SummarizeMe//Action Items//ID")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + escapeText("Action item: "+meeting.FileName))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, item := range items {
+		due, hasDue := item.DueDate()
+		if kind == KindEvent && !hasDue {
+			continue
+		}
+		component := "VTODO"
+		if kind == KindEvent {
+			component = "VEVENT"
+		}
+		w.line("BEGIN:" + component)
+		w.line(fmt.Sprintf("UID:%s-%d@summarizeme", meeting.SummaryID, item.Index))
+		w.line("DTSTAMP:" + stamp)
+		w.line("SUMMARY:" + escapeText(item.Title))
+		w.line("DESCRIPTION:" + escapeText(describe(item, meeting)))
+		if meeting.URL != "" {
+			w.line("URL:" + meeting.URL)
+		}
+		if kind == KindEvent {
+			w.line("DTSTART;VALUE=DATE:" + due.Format("20060102"))
+			w.line("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format("20060102"))
+			w.line("TRANSP:TRANSPARENT")
+		} else {
+			if hasDue {
+				w.line("DUE;VALUE=DATE:" + due.Format("20060102"))
+			}
+			if item.Done {
+				w.line("STATUS:COMPLETED")
+			} else {
+				w.line("STATUS:NEEDS-ACTION")
+			}
+		}
+		w.line("END:" + component)
+	}
+	w.line("END:VCALENDAR")
+	return []byte(w.b.String())
+}
+
+// describe menyusun deskripsi item: detail, penanggung jawab dan asal rapat.
+func describe(item Item, meeting Meeting) string {
+	var parts []string
+	if item.Description != "" {
+		parts = append(parts, item.Description)
+	}
+	if item.Assignee != "" {
+		parts = append(parts, "Penanggung jawab: "+item.Assignee)
+	}
+	source := "Dari rapat: " + meeting.FileName
+	if !meeting.Date.IsZero() {
+		source += " (" + meeting.Date.Format(dateLayout) + ")"
+	}
+	parts = append(parts, source)
+	if meeting.URL != "" {
+		parts = append(parts, meeting.URL)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// escapeText meng-escape nilai TEXT sesuai RFC 5545 bagian 3.3.11.
+func escapeText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+type icsWriter struct {
+	b strings.Builder
+}
+
+// line menulis satu content line dengan CRLF, dilipat (folding) per 75
+// oktet tanpa memotong karakter UTF-8.
+func (w *icsWriter) line(s string) {
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.b.WriteString(s[:cut])
+		w.b.WriteString("\r\n ")
+		s = s[cut:]
+		// Baris lanjutan diawali spasi yang ikut dihitung.
+		limit = icsLineLimit - 1
+	}
+	w.b.WriteString(s)
+	w.b.WriteString("\r\n")
+}
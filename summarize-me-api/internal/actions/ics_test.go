@@ -0,0 +1,39 @@
+package actions
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "tulis ulang file golden di testdata")
+
+func TestICS(t *testing.T) {
+	items := []Item{
+		{Index: 1, Title: "Kirim draf proposal; revisi, bila perlu", Description: "Sertakan anggaran\nper divisi", Assignee: "Budi", Due: "2024-05-10"},
+		{Index: 2, Title: "Siapkan materi presentasi untuk rapat koordinasi lintas divisi bulan depan — versi final", Assignee: "Pembicara 1"},
+		{Index: 3, Title: "Pesan ruang rapat", Due: "2024-05-08", Done: true},
+	}
+	now := time.Date(2024, 5, 6, 10, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+
+	for _, kind := range []string{KindTodo, KindEvent} {
+		t.Run(kind, func(t *testing.T) {
+			got := ICS(items, kind, testMeeting, now)
+			golden := filepath.Join("testdata", "action_items_"+kind+".ics")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("baca golden (jalankan dengan -update untuk membuatnya): %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("ICS tidak sama dengan %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package actions
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitHubConnector membuat issue lewat POST /repos/{owner}/{repo}/issues.
+// Field template: title dan body.
+type gitHubConnector struct {
+	baseConnector
+}
+
+type gitHubIssue struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+}
+
+func (c *gitHubConnector) Push(ctx context.Context, item Item, meeting Meeting) (Ref, error) {
+	data := c.mapper.data(item, meeting)
+	fields, err := c.mapper.render(data)
+	if err != nil {
+		return Ref{}, err
+	}
+	issue := gitHubIssue{Title: fields["title"], Body: fields["body"], Labels: c.opts.Labels}
+	if data.Assignee != "" {
+		issue.Assignees = []string{data.Assignee}
+	}
+
+	var created struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	url := strings.TrimRight(c.opts.URL, "/") + "/repos/" + c.opts.Project + "/issues"
+	err = c.doJSON(ctx, http.MethodPost, url, issue, func(h http.Header, _ []byte) {
+		h.Set("Accept", "application/vnd.github+json")
+		h.Set("Authorization", "Bearer "+c.opts.Token)
+		h.Set("X-GitHub-Api-Version", "2022-11-28")
+	}, &created)
+	if err != nil {
+		return Ref{}, err
+	}
+	return Ref{ExternalID: c.opts.Project + "#" + strconv.Itoa(created.Number), URL: created.HTMLURL}, nil
+}
+
+// jiraConnector membuat issue lewat POST /rest/api/2/issue. Field template
+// summary dan description; field lain (mis. customfield_10010) dikirim
+// apa adanya sebagai string. Akun di Assignees dikirim sebagai accountId
+// (Jira Cloud), atau sebagai name jika diawali "name:" (Jira Server/DC).
+type jiraConnector struct {
+	baseConnector
+}
+
+func (c *jiraConnector) Push(ctx context.Context, item Item, meeting Meeting) (Ref, error) {
+	data := c.mapper.data(item, meeting)
+	fields, err := c.mapper.render(data)
+	if err != nil {
+		return Ref{}, err
+	}
+	payload := map[string]any{
+		"project":   map[string]string{"key": c.opts.Project},
+		"issuetype": map[string]string{"name": c.opts.IssueType},
+	}
+	for name, value := range fields {
+		if value != "" {
+			payload[name] = value
+		}
+	}
+	if len(c.opts.Labels) > 0 {
+		payload["labels"] = c.opts.Labels
+	}
+	if item.Due != "" {
+		payload["duedate"] = item.Due
+	}
+	if data.Assignee != "" {
+		if name, ok := strings.CutPrefix(data.Assignee, "name:"); ok {
+			payload["assignee"] = map[string]string{"name": name}
+		} else {
+			payload["assignee"] = map[string]string{"accountId": data.Assignee}
+		}
+	}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	base := strings.TrimRight(c.opts.URL, "/")
+	err = c.doJSON(ctx, http.MethodPost, base+"/rest/api/2/issue", map[string]any{"fields": payload}, func(h http.Header, _ []byte) {
+		if c.opts.Username != "" {
+			h.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.opts.Username+":"+c.opts.Token)))
+		} else {
+			h.Set("Authorization", "Bearer "+c.opts.Token)
+		}
+	}, &created)
+	if err != nil {
+		return Ref{}, err
+	}
+	if created.Key == "" {
+		return Ref{}, fmt.Errorf("%s tidak mengembalikan key issue", c.opts.Name)
+	}
+	return Ref{ExternalID: created.Key, URL: base + "/browse/" + created.Key}, nil
+}
+
+// Header yang dikirim connector webhook. Skema tanda tangan sama dengan
+// webhook job: HMAC-SHA256 hex atas "<timestamp>.<body>".
+const (
+	SignatureHeader = "X-SummarizeMe-Signature"
+	EventHeader     = "X-SummarizeMe-Event"
+	// EventActionItem adalah nama event untuk satu action item.
+	EventActionItem = "action_item.created"
+)
+
+// webhookConnector mengirim item sebagai JSON ke URL mana pun, mis. n8n,
+// Zapier atau tracker internal. Tanpa Fields, payload berisi item, assignee
+// dan meeting; dengan Fields, hasil template ikut dikirim di "fields".
+// Respons opsional {"id": "...", "url": "..."} dipakai sebagai referensi.
+type webhookConnector struct {
+	baseConnector
+}
+
+type webhookPayload struct {
+	Event    string            `json:"event"`
+	Item     Item              `json:"item"`
+	Assignee string            `json:"assignee,omitempty"`
+	Meeting  Meeting           `json:"meeting"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+func (c *webhookConnector) Push(ctx context.Context, item Item, meeting Meeting) (Ref, error) {
+	data := c.mapper.data(item, meeting)
+	fields, err := c.mapper.render(data)
+	if err != nil {
+		return Ref{}, err
+	}
+	body := webhookPayload{Event: EventActionItem, Item: item, Assignee: data.Assignee, Meeting: meeting}
+	if len(fields) > 0 {
+		body.Fields = fields
+	}
+
+	var created struct {
+		ID  any    `json:"id"`
+		URL string `json:"url"`
+	}
+	err = c.doJSON(ctx, http.MethodPost, c.opts.URL, body, func(h http.Header, payload []byte) {
+		h.Set(EventHeader, EventActionItem)
+		if c.opts.Token != "" {
+			h.Set("Authorization", "Bearer "+c.opts.Token)
+		}
+		if c.opts.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			h.Set(SignatureHeader, "t="+timestamp+",v1="+sign(c.opts.Secret, timestamp, payload))
+		}
+	}, &created)
+	if err != nil {
+		return Ref{}, err
+	}
+	ref := Ref{URL: created.URL}
+	if created.ID != nil {
+		ref.ExternalID = fmt.Sprint(created.ID)
+	}
+	return ref, nil
+}
+
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
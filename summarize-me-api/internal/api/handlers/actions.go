@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"summarize-me-api/internal/actions"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActionHandler menangani ekspor action item ke kalender dan issue tracker.
+type ActionHandler struct {
+	actions *services.ActionService
+}
+
+// NewActionHandler membuat instance handler
+func NewActionHandler(actions *services.ActionService) *ActionHandler {
+	return &ActionHandler{actions: actions}
+}
+
+// HandleListConnectors menangani GET /api/action-connectors
+func (h *ActionHandler) HandleListConnectors(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"connectors": h.actions.Connectors()})
+}
+
+// HandleListItems menangani GET /api/summaries/:id/action-items
+func (h *ActionHandler) HandleListItems(c *gin.Context) {
+	items, err := h.actions.Items(c.GetString("userID"), c.Param("id"))
+	if !h.check(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// HandleExportICS menangani GET /api/summaries/:id/action-items/ics?kind=todo|event
+func (h *ActionHandler) HandleExportICS(c *gin.Context) {
+	data, summary, err := h.actions.ICS(c.GetString("userID"), c.Param("id"), c.DefaultQuery("kind", actions.KindTodo))
+	if !h.check(c, err) {
+		return
+	}
+	name := strings.TrimSuffix(summary.FileName, filepath.Ext(summary.FileName))
+	if name == "" {
+		name = "ringkasan-" + summary.ID
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-action-items.ics"`, safeFileName(name)))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
+
+// PushActionItemsRequest adalah body JSON untuk
+// POST /api/summaries/:id/action-items/push. Items kosong berarti semua.
+type PushActionItemsRequest struct {
+	Connector string `json:"connector" binding:"required"`
+	Items     []int  `json:"items"`
+}
+
+// HandlePush menangani POST /api/summaries/:id/action-items/push
+func (h *ActionHandler) HandlePush(c *gin.Context) {
+	var req PushActionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("WARN: Gagal bind JSON push action item: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid, field 'connector' wajib diisi"})
+		return
+	}
+
+	results, err := h.actions.Push(c.Request.Context(), c.GetString("userID"), c.Param("id"), req.Connector, req.Items)
+	if !h.check(c, err) {
+		return
+	}
+	// 502 hanya jika semua item gagal; sebagian gagal tetap 200 dengan
+	// status per item.
+	status := http.StatusBadGateway
+	for _, r := range results {
+		if r.Status != services.PushFailed {
+			status = http.StatusOK
+			break
+		}
+	}
+	c.JSON(status, gin.H{"connector": req.Connector, "results": results})
+}
+
+func (h *ActionHandler) check(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidActionExport), errors.Is(err, services.ErrUnknownConnector):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+	case errors.Is(err, services.ErrNoActionItems):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Tidak ada action item yang dikenali di ringkasan ini"})
+	default:
+		log.Printf("ERROR: Gagal memproses action item ringkasan %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses action item"})
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"summarize-me-api/internal/resilience"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler menangani endpoint /api/admin untuk admin dan support.
+type AdminHandler struct {
+	users     *services.UserService
+	jobs      *services.JobService
+	providers *resilience.Registry
+}
+
+// NewAdminHandler membuat instance handler
+func NewAdminHandler(users *services.UserService, jobs *services.JobService, providers *resilience.Registry) *AdminHandler {
+	return &AdminHandler{users: users, jobs: jobs, providers: providers}
+}
+
+// HandleListUsers menangani GET /api/admin/users?q=
+func (h *AdminHandler) HandleListUsers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"users": h.users.FindUsers(c.Query("q"))})
+}
+
+// HandleGetUser menangani GET /api/admin/users/:uid
+func (h *AdminHandler) HandleGetUser(c *gin.Context) {
+	uid := c.Param("uid")
+	user, err := h.users.GetUser(uid)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil user %s: %v", uid, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil user"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"user":       user,
+		"recentJobs": h.jobs.ListJobs(services.JobFilter{UserID: uid, Limit: 20}),
+	})
+}
+
+// SetRolesRequest adalah body JSON untuk PUT /api/admin/users/:uid/roles
+type SetRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// HandleSetRoles menangani PUT /api/admin/users/:uid/roles (khusus admin)
+func (h *AdminHandler) HandleSetRoles(c *gin.Context) {
+	var req SetRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+
+	uid := c.Param("uid")
+	user, err := h.users.SetRoles(uid, req.Roles)
+	switch {
+	case errors.Is(err, services.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+		return
+	case err != nil:
+		log.Printf("ERROR: Gagal mengubah role user %s: %v", uid, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah role"})
+		return
+	}
+	log.Printf("Role user %s diubah menjadi %v oleh %s", uid, user.Roles, c.GetString("userID"))
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
+
+// HandleStats menangani GET /api/admin/stats?days=30
+func (h *AdminHandler) HandleStats(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter days harus angka positif"})
+		return
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	c.JSON(http.StatusOK, h.jobs.Stats(since))
+}
+
+// HandleProviders menangani GET /api/admin/providers: status circuit
+// breaker dan jumlah retry per provider sejak server dijalankan.
+func (h *AdminHandler) HandleProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.providers.Snapshot()})
+}
+
+// HandleListJobs menangani GET /api/admin/jobs?userId=&status=&limit=
+func (h *AdminHandler) HandleListJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter limit harus angka positif"})
+		return
+	}
+	jobs := h.jobs.ListJobs(services.JobFilter{
+		UserID: c.Query("userId"),
+		Status: services.JobStatus(c.Query("status")),
+		Limit:  limit,
+	})
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+// HandleGetJob menangani GET /api/admin/jobs/:id
+func (h *AdminHandler) HandleGetJob(c *gin.Context) {
+	job, err := h.jobs.GetJob(c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil job"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
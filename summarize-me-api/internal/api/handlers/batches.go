@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchHandler menangani upload banyak file sekaligus.
+type BatchHandler struct {
+	batches *services.BatchService
+}
+
+// NewBatchHandler membuat instance handler
+func NewBatchHandler(batches *services.BatchService) *BatchHandler {
+	return &BatchHandler{batches: batches}
+}
+
+// HandleCreateBatch menangani POST /api/batches (multipart).
+//
+// Field "files" boleh diisi berkali-kali dengan file audio atau archive ZIP;
+// isi ZIP diekstrak dan setiap file audio menjadi satu job. Field opsional:
+// "name", "notifyEmails", "force" (transkripsi ulang audio yang pernah
+// diunggah), "glossaryIds" dan "glossaryTerms".
+func (h *BatchHandler) HandleCreateBatch(c *gin.Context) {
+	userID := c.GetString("userID")
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Request multipart tidak valid"})
+		return
+	}
+	notifyEmails, err := services.ParseNotifyEmails(c.PostForm("notifyEmails"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	force, ok := formForce(c)
+	if !ok {
+		return
+	}
+
+	files, err := h.readFiles(form.File["files"])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.batches.CreateBatch(userID, strings.TrimSpace(c.PostForm("name")), files, notifyEmails, force, formGlossary(c))
+	switch {
+	case errors.Is(err, services.ErrInvalidBatch), errors.Is(err, services.ErrInvalidGlossary):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("ERROR: Gagal membuat batch untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat batch"})
+		return
+	}
+
+	log.Printf("Batch %s dibuat untuk userID %s dengan %d job", report.ID, userID, len(report.JobIDs))
+	c.JSON(http.StatusAccepted, gin.H{"batch": report})
+}
+
+// readFiles membaca semua upload dan mengekstrak archive ZIP, dengan batas
+// total ukuran dari konfigurasi.
+func (h *BatchHandler) readFiles(headers []*multipart.FileHeader) ([]services.InputFile, error) {
+	maxBytes := h.batches.Limits().MaxBytes
+	var (
+		files []services.InputFile
+		total int64
+	)
+	for _, fh := range headers {
+		if total+fh.Size > maxBytes {
+			return nil, fmt.Errorf("%w: total upload melebihi %d MB", services.ErrInvalidBatch, maxBytes>>20)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuka %s", fh.Filename)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca %s", fh.Filename)
+		}
+
+		if strings.EqualFold(filepath.Ext(fh.Filename), ".zip") {
+			extracted, err := services.ExtractAudioArchive(data, maxBytes-total)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range extracted {
+				total += int64(len(e.Data))
+			}
+			files = append(files, extracted...)
+			continue
+		}
+		if !services.IsSupportedFile(fh.Filename) {
+			return nil, fmt.Errorf("%w: %s bukan file audio, video atau transkrip yang didukung", services.ErrInvalidBatch, fh.Filename)
+		}
+		total += int64(len(data))
+		files = append(files, services.InputFile{Name: fh.Filename, Data: data})
+	}
+	return files, nil
+}
+
+// HandleListBatches menangani GET /api/batches
+func (h *BatchHandler) HandleListBatches(c *gin.Context) {
+	batches := h.batches.ListBatches(c.GetString("userID"))
+	if batches == nil {
+		batches = []services.Batch{}
+	}
+	c.JSON(http.StatusOK, gin.H{"batches": batches})
+}
+
+// HandleGetBatch menangani GET /api/batches/:id
+func (h *BatchHandler) HandleGetBatch(c *gin.Context) {
+	report, err := h.batches.GetBatch(c.GetString("userID"), c.Param("id"))
+	if !h.checkFound(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"batch": report})
+}
+
+// HandleExportBatch menangani GET /api/batches/:id/export?format=md|json
+// dan mengembalikan ringkasan semua job dalam satu file.
+func (h *BatchHandler) HandleExportBatch(c *gin.Context) {
+	report, items, err := h.batches.Export(c.GetString("userID"), c.Param("id"))
+	if !h.checkFound(c, err) {
+		return
+	}
+
+	name := report.Name
+	if name == "" {
+		name = "batch-" + report.ID
+	}
+	switch c.DefaultQuery("format", "md") {
+	case "json":
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, safeFileName(name)))
+		c.JSON(http.StatusOK, gin.H{"batch": report.Batch, "status": report.Status, "items": items})
+	case "md":
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", name)
+		for _, item := range items {
+			fmt.Fprintf(&b, "## %s\n\n", item.FileName)
+			switch item.Status {
+			case services.JobCompleted:
+				b.WriteString(strings.TrimSpace(item.Summary))
+			case services.JobFailed:
+				fmt.Fprintf(&b, "_Gagal diproses: %s_", item.Error)
+			default:
+				b.WriteString("_Masih diproses._")
+			}
+			b.WriteString("\n\n")
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.md"`, safeFileName(name)))
+		c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(b.String()))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter format harus 'md' atau 'json'"})
+	}
+}
+
+func (h *BatchHandler) checkFound(c *gin.Context, err error) bool {
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch tidak ditemukan"})
+		return false
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil batch %s: %v", c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil batch"})
+		return false
+	}
+	return true
+}
+
+// safeFileName membuang karakter yang bermasalah di header Content-Disposition.
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '"' || r == '/' || r == '\\' || r < 32 {
+			return '_'
+		}
+		return r
+	}, name)
+}
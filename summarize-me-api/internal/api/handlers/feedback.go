@@ -1,32 +1,32 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"summarize-me-api/internal/feedback"
+	"summarize-me-api/internal/store"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"google.golang.org/api/sheets/v4"
 )
 
-// FeedbackHandler untuk dependensi (jika ada, saat ini kosong)
+// FeedbackHandler menampung sink tujuan penyimpanan feedback.
 type FeedbackHandler struct {
-	SheetID string
-	Range   string
+	sink feedback.Sink
 }
 
 // NewFeedbackHandler membuat instance handler
-func NewFeedbackHandler(sheetID string, sheetRange string) *FeedbackHandler {
-	return &FeedbackHandler{
-		SheetID: sheetID,
-		Range:   sheetRange,
-	}
+func NewFeedbackHandler(sink feedback.Sink) *FeedbackHandler {
+	return &FeedbackHandler{sink: sink}
 }
 
 // Struct untuk menampung data JSON dari frontend
 type FeedbackRequest struct {
 	Email   string `json:"email"`
 	Comment string `json:"comment"`
+	Page    string `json:"page"`
 }
 
 // HandleSubmitFeedback menangani POST /api/feedback
@@ -45,42 +45,56 @@ func (h *FeedbackHandler) HandleSubmitFeedback(c *gin.Context) {
 		return
 	}
 
-	// 2. Dapatkan konteks dan userID (dari middleware)
-	ctx := c.Request.Context()
-	userID, _ := c.Get("userID")
-
+	// 2. Dapatkan userID (dari middleware)
+	userID := c.GetString("userID")
 	log.Printf("Menerima feedback dari userID: %s", userID)
 
-	// 3. Inisialisasi Google Sheets Service
-	// Ini akan otomatis menggunakan Application Default Credentials (ADC)
-	// yang sudah terkonfigurasi di lingkungan Cloud Run Anda.
-	sheetsService, err := sheets.NewService(ctx)
-	if err != nil {
-		log.Printf("ERROR: Gagal membuat Sheets service: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal terhubung ke Google Sheets"})
+	// 3. Kirim ke semua sink yang dikonfigurasi
+	fb := feedback.Feedback{
+		ID:        store.NewID(),
+		UserID:    userID,
+		Email:     req.Email,
+		Comment:   req.Comment,
+		Page:      req.Page,
+		UserAgent: c.GetHeader("User-Agent"),
+		CreatedAt: time.Now(),
+	}
+	if err := h.sink.Submit(c.Request.Context(), fb); err != nil {
+		log.Printf("ERROR: Gagal menyimpan feedback: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan feedback"})
 		return
 	}
 
-	// 4. Siapkan data untuk dikirim
-	row := &sheets.ValueRange{
-		Values: [][]interface{}{
-			{time.Now().Format("2006-01-02/15:04:05"), req.Email, req.Comment},
-		},
+	log.Printf("Berhasil menyimpan feedback dari %s", req.Email)
+	c.JSON(http.StatusOK, gin.H{"message": "Feedback submitted successfully"})
+}
+
+// HandleListFeedback menangani GET /api/admin/feedback?limit=
+func (h *FeedbackHandler) HandleListFeedback(c *gin.Context) {
+	lister, ok := h.sink.(feedback.Lister)
+	if !ok {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Feedback sink tidak mendukung listing"})
+		return
 	}
 
-	// 5. Kirim data ke Google Sheet
-	_, err = sheetsService.Spreadsheets.Values.Append(h.SheetID, h.Range, row).
-		ValueInputOption("USER_ENTERED").
-		InsertDataOption("INSERT_ROWS").
-		Context(ctx).
-		Do()
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter limit harus angka positif"})
+		return
+	}
 
+	items, err := lister.List(c.Request.Context(), limit)
+	if errors.Is(err, feedback.ErrListUnsupported) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Feedback sink tidak mendukung listing"})
+		return
+	}
 	if err != nil {
-		log.Printf("ERROR: Gagal menulis ke Google Sheet: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan feedback"})
+		log.Printf("ERROR: Gagal membaca feedback: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membaca feedback"})
 		return
 	}
-
-	log.Printf("Berhasil menyimpan feedback dari %s", req.Email)
-	c.JSON(http.StatusOK, gin.H{"message": "Feedback submitted successfully"})
-}
\ No newline at end of file
+	if items == nil {
+		items = []feedback.Feedback{}
+	}
+	c.JSON(http.StatusOK, gin.H{"feedback": items})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GlossaryHandler menangani glosarium istilah milik user dan glosarium
+// bersama yang dikelola admin.
+type GlossaryHandler struct {
+	service *services.GlossaryService
+}
+
+// NewGlossaryHandler membuat instance handler
+func NewGlossaryHandler(s *services.GlossaryService) *GlossaryHandler {
+	return &GlossaryHandler{service: s}
+}
+
+// HandleListGlossaries menangani GET /api/glossaries. Glosarium bersama
+// ikut dikembalikan dengan shared=true.
+func (h *GlossaryHandler) HandleListGlossaries(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"glossaries": h.service.ListGlossaries(c.GetString("userID"))})
+}
+
+// HandleGetGlossary menangani GET /api/glossaries/:id
+func (h *GlossaryHandler) HandleGetGlossary(c *gin.Context) {
+	glossary, err := h.service.GetGlossary(c.GetString("userID"), c.Param("id"))
+	if !h.check(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"glossary": glossary})
+}
+
+// HandleCreateGlossary menangani POST /api/glossaries
+func (h *GlossaryHandler) HandleCreateGlossary(c *gin.Context) {
+	h.create(c, false)
+}
+
+// HandleUpdateGlossary menangani PUT /api/glossaries/:id
+func (h *GlossaryHandler) HandleUpdateGlossary(c *gin.Context) {
+	h.update(c, false)
+}
+
+// HandleDeleteGlossary menangani DELETE /api/glossaries/:id
+func (h *GlossaryHandler) HandleDeleteGlossary(c *gin.Context) {
+	h.delete(c, false)
+}
+
+// HandleCreateShared menangani POST /api/admin/glossaries
+func (h *GlossaryHandler) HandleCreateShared(c *gin.Context) {
+	h.create(c, true)
+}
+
+// HandleUpdateShared menangani PUT /api/admin/glossaries/:id
+func (h *GlossaryHandler) HandleUpdateShared(c *gin.Context) {
+	h.update(c, true)
+}
+
+// HandleDeleteShared menangani DELETE /api/admin/glossaries/:id
+func (h *GlossaryHandler) HandleDeleteShared(c *gin.Context) {
+	h.delete(c, true)
+}
+
+func (h *GlossaryHandler) create(c *gin.Context, shared bool) {
+	var input services.GlossaryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+	glossary, err := h.service.CreateGlossary(c.GetString("userID"), input, shared)
+	if !h.check(c, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"glossary": glossary})
+}
+
+func (h *GlossaryHandler) update(c *gin.Context, shared bool) {
+	var input services.GlossaryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+	glossary, err := h.service.UpdateGlossary(c.GetString("userID"), c.Param("id"), input, shared)
+	if !h.check(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"glossary": glossary})
+}
+
+func (h *GlossaryHandler) delete(c *gin.Context, shared bool) {
+	err := h.service.DeleteGlossary(c.GetString("userID"), c.Param("id"), shared)
+	if !h.check(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Glosarium berhasil dihapus"})
+}
+
+// check menulis respons error dan mengembalikan false jika err tidak nil.
+func (h *GlossaryHandler) check(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidGlossary):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Glosarium tidak ditemukan"})
+	default:
+		log.Printf("ERROR: Operasi glosarium gagal: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses glosarium"})
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"summarize-me-api/internal/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler melayani endpoint liveness dan readiness.
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler membuat instance handler
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// HandleLiveness menangani GET /healthz. Hanya menandakan proses hidup,
+// tanpa memeriksa dependensi eksternal.
+func (h *HealthHandler) HandleLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusUp})
+}
+
+// HandleReadiness menangani GET /readyz. Mengembalikan 503 jika ada
+// dependensi yang tidak siap agar load balancer berhenti mengirim traffic.
+func (h *HealthHandler) HandleReadiness(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+	code := http.StatusOK
+	if !report.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, report)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"summarize-me-api/internal/remote"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler menangani pembuatan dan status job milik user.
+type JobHandler struct {
+	jobs    *services.JobService
+	runner  *services.JobRunner
+	fetcher *remote.Fetcher
+}
+
+// NewJobHandler membuat instance handler
+func NewJobHandler(jobs *services.JobService, runner *services.JobRunner, fetcher *remote.Fetcher) *JobHandler {
+	return &JobHandler{jobs: jobs, runner: runner, fetcher: fetcher}
+}
+
+// CreateJobRequest adalah body JSON untuk POST /api/jobs
+type CreateJobRequest struct {
+	// SourceURL adalah link audio langsung atau feed RSS podcast.
+	SourceURL string `json:"sourceURL"`
+	// ItemGUID memilih episode RSS; kosong berarti episode terbaru.
+	ItemGUID     string   `json:"itemGuid"`
+	NotifyEmails []string `json:"notifyEmails"`
+	// Force mentranskrip ulang meski audio yang sama pernah diproses.
+	Force bool `json:"force"`
+	// Glossary mengganti glosarium bawaan untuk job ini.
+	Glossary *services.GlossaryOverride `json:"glossary"`
+}
+
+// HandleCreateJob menangani POST /api/jobs. Audio diunduh dan diproses di
+// background; status dipantau lewat GET /api/jobs/:id.
+func (h *JobHandler) HandleCreateJob(c *gin.Context) {
+	var req CreateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.SourceURL) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Field 'sourceURL' wajib diisi"})
+		return
+	}
+	notifyEmails, err := services.ParseNotifyEmails(strings.Join(req.NotifyEmails, ","))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := c.GetString("userID")
+	if _, err := h.runner.ResolveGlossary(userID, req.Glossary); !checkGlossary(c, err) {
+		return
+	}
+	if err := h.fetcher.Check(c.Request.Context(), req.SourceURL); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sourceURL := strings.TrimSpace(req.SourceURL)
+	job, err := h.runner.EnqueueFetch(services.Job{
+		UserID:       userID,
+		FileName:     sourceName(sourceURL),
+		SourceURL:    sourceURL,
+		NotifyEmails: notifyEmails,
+		Force:        req.Force,
+		Glossary:     req.Glossary,
+	}, func(ctx context.Context) (services.InputFile, error) {
+		audio, err := h.fetcher.FetchAudio(ctx, sourceURL, req.ItemGUID)
+		if err != nil {
+			return services.InputFile{}, err
+		}
+		log.Printf("Berhasil mengunduh %s (%d bytes, %s)", audio.SourceURL, len(audio.Data), audio.ContentType)
+		return services.InputFile{Name: audio.FileName, Data: audio.Data}, nil
+	})
+	switch {
+	case errors.Is(err, services.ErrQueueFull):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("ERROR: Gagal membuat job untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat job"})
+		return
+	}
+
+	log.Printf("Job %s dari URL dibuat untuk userID %s", job.ID, userID)
+	c.JSON(http.StatusAccepted, gin.H{"job": job})
+}
+
+// HandleGetJob menangani GET /api/jobs/:id
+func (h *JobHandler) HandleGetJob(c *gin.Context) {
+	id := c.Param("id")
+	job, err := h.jobs.GetJob(id)
+	if errors.Is(err, store.ErrNotFound) || (err == nil && job.UserID != c.GetString("userID")) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil job %s: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil job"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": job})
+}
+
+// sourceName adalah nama sementara job sampai audio selesai diunduh.
+func sourceName(sourceURL string) string {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return sourceURL
+	}
+	if name := path.Base(u.Path); name != "/" && name != "." {
+		return name
+	}
+	return u.Host
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"summarize-me-api/internal/api/middleware"
+	"summarize-me-api/internal/auth"
+	"summarize-me-api/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// maxLiveMessage membatasi ukuran satu pesan WebSocket dari klien.
+const maxLiveMessage = 1 << 20
+
+// LiveHandler menangani transkripsi live lewat WebSocket.
+type LiveHandler struct {
+	live     *services.LiveService
+	tickets  *auth.TicketStore
+	upgrader websocket.Upgrader
+}
+
+// NewLiveHandler membuat instance handler. Origin WebSocket dicek terhadap
+// daftar origin CORS.
+func NewLiveHandler(live *services.LiveService, tickets *auth.TicketStore, allowedOrigins []string) *LiveHandler {
+	return &LiveHandler{
+		live:    live,
+		tickets: tickets,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" {
+					return true // klien non-browser
+				}
+				for _, allowed := range allowedOrigins {
+					if origin == allowed {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+}
+
+// liveControl adalah pesan teks dari klien. Saat ini hanya {"type":"stop"}.
+type liveControl struct {
+	Type string `json:"type"`
+}
+
+// HandleIssueTicket menangani POST /api/live/ticket: menukar bearer token
+// dengan tiket sekali pakai yang berlaku auth.TicketTTL, untuk dikirim
+// sebagai query ticket saat membuka GET /api/live.
+func (h *LiveHandler) HandleIssueTicket(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Principal tidak ditemukan"})
+		return
+	}
+	ticket, expiresAt, err := h.tickets.Issue(principal)
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat tiket live untuk userID %s: %v", principal.UID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat tiket"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"ticket": ticket, "expiresAt": expiresAt})
+}
+
+// HandleLive menangani GET /api/live (WebSocket).
+//
+// Browser mengautentikasi dengan query ticket dari POST /api/live/ticket;
+// klien lain boleh memakai header Authorization. Query opsional: encoding
+// (webm_opus, ogg_opus, linear16), sampleRate dan language. Klien mengirim potongan audio sebagai pesan biner dan
+// {"type":"stop"} untuk selesai; server membalas LiveEvent dalam JSON.
+func (h *LiveHandler) HandleLive(c *gin.Context) {
+	userID := c.GetString("userID")
+	sampleRate, err := strconv.Atoi(c.DefaultQuery("sampleRate", "48000"))
+	if err != nil || sampleRate <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter sampleRate harus angka positif"})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		log.Printf("WARN: Gagal upgrade WebSocket untuk userID %s: %v", userID, err)
+		return
+	}
+	defer conn.Close()
+
+	session, err := h.live.Start(userID, services.StreamConfig{
+		Encoding:        c.DefaultQuery("encoding", "webm_opus"),
+		SampleRateHertz: int32(sampleRate),
+		LanguageCode:    c.Query("language"),
+	})
+	if err != nil {
+		log.Printf("ERROR: Gagal memulai sesi live untuk userID %s: %v", userID, err)
+		conn.WriteJSON(services.LiveEvent{Type: services.LiveEventError, Error: err.Error()})
+		return
+	}
+	conn.SetReadLimit(maxLiveMessage)
+	log.Printf("Sesi live dimulai untuk userID %s", userID)
+
+	// Penulis: teruskan semua event sampai channel ditutup. Event tetap
+	// dikuras walau koneksi sudah putus agar sesi tidak macet.
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for ev := range session.Events() {
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			if err := conn.WriteJSON(ev); err != nil {
+				log.Printf("WARN: Gagal mengirim event live ke userID %s: %v", userID, err)
+			}
+		}
+	}()
+
+	// Batas durasi sesi: hentikan pembacaan, lalu sesi ditutup seperti stop
+	timer := time.AfterFunc(h.live.MaxDuration(), func() {
+		log.Printf("Sesi live userID %s mencapai batas durasi", userID)
+		conn.SetReadDeadline(time.Now())
+	})
+	defer timer.Stop()
+
+	h.readAudio(conn, session)
+	session.Finish()
+	<-written
+
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	log.Printf("Sesi live selesai untuk userID %s", userID)
+}
+
+// readAudio membaca pesan klien sampai stop, error, atau koneksi putus.
+func (h *LiveHandler) readAudio(conn *websocket.Conn, session *services.LiveSession) {
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		switch msgType {
+		case websocket.BinaryMessage:
+			if err := session.Write(data); err != nil {
+				log.Printf("WARN: Gagal meneruskan audio live: %v", err)
+				return
+			}
+		case websocket.TextMessage:
+			var ctrl liveControl
+			if json.Unmarshal(data, &ctrl) == nil && ctrl.Type == "stop" {
+				return
+			}
+		}
+	}
+}
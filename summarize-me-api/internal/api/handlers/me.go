@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MeHandler menangani endpoint /api/me milik user yang sedang login.
+type MeHandler struct {
+	users *services.UserService
+}
+
+// NewMeHandler membuat instance handler
+func NewMeHandler(users *services.UserService) *MeHandler {
+	return &MeHandler{users: users}
+}
+
+// HandleGetPreferences menangani GET /api/me/preferences
+func (h *MeHandler) HandleGetPreferences(c *gin.Context) {
+	user, err := h.users.GetUser(c.GetString("userID"))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		log.Printf("ERROR: Gagal mengambil user %s: %v", c.GetString("userID"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil preferensi"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": user.Preferences})
+}
+
+// HandleUpdatePreferences menangani PUT /api/me/preferences
+func (h *MeHandler) HandleUpdatePreferences(c *gin.Context) {
+	var prefs services.UserPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	user, err := h.users.UpdatePreferences(userID, prefs)
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal menyimpan preferensi user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan preferensi"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": user.Preferences})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QAHandler menangani tanya-jawab atas transkrip sebuah ringkasan.
+type QAHandler struct {
+	qa *services.QAService
+}
+
+// NewQAHandler membuat instance handler
+func NewQAHandler(qa *services.QAService) *QAHandler {
+	return &QAHandler{qa: qa}
+}
+
+// AskRequest adalah body JSON untuk POST /api/summaries/:id/ask
+type AskRequest struct {
+	Question string `json:"question" binding:"required"`
+}
+
+// HandleAsk menangani POST /api/summaries/:id/ask
+func (h *QAHandler) HandleAsk(c *gin.Context) {
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("WARN: Gagal bind JSON pertanyaan: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid, field 'question' wajib diisi"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	answer, err := h.qa.Ask(c.Request.Context(), userID, c.Param("id"), req.Question)
+	switch {
+	case errors.Is(err, services.ErrInvalidQuestion):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+		return
+	case errors.Is(err, services.ErrNoTranscript):
+		c.JSON(http.StatusConflict, gin.H{"error": "Transkrip untuk ringkasan ini tidak tersimpan, tanya-jawab tidak tersedia"})
+		return
+	case err != nil:
+		log.Printf("ERROR: Gagal menjawab pertanyaan untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menjawab pertanyaan"})
+		return
+	}
+	c.JSON(http.StatusOK, answer)
+}
+
+// HandleGetConversation menangani GET /api/summaries/:id/ask
+func (h *QAHandler) HandleGetConversation(c *gin.Context) {
+	userID := c.GetString("userID")
+	conv, err := h.qa.Conversation(userID, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil riwayat percakapan untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil riwayat percakapan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"conversation": conv})
+}
+
+// HandleResetConversation menangani DELETE /api/summaries/:id/ask
+func (h *QAHandler) HandleResetConversation(c *gin.Context) {
+	userID := c.GetString("userID")
+	err := h.qa.ResetConversation(userID, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal menghapus riwayat percakapan untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus riwayat percakapan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Riwayat percakapan berhasil dihapus"})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RatingHandler menangani penilaian kualitas ringkasan.
+type RatingHandler struct {
+	summaries *services.SummaryService
+}
+
+// NewRatingHandler membuat instance handler
+func NewRatingHandler(summaries *services.SummaryService) *RatingHandler {
+	return &RatingHandler{summaries: summaries}
+}
+
+// RatingRequest adalah body JSON untuk POST /api/summaries/:id/rating
+type RatingRequest struct {
+	Thumb   string   `json:"thumb"`
+	Score   int      `json:"score"`
+	Issues  []string `json:"issues"`
+	Comment string   `json:"comment"`
+}
+
+// HandleRateSummary menangani POST /api/summaries/:id/rating
+func (h *RatingHandler) HandleRateSummary(c *gin.Context) {
+	var req RatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("WARN: Gagal bind JSON rating: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	rating, err := h.summaries.RateSummary(userID, c.Param("id"), services.RatingInput{
+		Thumb:   services.Thumb(req.Thumb),
+		Score:   req.Score,
+		Issues:  req.Issues,
+		Comment: req.Comment,
+	})
+	switch {
+	case errors.Is(err, services.ErrInvalidRating):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+		return
+	case err != nil:
+		log.Printf("ERROR: Gagal menyimpan rating untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan rating"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rating": rating})
+}
+
+// HandleRatingStats menangani GET /api/admin/ratings/stats?days=30
+func (h *RatingHandler) HandleRatingStats(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter days harus angka positif"})
+		return
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	c.JSON(http.StatusOK, gin.H{"since": since, "groups": h.summaries.RatingStats(since)})
+}
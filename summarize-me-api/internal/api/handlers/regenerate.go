@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegenerateHandler menangani pembuatan ulang ringkasan dari transkrip
+// yang tersimpan dan pemilihan variannya.
+type RegenerateHandler struct {
+	regenerate *services.RegenerateService
+	summaries  *services.SummaryService
+}
+
+// NewRegenerateHandler membuat instance handler
+func NewRegenerateHandler(regenerate *services.RegenerateService, summaries *services.SummaryService) *RegenerateHandler {
+	return &RegenerateHandler{regenerate: regenerate, summaries: summaries}
+}
+
+// RegenerateRequest adalah body JSON untuk POST /api/summaries/:id/regenerate.
+// Semua field opsional; nilai kosong memakai opsi bawaan.
+type RegenerateRequest struct {
+	Template string `json:"template"`
+	Length   string `json:"length"`
+	Language string `json:"language"`
+	Model    string `json:"model"`
+	// Activate menjadikan hasilnya isi ringkasan (dipakai ekspor, pencarian, dll).
+	Activate bool `json:"activate"`
+}
+
+// HandleOptions menangani GET /api/summary-options
+func (h *RegenerateHandler) HandleOptions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.regenerate.Options())
+}
+
+// HandleRegenerate menangani POST /api/summaries/:id/regenerate
+func (h *RegenerateHandler) HandleRegenerate(c *gin.Context) {
+	var req RegenerateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Printf("WARN: Gagal bind JSON regenerate: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+			return
+		}
+	}
+
+	userID := c.GetString("userID")
+	opts := services.SummaryOptions{Template: req.Template, Length: req.Length, Language: req.Language, Model: req.Model}
+	variant, summary, err := h.regenerate.Regenerate(c.Request.Context(), userID, c.Param("id"), opts, req.Activate)
+	if !h.check(c, err, "Gagal membuat ulang ringkasan") {
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"variant": variant, "summary": summary})
+}
+
+// HandleActivateVariant menangani POST /api/summaries/:id/variants/:variantId/activate
+func (h *RegenerateHandler) HandleActivateVariant(c *gin.Context) {
+	summary, err := h.summaries.ActivateVariant(c.GetString("userID"), c.Param("id"), c.Param("variantId"))
+	if !h.check(c, err, "Gagal memilih varian ringkasan") {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"summary": summary})
+}
+
+func (h *RegenerateHandler) check(c *gin.Context, err error, msg string) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidSummaryOptions):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+	case errors.Is(err, services.ErrVariantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Varian ringkasan tidak ditemukan"})
+	case errors.Is(err, services.ErrNoTranscript):
+		c.JSON(http.StatusConflict, gin.H{"error": "Transkrip untuk ringkasan ini tidak tersimpan, ringkasan tidak bisa dibuat ulang"})
+	case errors.Is(err, services.ErrTooManyVariants):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR: %s %s: %v", msg, c.Param("id"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"summarize-me-api/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// SearchHandler menangani pencarian semantik dan kata kunci atas riwayat
+// rapat.
+type SearchHandler struct {
+	search   *services.SearchService
+	fulltext *services.FullTextService
+}
+
+// NewSearchHandler membuat instance handler. search nil berarti pencarian
+// semantik dimatikan lewat konfigurasi.
+func NewSearchHandler(search *services.SearchService, fulltext *services.FullTextService) *SearchHandler {
+	return &SearchHandler{search: search, fulltext: fulltext}
+}
+
+// HandleSearch menangani GET /api/search?q=...&limit=10
+func (h *SearchHandler) HandleSearch(c *gin.Context) {
+	if h.search == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Pencarian semantik tidak aktif"})
+		return
+	}
+	limit, ok := searchLimit(c)
+	if !ok {
+		return
+	}
+
+	userID := c.GetString("userID")
+	query := c.Query("q")
+	results, err := h.search.Search(c.Request.Context(), userID, query, limit)
+	if errors.Is(err, services.ErrInvalidQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mencari untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal melakukan pencarian"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"query": query, "results": results})
+}
+
+// HandleKeywordSearch menangani GET /api/search/keyword?q=...&from=2025-01-01
+// &to=2025-01-31&tags=a,b&speaker=Budi&kind=transcript&limit=10&offset=0.
+// Frasa persis ditulis dalam tanda kutip ganda.
+func (h *SearchHandler) HandleKeywordSearch(c *gin.Context) {
+	limit, ok := searchLimit(c)
+	if !ok {
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter offset harus angka"})
+		return
+	}
+	from, err := parseDateParam(c.Query("from"), false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter from harus tanggal YYYY-MM-DD atau RFC3339"})
+		return
+	}
+	to, err := parseDateParam(c.Query("to"), true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter to harus tanggal YYYY-MM-DD atau RFC3339"})
+		return
+	}
+	var tags []string
+	for _, tag := range strings.Split(c.Query("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	userID := c.GetString("userID")
+	results, err := h.fulltext.Search(userID, services.KeywordQuery{
+		Text:    c.Query("q"),
+		From:    from,
+		To:      to,
+		Tags:    tags,
+		Speaker: strings.TrimSpace(c.Query("speaker")),
+		Kind:    c.Query("kind"),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if errors.Is(err, services.ErrInvalidQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mencari kata kunci untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal melakukan pencarian"})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// searchLimit membaca parameter limit; respons 400 sudah ditulis jika tidak
+// valid.
+func searchLimit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultSearchLimit, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > maxSearchLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter limit harus angka 1 sampai 50"})
+		return 0, false
+	}
+	return n, true
+}
+
+// parseDateParam menerima tanggal YYYY-MM-DD (UTC) atau RFC3339. Untuk batas
+// akhir, tanggal tanpa jam diperlakukan sampai akhir hari tersebut.
+func parseDateParam(raw string, endOfDay bool) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		if endOfDay {
+			t = t.Add(24*time.Hour - time.Nanosecond)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
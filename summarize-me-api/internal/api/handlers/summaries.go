@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SummaryHandler menangani pembacaan ringkasan yang tersimpan.
+type SummaryHandler struct {
+	summaries *services.SummaryService
+}
+
+// NewSummaryHandler membuat instance handler
+func NewSummaryHandler(summaries *services.SummaryService) *SummaryHandler {
+	return &SummaryHandler{summaries: summaries}
+}
+
+// HandleGetSummary menangani GET /api/summaries/:id
+func (h *SummaryHandler) HandleGetSummary(c *gin.Context) {
+	userID := c.GetString("userID")
+	summary, err := h.summaries.GetSummary(userID, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mengambil ringkasan untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil ringkasan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"summary": summary})
+}
+
+// UpdateSummaryRequest adalah body JSON untuk PATCH /api/summaries/:id.
+// Field yang tidak dikirim tidak diubah.
+type UpdateSummaryRequest struct {
+	Text *string   `json:"text"`
+	Tags *[]string `json:"tags"`
+}
+
+// HandleUpdateSummary menangani PATCH /api/summaries/:id
+func (h *SummaryHandler) HandleUpdateSummary(c *gin.Context) {
+	var req UpdateSummaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("WARN: Gagal bind JSON perubahan ringkasan: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	summary, err := h.summaries.UpdateSummary(userID, c.Param("id"), services.SummaryUpdate{Text: req.Text, Tags: req.Tags})
+	switch {
+	case errors.Is(err, services.ErrInvalidSummaryUpdate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ringkasan tidak ditemukan"})
+		return
+	case err != nil:
+		log.Printf("ERROR: Gagal mengubah ringkasan untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengubah ringkasan"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"summary": summary})
+}
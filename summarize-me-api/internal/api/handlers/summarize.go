@@ -2,9 +2,12 @@ package handlers
 
 import (
 	// "fmt"
+	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"summarize-me-api/internal/services" // Import service
 
 	"github.com/gin-gonic/gin"
@@ -12,12 +15,13 @@ import (
 
 // SummarizeHandler menampung dependensi untuk handler ringkasan.
 type SummarizeHandler struct {
-	service *services.SummarizeService
+	runner *services.JobRunner
+	jobs   *services.JobService
 }
 
 // NewSummarizeHandler membuat instance baru dari SummarizeHandler.
-func NewSummarizeHandler(s *services.SummarizeService) *SummarizeHandler {
-	return &SummarizeHandler{service: s}
+func NewSummarizeHandler(runner *services.JobRunner, jobs *services.JobService) *SummarizeHandler {
+	return &SummarizeHandler{runner: runner, jobs: jobs}
 }
 
 // HandleSummarize menangani request POST /api/summarize.
@@ -31,46 +35,152 @@ func (h *SummarizeHandler) HandleSummarize(c *gin.Context) {
 	}
 	log.Printf("Menerima request /api/summarize dari userID: %s", userID)
 
-	// 2. Ambil file (tetap sama)
-	file, err := c.FormFile("audioFile")
-	if err != nil {
-		log.Printf("WARN: Gagal mengambil file dari form: %v", err) 
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File 'audioFile' tidak ditemukan atau request tidak valid"})
+	// 2. Ambil input: transkrip mentah di field "text", atau file upload
+	fileName, fileData, ok := readSummarizeInput(c)
+	if !ok {
 		return
 	}
+	log.Printf("Berhasil menerima file: %s (Ukuran: %d bytes) dari userID: %s", fileName, len(fileData), userID)
 
-	// 3. Buka dan baca file (tetap sama)
-	openedFile, err := file.Open()
+	// Alamat peserta opsional yang ikut menerima email ringkasan
+	notifyEmails, err := services.ParseNotifyEmails(c.PostForm("notifyEmails"))
 	if err != nil {
-		log.Printf("ERROR: Gagal membuka file upload: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses file upload (tidak bisa dibuka)"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	defer openedFile.Close()
 
-	fileData, err := ioutil.ReadAll(openedFile)
-	if err != nil {
-		log.Printf("ERROR: Gagal membaca file upload: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses file upload (tidak bisa dibaca)"})
+	// "force" mentranskrip ulang meski audio yang sama pernah diunggah
+	force, ok := formForce(c)
+	if !ok {
+		return
+	}
+
+	// Glosarium pengganti opsional; ditolak di sini agar job tidak dibuat
+	glossary := formGlossary(c)
+	if _, err := h.runner.ResolveGlossary(userID.(string), glossary); !checkGlossary(c, err) {
 		return
 	}
-	log.Printf("Berhasil menerima file: %s (Ukuran: %d bytes) dari userID: %s", file.Filename, len(fileData), userID)
 
-	// 4. Panggil service, TAMBAHKAN file.Filename
-	// === PERBAIKAN: Ganti nama variabel 'summary' menjadi 'result' ===
-	result, err := h.service.TranscribeAndSummarize(c.Request.Context(), fileData, file.Filename) // <-- 'result' adalah map
+	// 3. Catat job agar bisa diinspeksi admin
+	job, err := h.jobs.StartJob(services.Job{
+		UserID:       userID.(string),
+		FileName:     fileName,
+		FileSize:     len(fileData),
+		NotifyEmails: notifyEmails,
+		Force:        force,
+		Glossary:     glossary,
+	})
+	if err != nil {
+		log.Printf("WARN: Gagal mencatat job untuk userID %s: %v", userID, err)
+	}
+
+	// 4. Proses langsung di request; runner juga mencatat ringkasan dan
+	// menandai job selesai
+	result, summary, err := h.runner.Run(c.Request.Context(), job, fileData)
 	if err != nil {
 		log.Printf("ERROR: Gagal TranscribeAndSummarize untuk userID %s: %v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Terjadi kesalahan saat memproses audio Anda."})
 		return
 	}
 
-	// 5. Kirim hasil (tetap sama)
+	// 5. Kirim hasil
 	log.Printf("Berhasil membuat ringkasan untuk userID: %s", userID)
-	// === PERBAIKAN: Gunakan 'result' yang dikembalikan service ===
 	c.JSON(http.StatusOK, gin.H{
-		"summary":    result["summary"],
-		"transcript": result["transcript"],
+		"jobId":          job.ID,
+		"summaryId":      summary.ID,
+		"summary":        result.Summary,
+		"transcript":     result.Transcript,
+		"model":          result.Model,
+		"fallbackFrom":   result.FallbackFrom,
+		"promptVersion":  result.PromptVersion,
+		"keyframes":      result.Keyframes,
+		"contentHash":    result.ContentHash,
+		"transcriptFrom": result.TranscriptFrom,
 	})
 }
 
+// formForce membaca field form opsional "force". Respons error sudah
+// ditulis jika ok bernilai false.
+func formForce(c *gin.Context) (force bool, ok bool) {
+	raw := strings.TrimSpace(c.PostForm("force"))
+	if raw == "" {
+		return false, true
+	}
+	force, err := strconv.ParseBool(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Field 'force' harus true atau false"})
+		return false, false
+	}
+	return force, true
+}
+
+// formGlossary membaca field form opsional "glossaryIds" (daftar ID
+// dipisah koma, atau "none" tanpa glosarium tersimpan) dan "glossaryTerms"
+// (istilah tambahan dipisah koma). nil jika keduanya kosong.
+func formGlossary(c *gin.Context) *services.GlossaryOverride {
+	o := services.GlossaryOverride{IDs: splitList(c.PostForm("glossaryIds"))}
+	for _, term := range splitList(c.PostForm("glossaryTerms")) {
+		o.Terms = append(o.Terms, services.GlossaryTerm{Term: term})
+	}
+	if len(o.IDs) == 0 && len(o.Terms) == 0 {
+		return nil
+	}
+	return &o
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// checkGlossary menulis respons error untuk override glosarium yang gagal
+// disusun dan mengembalikan false jika ada error.
+func checkGlossary(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidGlossary):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR: Gagal menyusun glosarium: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyusun glosarium"})
+	}
+	return false
+}
+
+// readSummarizeInput membaca input dari field "text" (transkrip yang
+// ditempel, diringkas tanpa Speech-to-Text) atau file "audioFile". Respons
+// error sudah ditulis jika ok bernilai false.
+func readSummarizeInput(c *gin.Context) (fileName string, fileData []byte, ok bool) {
+	if text := strings.TrimSpace(c.PostForm("text")); text != "" {
+		return "transkrip.txt", []byte(text), true
+	}
+
+	file, err := c.FormFile("audioFile")
+	if err != nil {
+		log.Printf("WARN: Gagal mengambil file dari form: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File 'audioFile' atau field 'text' tidak ditemukan atau request tidak valid"})
+		return "", nil, false
+	}
+
+	openedFile, err := file.Open()
+	if err != nil {
+		log.Printf("ERROR: Gagal membuka file upload: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses file upload (tidak bisa dibuka)"})
+		return "", nil, false
+	}
+	defer openedFile.Close()
+
+	fileData, err = ioutil.ReadAll(openedFile)
+	if err != nil {
+		log.Printf("ERROR: Gagal membaca file upload: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses file upload (tidak bisa dibaca)"})
+		return "", nil, false
+	}
+	return file.Filename, fileData, true
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/api/middleware"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TokenHandler menangani pengelolaan personal access token.
+type TokenHandler struct {
+	service *services.TokenService
+}
+
+// NewTokenHandler membuat instance handler
+func NewTokenHandler(s *services.TokenService) *TokenHandler {
+	return &TokenHandler{service: s}
+}
+
+// CreateTokenRequest adalah body JSON untuk POST /api/tokens
+type CreateTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+// tokenResponse adalah representasi token untuk klien, tanpa hash.
+type tokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Hint       string     `json:"hint"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+func newTokenResponse(t services.AccessToken) tokenResponse {
+	return tokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Scopes:     t.Scopes,
+		Hint:       t.Hint,
+		Active:     t.Active(time.Now()),
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		RevokedAt:  t.RevokedAt,
+	}
+}
+
+// HandleCreateToken menangani POST /api/tokens
+func (h *TokenHandler) HandleCreateToken(c *gin.Context) {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Autentikasi gagal (internal server error)"})
+		return
+	}
+
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("WARN: Gagal bind JSON token: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+	if req.Name == "" || req.ExpiresInDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nama token wajib diisi dan expiresInDays tidak boleh negatif"})
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, plain, err := h.service.CreateToken(principal, req.Name, req.Scopes, ttl)
+	if errors.Is(err, services.ErrInvalidScope) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat token untuk userID %s: %v", principal.UID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat token"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":     plain,
+		"tokenInfo": newTokenResponse(token),
+		"warning":   "Simpan token ini sekarang, token tidak akan ditampilkan lagi.",
+	})
+}
+
+// HandleListTokens menangani GET /api/tokens
+func (h *TokenHandler) HandleListTokens(c *gin.Context) {
+	userID := c.GetString("userID")
+	tokens := h.service.ListTokens(userID)
+	resp := make([]tokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		resp = append(resp, newTokenResponse(t))
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": resp})
+}
+
+// HandleRevokeToken menangani DELETE /api/tokens/:id
+func (h *TokenHandler) HandleRevokeToken(c *gin.Context) {
+	userID := c.GetString("userID")
+	err := h.service.RevokeToken(userID, c.Param("id"))
+	if errors.Is(err, store.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Token tidak ditemukan"})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal mencabut token untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mencabut token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Token berhasil dicabut"})
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"summarize-me-api/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUsageRecords membatasi jumlah catatan yang dikembalikan ke user.
+const maxUsageRecords = 200
+
+// UsageHandler menangani laporan pemakaian dan biaya provider.
+type UsageHandler struct {
+	usage *services.UsageService
+}
+
+// NewUsageHandler membuat instance handler
+func NewUsageHandler(usage *services.UsageService) *UsageHandler {
+	return &UsageHandler{usage: usage}
+}
+
+// HandleMyUsage menangani GET /api/me/usage?month=YYYY-MM atau ?from=&to=.
+// Tanpa parameter berarti bulan berjalan, dikelompokkan per hari.
+func (h *UsageHandler) HandleMyUsage(c *gin.Context) {
+	userID := c.GetString("userID")
+	filter, ok := usagePeriod(c)
+	if !ok {
+		return
+	}
+	filter.UserID = userID
+	report, err := h.usage.Report(filter, services.UsageByDay)
+	if !h.check(c, err) {
+		return
+	}
+	records := h.usage.Records(filter)
+	if len(records) > maxUsageRecords {
+		records = records[:maxUsageRecords]
+	}
+	c.JSON(http.StatusOK, gin.H{"report": report, "records": records})
+}
+
+// HandleReport menangani GET /api/admin/usage?month=&from=&to=&groupBy=day|user|model&userId=
+func (h *UsageHandler) HandleReport(c *gin.Context) {
+	filter, ok := usagePeriod(c)
+	if !ok {
+		return
+	}
+	filter.UserID = c.Query("userId")
+	report, err := h.usage.Report(filter, c.DefaultQuery("groupBy", services.UsageByDay))
+	if !h.check(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *UsageHandler) check(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrInvalidUsageQuery):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Printf("ERROR: Gagal membuat laporan pemakaian: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat laporan pemakaian"})
+	}
+	return false
+}
+
+// usagePeriod membaca periode laporan (UTC): from/to (YYYY-MM-DD atau
+// RFC3339, to inklusif) atau month (YYYY-MM), bawaan bulan berjalan.
+// Respons 400 sudah ditulis jika tidak valid.
+func usagePeriod(c *gin.Context) (services.UsageFilter, bool) {
+	if c.Query("from") != "" || c.Query("to") != "" {
+		from, errFrom := parseDateParam(c.Query("from"), false)
+		to, errTo := parseDateParam(c.Query("to"), true)
+		if errFrom != nil || errTo != nil || from.IsZero() || to.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter from dan to wajib diisi dengan format YYYY-MM-DD atau RFC3339"})
+			return services.UsageFilter{}, false
+		}
+		return services.UsageFilter{From: from, To: to.Add(time.Nanosecond)}, true
+	}
+
+	month := time.Now().UTC()
+	if raw := c.Query("month"); raw != "" {
+		var err error
+		if month, err = time.Parse("2006-01", raw); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter month harus berformat YYYY-MM"})
+			return services.UsageFilter{}, false
+		}
+	}
+	from := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return services.UsageFilter{From: from, To: from.AddDate(0, 1, 0)}, true
+}
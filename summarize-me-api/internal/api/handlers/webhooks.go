@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"summarize-me-api/internal/services"
+	"summarize-me-api/internal/store"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler menangani pendaftaran webhook dan log pengirimannya.
+type WebhookHandler struct {
+	service *services.WebhookService
+}
+
+// NewWebhookHandler membuat instance handler
+func NewWebhookHandler(s *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: s}
+}
+
+// CreateWebhookRequest adalah body JSON untuk POST /api/webhooks
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+// webhookResponse adalah representasi webhook tanpa secret.
+type webhookResponse struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newWebhookResponse(h services.Webhook) webhookResponse {
+	return webhookResponse{ID: h.ID, URL: h.URL, Events: h.Events, CreatedAt: h.CreatedAt}
+}
+
+// HandleCreateWebhook menangani POST /api/webhooks
+func (h *WebhookHandler) HandleCreateWebhook(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Input tidak valid"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	hook, err := h.service.CreateWebhook(c.Request.Context(), userID, req.URL, req.Events)
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Gagal membuat webhook untuk userID %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal membuat webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": newWebhookResponse(hook),
+		"secret":  hook.Secret,
+		"warning": "Simpan secret ini untuk memverifikasi header " + services.SignatureHeader + ", secret tidak akan ditampilkan lagi.",
+	})
+}
+
+// HandleListWebhooks menangani GET /api/webhooks
+func (h *WebhookHandler) HandleListWebhooks(c *gin.Context) {
+	hooks := h.service.ListWebhooks(c.GetString("userID"))
+	resp := make([]webhookResponse, 0, len(hooks))
+	for _, hook := range hooks {
+		resp = append(resp, newWebhookResponse(hook))
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": resp})
+}
+
+// HandleDeleteWebhook menangani DELETE /api/webhooks/:id
+func (h *WebhookHandler) HandleDeleteWebhook(c *gin.Context) {
+	err := h.service.DeleteWebhook(c.GetString("userID"), c.Param("id"))
+	if !h.checkError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook berhasil dihapus"})
+}
+
+// HandleListDeliveries menangani GET /api/webhooks/:id/deliveries?limit=
+func (h *WebhookHandler) HandleListDeliveries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parameter limit harus angka positif"})
+		return
+	}
+	deliveries, err := h.service.ListDeliveries(c.GetString("userID"), c.Param("id"), limit)
+	if !h.checkError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// HandleTestWebhook menangani POST /api/webhooks/:id/test
+func (h *WebhookHandler) HandleTestWebhook(c *gin.Context) {
+	delivery, err := h.service.SendTest(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if !h.checkError(c, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"delivery": delivery})
+}
+
+// checkError menulis respons error dan mengembalikan false jika err tidak nil.
+func (h *WebhookHandler) checkError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, store.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook tidak ditemukan"})
+	default:
+		log.Printf("ERROR: Operasi webhook gagal: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memproses webhook"})
+	}
+	return false
+}
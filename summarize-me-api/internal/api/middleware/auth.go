@@ -1,19 +1,40 @@
 package middleware
 
 import (
-	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"strings"
+	"summarize-me-api/internal/auth"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-gonic/gin"
 )
 
-// FirebaseAuthMiddleware membuat middleware Gin untuk verifikasi token Firebase.
-func FirebaseAuthMiddleware(client *auth.Client) gin.HandlerFunc {
+// PrincipalKey adalah key context Gin tempat *auth.Principal disimpan.
+const PrincipalKey = "principal"
+
+// AuthMiddleware membuat middleware Gin untuk verifikasi bearer token
+// menggunakan Authenticator yang dipilih di konfigurasi.
+//
+// Browser tidak bisa mengirim header Authorization saat membuka WebSocket,
+// jadi khusus request upgrade diterima tiket sekali pakai dari query ticket
+// (lihat LiveHandler.HandleIssueTicket). Bearer token tidak pernah diterima dari query
+// string karena URL tercatat di access log.
+func AuthMiddleware(authenticator auth.Authenticator, tickets *auth.TicketStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if value := c.Query("ticket"); authHeader == "" && value != "" && isWebSocketUpgrade(c.Request) {
+			principal, err := tickets.Redeem(value)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Tiket tidak valid atau expired"})
+				c.Abort()
+				return
+			}
+			c.Set(PrincipalKey, principal)
+			c.Set("userID", principal.UID)
+			c.Next()
+			return
+		}
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header dibutuhkan"})
 			c.Abort()
@@ -27,7 +48,7 @@ func FirebaseAuthMiddleware(client *auth.Client) gin.HandlerFunc {
 			return
 		}
 
-		token, err := client.VerifyIDToken(context.Background(), tokenString)
+		principal, err := authenticator.Authenticate(c.Request.Context(), tokenString)
 		if err != nil {
 			log.Printf("Error verifikasi token: %v\n", err)
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token tidak valid atau expired"})
@@ -35,8 +56,41 @@ func FirebaseAuthMiddleware(client *auth.Client) gin.HandlerFunc {
 			return
 		}
 
-		// Simpan userID di context Gin
-		c.Set("userID", token.UID)
+		// Simpan principal dan userID di context Gin
+		c.Set(PrincipalKey, principal)
+		c.Set("userID", principal.UID)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// Logger mencatat setiap request seperti logger bawaan Gin, tetapi tanpa
+// query string agar tiket atau token yang terlanjur dikirim di URL tidak
+// tersimpan di log.
+func Logger() gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(p gin.LogFormatterParams) string {
+		path, _, _ := strings.Cut(p.Path, "?")
+		return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+			p.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			p.StatusCode,
+			p.Latency,
+			p.ClientIP,
+			p.Method,
+			path,
+			p.ErrorMessage,
+		)
+	})
+}
+
+// GetPrincipal mengambil principal yang diset oleh AuthMiddleware.
+func GetPrincipal(c *gin.Context) (*auth.Principal, bool) {
+	v, exists := c.Get(PrincipalKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := v.(*auth.Principal)
+	return principal, ok
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole menolak request jika principal tidak memiliki salah satu role.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok || !principal.HasRole(roles...) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Anda tidak memiliki akses ke resource ini"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
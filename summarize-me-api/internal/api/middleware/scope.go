@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope menolak request yang principal-nya tidak memiliki scope.
+// Login interaktif (tanpa daftar scope) selalu lolos.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok || !principal.HasScope(scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Token tidak memiliki scope '" + scope + "'"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireInteractive menolak request yang diautentikasi dengan personal
+// access token, mis. untuk mencegah token membuat token baru.
+func RequireInteractive() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := GetPrincipal(c)
+		if !ok || principal.Scopes != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Endpoint ini hanya bisa diakses dari sesi login"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -4,49 +4,167 @@ import (
 	"net/http"
 	"summarize-me-api/internal/api/handlers"
 	"summarize-me-api/internal/api/middleware"
+	"summarize-me-api/internal/auth"
 	"summarize-me-api/internal/config"
+	"summarize-me-api/internal/feedback"
+	"summarize-me-api/internal/health"
+	"summarize-me-api/internal/remote"
+	"summarize-me-api/internal/resilience"
 	"summarize-me-api/internal/services"
 
-	"firebase.google.com/go/v4/auth"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-// Ambil ID Sheet dari environment variable
-const SHEET_ID = "1JYNHzsm_EKBEVT_UFM1doFAWqX_bl6ziNlVtgMkURxo"
-const RANGE = "Sheet1!A:C"
+// Dependencies menampung semua service yang dibutuhkan handler.
+type Dependencies struct {
+	Authenticator   auth.Authenticator
+	HealthChecker   *health.Checker
+	JobRunner       *services.JobRunner
+	TokenService    *services.TokenService
+	UserService     *services.UserService
+	JobService      *services.JobService
+	SummaryService  *services.SummaryService
+	QAService       *services.QAService
+	Regenerate      *services.RegenerateService
+	SearchService   *services.SearchService
+	FullText        *services.FullTextService
+	ActionService   *services.ActionService
+	BatchService    *services.BatchService
+	LiveService     *services.LiveService
+	WebhookService  *services.WebhookService
+	FeedbackSink    feedback.Sink
+	RemoteFetcher   *remote.Fetcher
+	Providers       *resilience.Registry
+	UsageService    *services.UsageService
+	GlossaryService *services.GlossaryService
+}
 
 // SetupRouter mengkonfigurasi dan mengembalikan Gin engine.
-func SetupRouter(cfg *config.Config, authClient *auth.Client, summarizeService *services.SummarizeService) *gin.Engine {
-	r := gin.Default()
-	
+func SetupRouter(cfg *config.Config, deps Dependencies) *gin.Engine {
+	// Logger bawaan Gin mencatat URL lengkap; pakai versi tanpa query string.
+	r := gin.New()
+	r.Use(middleware.Logger(), gin.Recovery())
+
 	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOrigins = []string{"http://localhost:5173", cfg.FrontendURL, "https://summarizemeai.vercel.app"}
-	corsConfig.AllowMethods = []string{"GET", "POST", "OPTIONS"}
-	corsConfig.AllowHeaders = []string{"Authorization", "Content-Type", "Origin"} 
-	corsConfig.AllowCredentials = true 
+	corsConfig.AllowOrigins = cfg.CORSOrigins
+	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	corsConfig.AllowHeaders = []string{"Authorization", "Content-Type", "Origin"}
+	corsConfig.AllowCredentials = true
 	r.Use(cors.New(corsConfig))
 
-
 	// Rute publik untuk health check
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": "pong"})
 	})
+	healthHandler := handlers.NewHealthHandler(deps.HealthChecker)
+	r.GET("/healthz", healthHandler.HandleLiveness)
+	r.GET("/readyz", healthHandler.HandleReadiness)
 
 	// Buat instance handler
-	summarizeHandler := handlers.NewSummarizeHandler(summarizeService)
-	// --- TAMBAHKAN INI ---
-	feedbackHandler := handlers.NewFeedbackHandler(SHEET_ID, RANGE)
+	summarizeHandler := handlers.NewSummarizeHandler(deps.JobRunner, deps.JobService)
+	feedbackHandler := handlers.NewFeedbackHandler(deps.FeedbackSink)
+	tokenHandler := handlers.NewTokenHandler(deps.TokenService)
+	adminHandler := handlers.NewAdminHandler(deps.UserService, deps.JobService, deps.Providers)
+	ratingHandler := handlers.NewRatingHandler(deps.SummaryService)
+	qaHandler := handlers.NewQAHandler(deps.QAService)
+	summaryHandler := handlers.NewSummaryHandler(deps.SummaryService)
+	regenerateHandler := handlers.NewRegenerateHandler(deps.Regenerate, deps.SummaryService)
+	searchHandler := handlers.NewSearchHandler(deps.SearchService, deps.FullText)
+	actionHandler := handlers.NewActionHandler(deps.ActionService)
+	webhookHandler := handlers.NewWebhookHandler(deps.WebhookService)
+	meHandler := handlers.NewMeHandler(deps.UserService)
+	usageHandler := handlers.NewUsageHandler(deps.UsageService)
+	glossaryHandler := handlers.NewGlossaryHandler(deps.GlossaryService)
+	jobHandler := handlers.NewJobHandler(deps.JobService, deps.JobRunner, deps.RemoteFetcher)
+	batchHandler := handlers.NewBatchHandler(deps.BatchService)
+	tickets := auth.NewTicketStore()
+	liveHandler := handlers.NewLiveHandler(deps.LiveService, tickets, cfg.CORSOrigins)
 
+	// Personal access token diterima di samping token dari provider utama,
+	// lalu principal dilengkapi role dari user store.
+	authenticator := deps.UserService.Authenticator(auth.Chain(deps.TokenService, deps.Authenticator))
 
 	// Grup rute API yang memerlukan autentikasi
 	api := r.Group("/api")
-	api.Use(middleware.FirebaseAuthMiddleware(authClient)) // Terapkan middleware auth
+	api.Use(middleware.AuthMiddleware(authenticator, tickets)) // Terapkan middleware auth
 	{
-		api.POST("/summarize", summarizeHandler.HandleSummarize)
-		// --- TAMBAHKAN RUTE INI ---
-		api.POST("/feedback", feedbackHandler.HandleSubmitFeedback) 
+		api.POST("/summarize", middleware.RequireScope(auth.ScopeSummarize), summarizeHandler.HandleSummarize)
+		api.POST("/feedback", middleware.RequireScope(auth.ScopeFeedback), feedbackHandler.HandleSubmitFeedback)
+		api.POST("/summaries/:id/rating", middleware.RequireScope(auth.ScopeFeedback), ratingHandler.HandleRateSummary)
+		api.GET("/summaries/:id", middleware.RequireScope(auth.ScopeReadHistory), summaryHandler.HandleGetSummary)
+		api.PATCH("/summaries/:id", middleware.RequireScope(auth.ScopeSummarize), summaryHandler.HandleUpdateSummary)
+		api.GET("/summary-options", middleware.RequireScope(auth.ScopeReadHistory), regenerateHandler.HandleOptions)
+		api.POST("/summaries/:id/regenerate", middleware.RequireScope(auth.ScopeSummarize), regenerateHandler.HandleRegenerate)
+		api.POST("/summaries/:id/variants/:variantId/activate", middleware.RequireScope(auth.ScopeSummarize), regenerateHandler.HandleActivateVariant)
+		api.GET("/search", middleware.RequireScope(auth.ScopeReadHistory), searchHandler.HandleSearch)
+		api.GET("/search/keyword", middleware.RequireScope(auth.ScopeReadHistory), searchHandler.HandleKeywordSearch)
+		api.POST("/summaries/:id/ask", middleware.RequireScope(auth.ScopeSummarize), qaHandler.HandleAsk)
+		api.GET("/summaries/:id/ask", middleware.RequireScope(auth.ScopeReadHistory), qaHandler.HandleGetConversation)
+		api.DELETE("/summaries/:id/ask", middleware.RequireScope(auth.ScopeSummarize), qaHandler.HandleResetConversation)
+
+		// Ekspor action item ke kalender (ICS) dan issue tracker
+		api.GET("/action-connectors", middleware.RequireScope(auth.ScopeReadHistory), actionHandler.HandleListConnectors)
+		api.GET("/summaries/:id/action-items", middleware.RequireScope(auth.ScopeReadHistory), actionHandler.HandleListItems)
+		api.GET("/summaries/:id/action-items/ics", middleware.RequireScope(auth.ScopeReadHistory), actionHandler.HandleExportICS)
+		api.POST("/summaries/:id/action-items/push", middleware.RequireScope(auth.ScopeSummarize), middleware.RequireRole(auth.RoleAdmin, auth.RoleTracker), actionHandler.HandlePush)
+
+		// Glosarium istilah untuk frasa Speech-to-Text dan ejaan ringkasan
+		api.GET("/glossaries", middleware.RequireScope(auth.ScopeReadHistory), glossaryHandler.HandleListGlossaries)
+		api.POST("/glossaries", middleware.RequireScope(auth.ScopeSummarize), glossaryHandler.HandleCreateGlossary)
+		api.GET("/glossaries/:id", middleware.RequireScope(auth.ScopeReadHistory), glossaryHandler.HandleGetGlossary)
+		api.PUT("/glossaries/:id", middleware.RequireScope(auth.ScopeSummarize), glossaryHandler.HandleUpdateGlossary)
+		api.DELETE("/glossaries/:id", middleware.RequireScope(auth.ScopeSummarize), glossaryHandler.HandleDeleteGlossary)
+
+		api.POST("/jobs", middleware.RequireScope(auth.ScopeSummarize), jobHandler.HandleCreateJob)
+		api.GET("/jobs/:id", middleware.RequireScope(auth.ScopeReadHistory), jobHandler.HandleGetJob)
+
+		// Transkripsi live lewat WebSocket, dibuka dengan tiket sekali pakai
+		api.POST("/live/ticket", middleware.RequireScope(auth.ScopeSummarize), liveHandler.HandleIssueTicket)
+		api.GET("/live", middleware.RequireScope(auth.ScopeSummarize), liveHandler.HandleLive)
+
+		// Batch: banyak file atau archive ZIP, diproses di background
+		api.POST("/batches", middleware.RequireScope(auth.ScopeSummarize), batchHandler.HandleCreateBatch)
+		api.GET("/batches", middleware.RequireScope(auth.ScopeReadHistory), batchHandler.HandleListBatches)
+		api.GET("/batches/:id", middleware.RequireScope(auth.ScopeReadHistory), batchHandler.HandleGetBatch)
+		api.GET("/batches/:id/export", middleware.RequireScope(auth.ScopeReadHistory), batchHandler.HandleExportBatch)
+
+		// Pengelolaan personal access token hanya dari sesi login
+		tokens := api.Group("/tokens", middleware.RequireInteractive())
+		tokens.POST("", tokenHandler.HandleCreateToken)
+		tokens.GET("", tokenHandler.HandleListTokens)
+		tokens.DELETE("/:id", tokenHandler.HandleRevokeToken)
+
+		// Preferensi milik user sendiri
+		me := api.Group("/me", middleware.RequireInteractive())
+		me.GET("/preferences", meHandler.HandleGetPreferences)
+		me.PUT("/preferences", meHandler.HandleUpdatePreferences)
+		me.GET("/usage", usageHandler.HandleMyUsage)
+
+		// Webhook notifikasi job selesai
+		webhooks := api.Group("/webhooks", middleware.RequireInteractive())
+		webhooks.POST("", webhookHandler.HandleCreateWebhook)
+		webhooks.GET("", webhookHandler.HandleListWebhooks)
+		webhooks.DELETE("/:id", webhookHandler.HandleDeleteWebhook)
+		webhooks.GET("/:id/deliveries", webhookHandler.HandleListDeliveries)
+		webhooks.POST("/:id/test", webhookHandler.HandleTestWebhook)
+
+		// Endpoint admin untuk tim support, tidak bisa diakses lewat token
+		admin := api.Group("/admin", middleware.RequireInteractive(), middleware.RequireRole(auth.RoleAdmin, auth.RoleSupport))
+		admin.GET("/users", adminHandler.HandleListUsers)
+		admin.GET("/users/:uid", adminHandler.HandleGetUser)
+		admin.PUT("/users/:uid/roles", middleware.RequireRole(auth.RoleAdmin), adminHandler.HandleSetRoles)
+		admin.GET("/stats", adminHandler.HandleStats)
+		admin.GET("/providers", adminHandler.HandleProviders)
+		admin.GET("/usage", middleware.RequireRole(auth.RoleAdmin), usageHandler.HandleReport)
+		admin.POST("/glossaries", middleware.RequireRole(auth.RoleAdmin), glossaryHandler.HandleCreateShared)
+		admin.PUT("/glossaries/:id", middleware.RequireRole(auth.RoleAdmin), glossaryHandler.HandleUpdateShared)
+		admin.DELETE("/glossaries/:id", middleware.RequireRole(auth.RoleAdmin), glossaryHandler.HandleDeleteShared)
+		admin.GET("/jobs", adminHandler.HandleListJobs)
+		admin.GET("/jobs/:id", adminHandler.HandleGetJob)
+		admin.GET("/feedback", feedbackHandler.HandleListFeedback)
+		admin.GET("/ratings/stats", ratingHandler.HandleRatingStats)
 	}
 
 	return r
-}
\ No newline at end of file
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidToken dikembalikan Authenticator jika token tidak bisa diverifikasi.
+var ErrInvalidToken = errors.New("token tidak valid atau expired")
+
+// Scope yang bisa diberikan ke personal access token.
+const (
+	ScopeSummarize   = "summarize"
+	ScopeReadHistory = "read-history"
+	ScopeFeedback    = "feedback"
+)
+
+// AllScopes adalah daftar semua scope yang valid.
+var AllScopes = []string{ScopeSummarize, ScopeReadHistory, ScopeFeedback}
+
+// Role yang dikenal aplikasi. Setiap user yang terautentikasi otomatis
+// memiliki RoleUser.
+const (
+	RoleUser    = "user"
+	RoleAdmin   = "admin"
+	RoleSupport = "support"
+	// RoleTracker boleh mengirim action item ke issue tracker bersama
+	// (lihat actions.connectors), yang memakai kredensial server.
+	RoleTracker = "tracker"
+)
+
+// AllRoles adalah daftar semua role yang valid.
+var AllRoles = []string{RoleUser, RoleAdmin, RoleSupport, RoleTracker}
+
+// Principal adalah identitas user yang sudah terverifikasi, apa pun provider-nya.
+type Principal struct {
+	UID      string         `json:"uid"`
+	Email    string         `json:"email,omitempty"`
+	Provider string         `json:"provider"`
+	Claims   map[string]any `json:"-"`
+	// EmailVerified bernilai true hanya jika provider menjamin pemilik akun
+	// menguasai alamat Email (claim "email_verified" atau konfigurasi statis).
+	EmailVerified bool `json:"emailVerified"`
+	// Scopes nil berarti akses penuh (login interaktif). Personal access
+	// token selalu membawa daftar scope eksplisit.
+	Scopes []string `json:"scopes,omitempty"`
+	Roles  []string `json:"roles,omitempty"`
+}
+
+// HasRole mengecek apakah principal memiliki salah satu role yang diminta.
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, want := range roles {
+		if want == RoleUser {
+			return true
+		}
+		for _, have := range p.Roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope mengecek apakah principal boleh memakai scope tertentu.
+func (p *Principal) HasScope(scope string) bool {
+	if p.Scopes == nil {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticator memverifikasi bearer token dan mengembalikan Principal.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+// RolesFromClaims membaca role dari custom claims. Mendukung claim "role"
+// (string), "roles" (array) dan "realm_access.roles" milik Keycloak.
+func RolesFromClaims(claims map[string]any) []string {
+	var roles []string
+	if role := claimString(claims, "role"); role != "" {
+		roles = append(roles, role)
+	}
+	roles = append(roles, claimStrings(claims, "roles")...)
+	if realm, ok := claims["realm_access"].(map[string]any); ok {
+		roles = append(roles, claimStrings(realm, "roles")...)
+	}
+	return roles
+}
+
+func claimStrings(claims map[string]any, key string) []string {
+	list, ok := claims[key].([]any)
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// claimBool mengambil claim bertipe bool, false jika tidak ada.
+func claimBool(claims map[string]any, key string) bool {
+	v, _ := claims[key].(bool)
+	return v
+}
+
+// claimString mengambil claim bertipe string, kosong jika tidak ada.
+func claimString(claims map[string]any, key string) string {
+	if v, ok := claims[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+// chain mencoba beberapa Authenticator berurutan.
+type chain []Authenticator
+
+// Chain menggabungkan beberapa Authenticator. Token diterima oleh
+// Authenticator pertama yang berhasil memverifikasinya.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return chain(authenticators)
+}
+
+// Authenticate mengembalikan error dari Authenticator terakhir jika semua gagal.
+func (c chain) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	err := ErrInvalidToken
+	for _, a := range c {
+		var principal *Principal
+		if principal, err = a.Authenticate(ctx, token); err == nil {
+			return principal, nil
+		}
+	}
+	return nil, err
+}
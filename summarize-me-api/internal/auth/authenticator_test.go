@@ -0,0 +1,33 @@
+package auth
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestRolesFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims string
+		want   []string
+	}{
+		{"tanpa role", `{"sub":"u1"}`, nil},
+		{"role tunggal", `{"role":"admin"}`, []string{"admin"}},
+		{"array roles", `{"roles":["support","tracker"]}`, []string{"support", "tracker"}},
+		{"keycloak realm_access", `{"realm_access":{"roles":["admin","offline_access"]}}`, []string{"admin", "offline_access"}},
+		{"semua sumber", `{"role":"admin","roles":["support"],"realm_access":{"roles":["tracker"]}}`, []string{"admin", "support", "tracker"}},
+		{"tipe salah diabaikan", `{"role":["admin"],"roles":"support","realm_access":{"roles":[1,"tracker"]}}`, []string{"tracker"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims map[string]any
+			if err := json.Unmarshal([]byte(tt.claims), &claims); err != nil {
+				t.Fatal(err)
+			}
+			if got := RolesFromClaims(claims); !slices.Equal(got, tt.want) {
+				t.Errorf("RolesFromClaims = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
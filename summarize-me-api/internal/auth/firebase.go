@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	firebaseauth "firebase.google.com/go/v4/auth"
+)
+
+// FirebaseAuthenticator memverifikasi Firebase ID token.
+type FirebaseAuthenticator struct {
+	client *firebaseauth.Client
+}
+
+// NewFirebaseAuthenticator membuat Authenticator berbasis Firebase Auth.
+func NewFirebaseAuthenticator(client *firebaseauth.Client) *FirebaseAuthenticator {
+	return &FirebaseAuthenticator{client: client}
+}
+
+// Authenticate memverifikasi ID token ke Firebase.
+func (a *FirebaseAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	idToken, err := a.client.VerifyIDToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return &Principal{
+		UID:      idToken.UID,
+		Email:    claimString(idToken.Claims, "email"),
+		Provider: "firebase",
+		Claims:   idToken.Claims,
+		Roles:    RolesFromClaims(idToken.Claims),
+
+		EmailVerified: claimBool(idToken.Claims, "email_verified"),
+	}, nil
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// OIDCOptions mengatur verifikasi JWT dari provider OIDC (mis. Keycloak).
+type OIDCOptions struct {
+	// Issuer wajib diisi dan wajib cocok dengan claim "iss". Jika JWKSURL
+	// dan JWKSFile kosong, lokasi JWKS dicari lewat
+	// <Issuer>/.well-known/openid-configuration.
+	Issuer string
+	// Audience, jika diisi, wajib ada di claim "aud".
+	Audience string
+	JWKSURL  string
+	// JWKSFile berisi JWKS lokal, berguna untuk development tanpa IdP.
+	JWKSFile   string
+	UIDClaim   string
+	EmailClaim string
+}
+
+// OIDCAuthenticator memverifikasi JWT bertanda tangan dengan kunci dari JWKS.
+type OIDCAuthenticator struct {
+	opts OIDCOptions
+	jwks *keyfunc.JWKS
+}
+
+// NewOIDCAuthenticator memuat JWKS dan membuat Authenticator OIDC.
+func NewOIDCAuthenticator(ctx context.Context, opts OIDCOptions) (*OIDCAuthenticator, error) {
+	if opts.Issuer == "" {
+		return nil, errors.New("issuer wajib diisi untuk provider oidc")
+	}
+	if opts.UIDClaim == "" {
+		opts.UIDClaim = "sub"
+	}
+	if opts.EmailClaim == "" {
+		opts.EmailClaim = "email"
+	}
+
+	var jwks *keyfunc.JWKS
+	var err error
+	switch {
+	case opts.JWKSFile != "":
+		data, readErr := os.ReadFile(opts.JWKSFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("gagal membaca file JWKS %s: %w", opts.JWKSFile, readErr)
+		}
+		jwks, err = keyfunc.NewJSON(data)
+	default:
+		jwksURL := opts.JWKSURL
+		if jwksURL == "" {
+			if jwksURL, err = discoverJWKSURL(ctx, opts.Issuer); err != nil {
+				return nil, err
+			}
+		}
+		jwks, err = keyfunc.Get(jwksURL, keyfunc.Options{
+			Ctx:               ctx,
+			RefreshInterval:   time.Hour,
+			RefreshRateLimit:  time.Minute,
+			RefreshTimeout:    10 * time.Second,
+			RefreshUnknownKID: true,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat JWKS: %w", err)
+	}
+	return &OIDCAuthenticator{opts: opts, jwks: jwks}, nil
+}
+
+// discoverJWKSURL membaca jwks_uri dari dokumen discovery OIDC.
+func discoverJWKSURL(ctx context.Context, issuer string) (string, error) {
+	discoveryURL := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gagal mengambil dokumen discovery OIDC: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("dokumen discovery OIDC mengembalikan status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", fmt.Errorf("gagal parse dokumen discovery OIDC: %w", err)
+	}
+	if doc.JWKSURI == "" {
+		return "", errors.New("dokumen discovery OIDC tidak memuat jwks_uri")
+	}
+	return doc.JWKSURI, nil
+}
+
+// Authenticate memverifikasi tanda tangan, masa berlaku, issuer dan audience JWT.
+func (a *OIDCAuthenticator) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(a.validMethods()))
+	parsed, err := parser.ParseWithClaims(token, claims, a.jwks.Keyfunc)
+	if err != nil || !parsed.Valid {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	// MapClaims.Valid melewatkan exp yang tidak ada; token tanpa masa
+	// berlaku tidak diterima.
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("%w: claim exp tidak ada", ErrInvalidToken)
+	}
+	if !claims.VerifyIssuer(a.opts.Issuer, true) {
+		return nil, fmt.Errorf("%w: issuer tidak cocok", ErrInvalidToken)
+	}
+	if a.opts.Audience != "" && !claims.VerifyAudience(a.opts.Audience, true) {
+		return nil, fmt.Errorf("%w: audience tidak cocok", ErrInvalidToken)
+	}
+
+	uid := claimString(claims, a.opts.UIDClaim)
+	if uid == "" {
+		return nil, fmt.Errorf("%w: claim %s kosong", ErrInvalidToken, a.opts.UIDClaim)
+	}
+	return &Principal{
+		UID:      uid,
+		Email:    claimString(claims, a.opts.EmailClaim),
+		Provider: "oidc",
+		Claims:   claims,
+		Roles:    RolesFromClaims(claims),
+
+		EmailVerified: claimBool(claims, "email_verified"),
+	}, nil
+}
+
+// validMethods mengembalikan algoritma RS dan ES yang sesuai dengan jenis
+// kunci di JWKS saat ini. Algoritma lain (HS, none) selalu ditolak agar
+// kunci publik atau kunci "oct" di JWKS tidak bisa dipakai sebagai secret
+// HMAC.
+func (a *OIDCAuthenticator) validMethods() []string {
+	var rsaKey, ecKey bool
+	for _, key := range a.jwks.ReadOnlyKeys() {
+		switch key.(type) {
+		case *rsa.PublicKey:
+			rsaKey = true
+		case *ecdsa.PublicKey:
+			ecKey = true
+		}
+	}
+	// Slice kosong (bukan nil) agar parser menolak semua algoritma jika
+	// JWKS tidak memuat kunci RSA atau EC.
+	methods := []string{}
+	if rsaKey {
+		methods = append(methods, "RS256", "RS384", "RS512")
+	}
+	if ecKey {
+		methods = append(methods, "ES256", "ES384", "ES512")
+	}
+	return methods
+}
+
+// Close menghentikan goroutine refresh JWKS.
+func (a *OIDCAuthenticator) Close() {
+	a.jwks.EndBackground()
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testIssuer   = "https://idp.example.com/realms/summarize"
+	testAudience = "summarize-api"
+	// testOctSecret adalah kunci "oct" di JWKS uji; token HS256 yang
+	// ditandatangani dengannya tetap harus ditolak.
+	testOctSecret = "rahasia-bersama-yang-panjang"
+)
+
+// newTestOIDC membuat OIDCAuthenticator dengan JWKS lokal berisi kunci RSA
+// "rsa-1" dan kunci "oct-1".
+func newTestOIDC(t *testing.T) (*OIDCAuthenticator, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256", "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())},
+		{"kty": "oct", "kid": "oct-1", "k": b64([]byte(testOctSecret))},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := NewOIDCAuthenticator(context.Background(), OIDCOptions{Issuer: testIssuer, Audience: testAudience, JWKSFile: path})
+	if err != nil {
+		t.Fatalf("NewOIDCAuthenticator: %v", err)
+	}
+	t.Cleanup(a.Close)
+	return a, key
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            testIssuer,
+		"aud":            testAudience,
+		"sub":            "user-1",
+		"email":          "budi@acme.id",
+		"email_verified": true,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"realm_access":   map[string]any{"roles": []string{"support"}},
+	}
+}
+
+func TestOIDCAuthenticate(t *testing.T) {
+	a, key := newTestOIDC(t)
+
+	principal, err := a.Authenticate(context.Background(), signToken(t, jwt.SigningMethodRS256, key, "rsa-1", validClaims()))
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if principal.UID != "user-1" || principal.Email != "budi@acme.id" || !principal.EmailVerified || principal.Provider != "oidc" {
+		t.Errorf("principal = %+v", principal)
+	}
+	if !slices.Equal(principal.Roles, []string{RoleSupport}) {
+		t.Errorf("roles = %v, want [%s]", principal.Roles, RoleSupport)
+	}
+}
+
+func TestOIDCAuthenticateRejects(t *testing.T) {
+	a, key := newTestOIDC(t)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+
+	with := func(mutate func(c jwt.MapClaims)) jwt.MapClaims {
+		c := validClaims()
+		mutate(c)
+		return c
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"issuer lain", signToken(t, jwt.SigningMethodRS256, key, "rsa-1", with(func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }))},
+		{"tanpa issuer", signToken(t, jwt.SigningMethodRS256, key, "rsa-1", with(func(c jwt.MapClaims) { delete(c, "iss") }))},
+		{"audience lain", signToken(t, jwt.SigningMethodRS256, key, "rsa-1", with(func(c jwt.MapClaims) { c["aud"] = "aplikasi-lain" }))},
+		{"kedaluwarsa", signToken(t, jwt.SigningMethodRS256, key, "rsa-1", with(func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }))},
+		{"tanpa exp", signToken(t, jwt.SigningMethodRS256, key, "rsa-1", with(func(c jwt.MapClaims) { delete(c, "exp") }))},
+		{"kunci lain", signToken(t, jwt.SigningMethodRS256, otherKey, "rsa-1", validClaims())},
+		{"alg none", signToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "rsa-1", validClaims())},
+		{"HS256 dengan kunci publik RSA", signToken(t, jwt.SigningMethodHS256, pubPEM, "rsa-1", validClaims())},
+		{"HS256 dengan kunci oct di JWKS", signToken(t, jwt.SigningMethodHS256, []byte(testOctSecret), "oct-1", validClaims())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := a.Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Authenticate = %+v, %v; want ErrInvalidToken", principal, err)
+			}
+		})
+	}
+}
+
+func TestNewOIDCAuthenticatorRequiresIssuer(t *testing.T) {
+	if _, err := NewOIDCAuthenticator(context.Background(), OIDCOptions{JWKSFile: "jwks.json"}); err == nil {
+		t.Fatal("NewOIDCAuthenticator tanpa issuer seharusnya error")
+	}
+}
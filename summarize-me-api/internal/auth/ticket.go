@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// TicketTTL adalah masa berlaku tiket WebSocket sejak diterbitkan.
+const TicketTTL = 30 * time.Second
+
+// TicketStore menerbitkan tiket sekali pakai untuk membuka WebSocket.
+// Browser tidak bisa mengirim header Authorization saat upgrade, jadi klien
+// menukar bearer token-nya dengan tiket lewat POST biasa lalu menaruh tiket
+// di query string. Tiket yang bocor ke access log sudah tidak berguna.
+type TicketStore struct {
+	mu      sync.Mutex
+	tickets map[string]ticket
+}
+
+type ticket struct {
+	principal Principal
+	expiresAt time.Time
+}
+
+// NewTicketStore membuat TicketStore kosong. Tiket hanya disimpan di memori.
+func NewTicketStore() *TicketStore {
+	return &TicketStore{tickets: make(map[string]ticket)}
+}
+
+// Issue menerbitkan tiket baru untuk principal.
+func (s *TicketStore) Issue(principal *Principal) (string, time.Time, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	value := base64.RawURLEncoding.EncodeToString(raw)
+	now := time.Now()
+	expiresAt := now.Add(TicketTTL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, t := range s.tickets {
+		if now.After(t.expiresAt) {
+			delete(s.tickets, k)
+		}
+	}
+	s.tickets[value] = ticket{principal: *principal, expiresAt: expiresAt}
+	return value, expiresAt, nil
+}
+
+// Redeem menukar tiket dengan principal-nya. Tiket langsung dihapus, jadi
+// hanya bisa dipakai sekali.
+func (s *TicketStore) Redeem(value string) (*Principal, error) {
+	s.mu.Lock()
+	t, ok := s.tickets[value]
+	delete(s.tickets, value)
+	s.mu.Unlock()
+	if !ok || time.Now().After(t.expiresAt) {
+		return nil, ErrInvalidToken
+	}
+	principal := t.principal
+	return &principal, nil
+}
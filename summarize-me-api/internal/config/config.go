@@ -1,45 +1,816 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Config menampung semua variabel konfigurasi aplikasi.
+//
+// Nilai dimuat berurutan dari: default bawaan, file YAML, environment
+// variables, lalu flag CLI. Sumber yang belakangan menimpa sumber sebelumnya.
 type Config struct {
-	Port           string
-	FirebaseProjectID string
-	GeminiAPIKey   string
-	FrontendURL    string
+	Port              string   `yaml:"port"`
+	FirebaseProjectID string   `yaml:"firebaseProjectId"`
+	GeminiAPIKey      string   `yaml:"geminiApiKey"`
+	FrontendURL       string   `yaml:"frontendUrl"`
+	GCSBucketName     string   `yaml:"gcsBucketName"`
+	CORSOrigins       []string `yaml:"corsOrigins"`
+
+	Gemini   GeminiConfig   `yaml:"gemini"`
+	Speech   SpeechConfig   `yaml:"speech"`
+	Feedback FeedbackConfig `yaml:"feedback"`
+	Health   HealthConfig   `yaml:"health"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Webhooks WebhooksConfig `yaml:"webhooks"`
+	Email    EmailConfig    `yaml:"email"`
+	Jobs     JobsConfig     `yaml:"jobs"`
+	Remote   RemoteConfig   `yaml:"remote"`
+	Media    MediaConfig    `yaml:"media"`
+	Live     LiveConfig     `yaml:"live"`
+	Search   SearchConfig   `yaml:"search"`
+	Actions  ActionsConfig  `yaml:"actions"`
+
+	Resilience ResilienceConfig `yaml:"resilience"`
+	Usage      UsageConfig      `yaml:"usage"`
+}
+
+// GeminiConfig mengatur model yang dipakai untuk peringkasan.
+type GeminiConfig struct {
+	Model string `yaml:"model"`
+	// Fallbacks dicoba berurutan jika model awal gagal (kuota habis,
+	// konten diblokir, provider down).
+	Fallbacks []string `yaml:"fallbacks"`
+	// Routes memilih model awal berdasarkan panjang transkrip dan opsi
+	// ringkasan; yang pertama cocok dipakai, tanpa yang cocok berarti Model.
+	Routes []ModelRouteConfig `yaml:"routes"`
+	// AllowedModels adalah model lain yang boleh dipilih user saat membuat
+	// ulang ringkasan. Model utama selalu diizinkan.
+	AllowedModels []string `yaml:"allowedModels"`
+}
+
+// SpeechConfig mengatur parameter Speech-to-Text.
+type SpeechConfig struct {
+	LanguageCode    string `yaml:"languageCode"`
+	SampleRateHertz int    `yaml:"sampleRateHertz"`
+}
+
+// Feedback sink yang didukung.
+const (
+	FeedbackSinkSheets    = "sheets"
+	FeedbackSinkFirestore = "firestore"
+	FeedbackSinkNDJSON    = "ndjson"
+	FeedbackSinkWebhook   = "webhook"
+)
+
+// FeedbackConfig menentukan tujuan penyimpanan feedback. Feedback dikirim
+// ke semua sink; listing admin membaca dari sink pertama yang mendukungnya.
+type FeedbackConfig struct {
+	Sinks               []string `yaml:"sinks"`
+	SheetID             string   `yaml:"sheetId"`
+	SheetRange          string   `yaml:"sheetRange"`
+	FirestoreCollection string   `yaml:"firestoreCollection"`
+	// NDJSONPath kosong berarti <dataDir>/feedback.ndjson.
+	NDJSONPath string `yaml:"ndjsonPath"`
+	WebhookURL string `yaml:"webhookUrl"`
+}
+
+// HealthConfig mengatur probe dependensi untuk endpoint /readyz.
+type HealthConfig struct {
+	ProbeTimeout time.Duration `yaml:"probeTimeout"`
+	CacheTTL     time.Duration `yaml:"cacheTTL"`
+}
+
+// StorageConfig mengatur penyimpanan data lokal server (token, metadata, dll).
+type StorageConfig struct {
+	// DataDir adalah direktori file JSON. Kosong berarti hanya di memori.
+	DataDir string `yaml:"dataDir"`
+}
+
+// ModelRouteConfig adalah satu aturan routing model. Kondisi kosong atau 0
+// diabaikan. Urutan field sama dengan services.ModelRoute.
+type ModelRouteConfig struct {
+	Model     string   `yaml:"model"`
+	MinChars  int      `yaml:"minChars"`
+	MaxChars  int      `yaml:"maxChars"`
+	Lengths   []string `yaml:"lengths"`
+	Templates []string `yaml:"templates"`
+}
+
+// WebhooksConfig mengatur pengiriman webhook keluar.
+type WebhooksConfig struct {
+	MaxAttempts    int           `yaml:"maxAttempts"`
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`
+	Timeout        time.Duration `yaml:"timeout"`
+	// AllowPrivate mengizinkan URL webhook ke alamat internal. Hanya untuk development.
+	AllowPrivate bool `yaml:"allowPrivate"`
+}
+
+// ResilienceConfig mengatur retry, timeout per tahap dan circuit breaker
+// untuk pemanggilan Gemini, Speech-to-Text dan GCS.
+type ResilienceConfig struct {
+	Gemini  RetryPolicyConfig `yaml:"gemini"`
+	Speech  RetryPolicyConfig `yaml:"speech"`
+	Storage RetryPolicyConfig `yaml:"storage"`
+	// BreakerThreshold adalah jumlah kegagalan sementara berturut-turut
+	// yang membuka circuit breaker satu provider. 0 mematikan breaker.
+	BreakerThreshold int           `yaml:"breakerThreshold"`
+	BreakerCooldown  time.Duration `yaml:"breakerCooldown"`
+}
+
+// RetryPolicyConfig adalah kebijakan retry satu provider. Urutan field sama
+// dengan resilience.Policy agar bisa dikonversi langsung.
+type RetryPolicyConfig struct {
+	MaxAttempts    int           `yaml:"maxAttempts"`
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`
+	// Timeout membatasi satu percobaan, bukan total semua retry. Untuk
+	// speech, Timeout juga membatasi total penantian operasi transkripsi.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+func (p RetryPolicyConfig) valid() bool {
+	return p.MaxAttempts >= 1 && p.InitialBackoff > 0 && p.MaxBackoff >= p.InitialBackoff && p.Timeout > 0
+}
+
+// UsageConfig adalah tabel harga untuk menghitung biaya pemakaian per job.
+// Harga hanya bisa diatur lewat YAML.
+type UsageConfig struct {
+	Currency        string  `yaml:"currency"`
+	SpeechPerMinute float64 `yaml:"speechPerMinute"`
+	// StoragePerGB dikenakan atas ukuran file yang diupload ke GCS.
+	StoragePerGB float64            `yaml:"storagePerGB"`
+	Models       []ModelPriceConfig `yaml:"models"`
+}
+
+// ModelPriceConfig adalah harga token satu model per satu juta token.
+// Urutan field sama dengan services.ModelPrice.
+type ModelPriceConfig struct {
+	Model            string  `yaml:"model"`
+	InputPerMillion  float64 `yaml:"inputPerMillion"`
+	OutputPerMillion float64 `yaml:"outputPerMillion"`
+}
+
+// JobsConfig mengatur antrean job background dan batas upload batch.
+type JobsConfig struct {
+	Workers       int `yaml:"workers"`
+	QueueSize     int `yaml:"queueSize"`
+	MaxInputFiles int `yaml:"maxInputFiles"`
+	MaxBatchMB    int `yaml:"maxBatchMB"`
+}
+
+// RemoteConfig mengatur unduhan audio dari URL (POST /api/jobs).
+type RemoteConfig struct {
+	MaxDownloadMB int           `yaml:"maxDownloadMB"`
+	Timeout       time.Duration `yaml:"timeout"`
+	// AllowPrivate mengizinkan URL ke alamat internal. Hanya untuk development.
+	AllowPrivate bool `yaml:"allowPrivate"`
+}
+
+// MediaConfig mengatur ekstraksi audio dari file video dengan ffmpeg.
+type MediaConfig struct {
+	FFmpegPath  string `yaml:"ffmpegPath"`
+	FFprobePath string `yaml:"ffprobePath"`
+	// Keyframes mencatat timestamp keyframe video di hasil ringkasan. Timestamp
+	// belum dipetakan ke bagian ringkasan, lihat services.SummarizeResult.
+	Keyframes        bool          `yaml:"keyframes"`
+	KeyframeInterval time.Duration `yaml:"keyframeInterval"`
+}
+
+// LiveConfig mengatur transkripsi live lewat WebSocket (/api/live).
+type LiveConfig struct {
+	SummaryInterval time.Duration `yaml:"summaryInterval"`
+	MaxDuration     time.Duration `yaml:"maxDuration"`
+}
+
+// Embedder yang didukung untuk pencarian semantik.
+const (
+	EmbedderGemini = "gemini"
+	EmbedderHTTP   = "http"
+)
+
+// SearchConfig mengatur pencarian semantik (/api/search). Embedder kosong
+// berarti pencarian dimatikan.
+type SearchConfig struct {
+	Embedder string `yaml:"embedder"`
+	Model    string `yaml:"model"`
+	// URL dan APIKey hanya untuk embedder http (server model lokal yang
+	// kompatibel dengan /v1/embeddings OpenAI).
+	URL     string        `yaml:"url"`
+	APIKey  string        `yaml:"apiKey"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Connector action item yang didukung.
+const (
+	ActionConnectorGitHub  = "github"
+	ActionConnectorJira    = "jira"
+	ActionConnectorWebhook = "webhook"
+)
+
+// ActionsConfig mengatur ekspor action item ke issue tracker.
+type ActionsConfig struct {
+	// LinkURL adalah tautan ke ringkasan yang ikut dikirim ke tracker, dengan
+	// "{id}" diganti ID ringkasan. Kosong berarti tanpa tautan.
+	LinkURL string `yaml:"linkUrl"`
+	// Connectors hanya bisa diatur lewat file YAML.
+	Connectors []ActionConnectorConfig `yaml:"connectors"`
+}
+
+// ActionConnectorConfig adalah satu tujuan push action item.
+type ActionConnectorConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	// URL adalah base URL API (GitHub Enterprise, Jira) atau URL webhook.
+	URL string `yaml:"url"`
+	// Project adalah "owner/repo" untuk GitHub atau project key untuk Jira.
+	Project   string `yaml:"project"`
+	IssueType string `yaml:"issueType"`
+	Username  string `yaml:"username"`
+	Token     string `yaml:"token"`
+	// TokenEnv adalah nama env yang berisi token, agar token tidak perlu
+	// ditulis di file konfigurasi. Dipakai jika Token kosong.
+	TokenEnv string   `yaml:"tokenEnv"`
+	Secret   string   `yaml:"secret"`
+	Labels   []string `yaml:"labels"`
+	// Fields adalah template text/template per field tujuan.
+	Fields map[string]string `yaml:"fields"`
+	// Assignees memetakan nama pembicara ke akun di tracker.
+	Assignees map[string]string `yaml:"assignees"`
+	Timeout   time.Duration     `yaml:"timeout"`
+}
+
+// EmailConfig mengatur pengiriman email lewat SMTP. SMTPHost kosong berarti
+// notifikasi email dimatikan.
+type EmailConfig struct {
+	SMTPHost string `yaml:"smtpHost"`
+	SMTPPort int    `yaml:"smtpPort"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// Provider autentikasi yang didukung.
+const (
+	AuthProviderFirebase = "firebase"
+	AuthProviderOIDC     = "oidc"
+	AuthProviderStatic   = "static"
+)
+
+// AuthConfig memilih provider autentikasi untuk rute /api.
+type AuthConfig struct {
+	Provider     string              `yaml:"provider"`
+	OIDC         OIDCConfig          `yaml:"oidc"`
+	StaticTokens []StaticTokenConfig `yaml:"staticTokens"`
+	// Admins berisi UID atau email yang selalu mendapat role admin.
+	Admins []string `yaml:"admins"`
+}
+
+// OIDCConfig mengatur verifikasi JWT dari IdP OIDC seperti Keycloak.
+type OIDCConfig struct {
+	// Issuer wajib diisi; claim "iss" setiap token harus cocok.
+	Issuer     string `yaml:"issuer"`
+	Audience   string `yaml:"audience"`
+	JWKSURL    string `yaml:"jwksUrl"`
+	JWKSFile   string `yaml:"jwksFile"`
+	UIDClaim   string `yaml:"uidClaim"`
+	EmailClaim string `yaml:"emailClaim"`
+}
+
+// StaticTokenConfig adalah satu token tetap untuk provider static.
+type StaticTokenConfig struct {
+	Token string `yaml:"token"`
+	UID   string `yaml:"uid"`
+	Email string `yaml:"email"`
+}
+
+// Default mengembalikan konfigurasi bawaan sebelum sumber lain diterapkan.
+func Default() *Config {
+	return &Config{
+		Port:          "8080",
+		FrontendURL:   "http://localhost:5173",
+		GCSBucketName: "summarizeme_bucket",
+		CORSOrigins:   []string{"http://localhost:5173", "https://summarizemeai.vercel.app"},
+		Gemini: GeminiConfig{
+			Model:         "gemini-2.5-flash",
+			AllowedModels: []string{"gemini-2.5-pro"},
+			Fallbacks:     []string{"gemini-2.5-flash-lite"},
+		},
+		Speech: SpeechConfig{
+			LanguageCode:    "id-ID",
+			SampleRateHertz: 16000,
+		},
+		Feedback: FeedbackConfig{
+			Sinks:               []string{FeedbackSinkNDJSON, FeedbackSinkSheets},
+			SheetID:             "1JYNHzsm_EKBEVT_UFM1doFAWqX_bl6ziNlVtgMkURxo",
+			SheetRange:          "Sheet1!A:C",
+			FirestoreCollection: "project_feedback",
+		},
+		Health: HealthConfig{
+			ProbeTimeout: 3 * time.Second,
+			CacheTTL:     10 * time.Second,
+		},
+		Storage: StorageConfig{
+			DataDir: "data",
+		},
+		Webhooks: WebhooksConfig{
+			MaxAttempts:    6,
+			InitialBackoff: 5 * time.Second,
+			MaxBackoff:     10 * time.Minute,
+			Timeout:        10 * time.Second,
+		},
+		Resilience: ResilienceConfig{
+			Gemini: RetryPolicyConfig{
+				MaxAttempts:    4,
+				InitialBackoff: 2 * time.Second,
+				MaxBackoff:     30 * time.Second,
+				Timeout:        3 * time.Minute,
+			},
+			Speech: RetryPolicyConfig{
+				MaxAttempts:    3,
+				InitialBackoff: 5 * time.Second,
+				MaxBackoff:     time.Minute,
+				Timeout:        30 * time.Minute,
+			},
+			Storage: RetryPolicyConfig{
+				MaxAttempts:    3,
+				InitialBackoff: time.Second,
+				MaxBackoff:     10 * time.Second,
+				Timeout:        2 * time.Minute,
+			},
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+		},
+		Usage: UsageConfig{
+			Currency:        "USD",
+			SpeechPerMinute: 0.024,
+			StoragePerGB:    0.02,
+			Models: []ModelPriceConfig{
+				{Model: "gemini-2.5-flash", InputPerMillion: 0.30, OutputPerMillion: 2.50},
+				{Model: "gemini-2.5-flash-lite", InputPerMillion: 0.10, OutputPerMillion: 0.40},
+				{Model: "gemini-2.5-pro", InputPerMillion: 1.25, OutputPerMillion: 10.00},
+			},
+		},
+		Jobs: JobsConfig{
+			Workers:       2,
+			QueueSize:     200,
+			MaxInputFiles: 50,
+			MaxBatchMB:    500,
+		},
+		Remote: RemoteConfig{
+			MaxDownloadMB: 300,
+			Timeout:       10 * time.Minute,
+		},
+		Media: MediaConfig{
+			FFmpegPath:       "ffmpeg",
+			FFprobePath:      "ffprobe",
+			KeyframeInterval: 30 * time.Second,
+		},
+		Live: LiveConfig{
+			SummaryInterval: 3 * time.Minute,
+			MaxDuration:     2 * time.Hour,
+		},
+		Search: SearchConfig{
+			Embedder: EmbedderGemini,
+			Model:    "text-embedding-004",
+			Timeout:  30 * time.Second,
+		},
+		Email: EmailConfig{
+			SMTPPort: 587,
+			From:     "SummarizeMe <no-reply@summarizeme.local>",
+		},
+		Auth: AuthConfig{
+			Provider: AuthProviderFirebase,
+			OIDC: OIDCConfig{
+				UIDClaim:   "sub",
+				EmailClaim: "email",
+			},
+		},
+	}
+}
+
+// setting memetakan satu field Config ke nama flag dan environment variable-nya.
+type setting struct {
+	flag   string
+	env    string
+	usage  string
+	secret bool
+	ptr    func(c *Config) any
 }
 
-// LoadConfig memuat konfigurasi dari environment variables.
-func LoadConfig() *Config {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" // Default port jika tidak diset
+var settings = []setting{
+	{flag: "port", env: "PORT", usage: "port HTTP server", ptr: func(c *Config) any { return &c.Port }},
+	{flag: "firebase-project-id", env: "FIREBASE_PROJECT_ID", usage: "ID project Firebase", ptr: func(c *Config) any { return &c.FirebaseProjectID }},
+	{flag: "gemini-api-key", env: "GEMINI_API_KEY", usage: "API key Gemini", secret: true, ptr: func(c *Config) any { return &c.GeminiAPIKey }},
+	{flag: "frontend-url", env: "FRONTEND_URL", usage: "URL frontend (otomatis masuk daftar CORS)", ptr: func(c *Config) any { return &c.FrontendURL }},
+	{flag: "gcs-bucket", env: "GCS_BUCKET_NAME", usage: "nama GCS bucket untuk upload audio sementara", ptr: func(c *Config) any { return &c.GCSBucketName }},
+	{flag: "cors-origins", env: "CORS_ORIGINS", usage: "daftar origin CORS, dipisah koma", ptr: func(c *Config) any { return &c.CORSOrigins }},
+	{flag: "gemini-model", env: "GEMINI_MODEL", usage: "nama model Gemini", ptr: func(c *Config) any { return &c.Gemini.Model }},
+	{flag: "gemini-allowed-models", env: "GEMINI_ALLOWED_MODELS", usage: "model lain yang boleh dipilih saat membuat ulang ringkasan, dipisah koma", ptr: func(c *Config) any { return &c.Gemini.AllowedModels }},
+	{flag: "gemini-fallback-models", env: "GEMINI_FALLBACK_MODELS", usage: "model cadangan jika model utama gagal, berurutan dan dipisah koma", ptr: func(c *Config) any { return &c.Gemini.Fallbacks }},
+	{flag: "speech-language", env: "SPEECH_LANGUAGE_CODE", usage: "kode bahasa Speech-to-Text", ptr: func(c *Config) any { return &c.Speech.LanguageCode }},
+	{flag: "speech-sample-rate", env: "SPEECH_SAMPLE_RATE_HERTZ", usage: "sample rate audio (Hz), 0 untuk deteksi otomatis", ptr: func(c *Config) any { return &c.Speech.SampleRateHertz }},
+	{flag: "feedback-sinks", env: "FEEDBACK_SINKS", usage: "tujuan feedback: sheets, firestore, ndjson, webhook (dipisah koma)", ptr: func(c *Config) any { return &c.Feedback.Sinks }},
+	{flag: "feedback-sheet-id", env: "FEEDBACK_SHEET_ID", usage: "ID Google Sheet untuk feedback", ptr: func(c *Config) any { return &c.Feedback.SheetID }},
+	{flag: "feedback-sheet-range", env: "FEEDBACK_SHEET_RANGE", usage: "range Google Sheet untuk feedback", ptr: func(c *Config) any { return &c.Feedback.SheetRange }},
+	{flag: "feedback-firestore-collection", env: "FEEDBACK_FIRESTORE_COLLECTION", usage: "collection Firestore untuk feedback", ptr: func(c *Config) any { return &c.Feedback.FirestoreCollection }},
+	{flag: "feedback-ndjson-path", env: "FEEDBACK_NDJSON_PATH", usage: "file NDJSON lokal untuk feedback", ptr: func(c *Config) any { return &c.Feedback.NDJSONPath }},
+	{flag: "feedback-webhook-url", env: "FEEDBACK_WEBHOOK_URL", usage: "URL webhook untuk feedback", secret: true, ptr: func(c *Config) any { return &c.Feedback.WebhookURL }},
+	{flag: "health-probe-timeout", env: "HEALTH_PROBE_TIMEOUT", usage: "batas waktu tiap probe readiness", ptr: func(c *Config) any { return &c.Health.ProbeTimeout }},
+	{flag: "health-cache-ttl", env: "HEALTH_CACHE_TTL", usage: "lama hasil readiness di-cache", ptr: func(c *Config) any { return &c.Health.CacheTTL }},
+	{flag: "data-dir", env: "DATA_DIR", usage: "direktori data lokal, kosong untuk in-memory", ptr: func(c *Config) any { return &c.Storage.DataDir }},
+	{flag: "webhook-max-attempts", env: "WEBHOOK_MAX_ATTEMPTS", usage: "jumlah maksimal percobaan kirim webhook", ptr: func(c *Config) any { return &c.Webhooks.MaxAttempts }},
+	{flag: "webhook-initial-backoff", env: "WEBHOOK_INITIAL_BACKOFF", usage: "jeda awal sebelum retry webhook", ptr: func(c *Config) any { return &c.Webhooks.InitialBackoff }},
+	{flag: "webhook-max-backoff", env: "WEBHOOK_MAX_BACKOFF", usage: "jeda maksimal antar retry webhook", ptr: func(c *Config) any { return &c.Webhooks.MaxBackoff }},
+	{flag: "webhook-timeout", env: "WEBHOOK_TIMEOUT", usage: "batas waktu satu request webhook", ptr: func(c *Config) any { return &c.Webhooks.Timeout }},
+	{flag: "webhook-allow-private", env: "WEBHOOK_ALLOW_PRIVATE", usage: "izinkan URL webhook ke alamat internal (development saja)", ptr: func(c *Config) any { return &c.Webhooks.AllowPrivate }},
+	{flag: "gemini-max-attempts", env: "GEMINI_MAX_ATTEMPTS", usage: "jumlah maksimal percobaan panggilan Gemini", ptr: func(c *Config) any { return &c.Resilience.Gemini.MaxAttempts }},
+	{flag: "gemini-timeout", env: "GEMINI_TIMEOUT", usage: "batas waktu satu panggilan Gemini", ptr: func(c *Config) any { return &c.Resilience.Gemini.Timeout }},
+	{flag: "speech-max-attempts", env: "SPEECH_MAX_ATTEMPTS", usage: "jumlah maksimal percobaan transkripsi Speech-to-Text", ptr: func(c *Config) any { return &c.Resilience.Speech.MaxAttempts }},
+	{flag: "speech-timeout", env: "SPEECH_TIMEOUT", usage: "batas waktu satu transkripsi Speech-to-Text", ptr: func(c *Config) any { return &c.Resilience.Speech.Timeout }},
+	{flag: "gcs-max-attempts", env: "GCS_MAX_ATTEMPTS", usage: "jumlah maksimal percobaan upload ke GCS", ptr: func(c *Config) any { return &c.Resilience.Storage.MaxAttempts }},
+	{flag: "gcs-timeout", env: "GCS_TIMEOUT", usage: "batas waktu satu upload ke GCS", ptr: func(c *Config) any { return &c.Resilience.Storage.Timeout }},
+	{flag: "breaker-threshold", env: "BREAKER_THRESHOLD", usage: "kegagalan berturut-turut yang membuka circuit breaker provider, 0 untuk mematikan", ptr: func(c *Config) any { return &c.Resilience.BreakerThreshold }},
+	{flag: "breaker-cooldown", env: "BREAKER_COOLDOWN", usage: "lama circuit breaker terbuka sebelum mencoba lagi", ptr: func(c *Config) any { return &c.Resilience.BreakerCooldown }},
+	{flag: "usage-currency", env: "USAGE_CURRENCY", usage: "mata uang tabel harga pemakaian", ptr: func(c *Config) any { return &c.Usage.Currency }},
+	{flag: "job-workers", env: "JOB_WORKERS", usage: "jumlah worker job background", ptr: func(c *Config) any { return &c.Jobs.Workers }},
+	{flag: "job-queue-size", env: "JOB_QUEUE_SIZE", usage: "kapasitas antrean job background", ptr: func(c *Config) any { return &c.Jobs.QueueSize }},
+	{flag: "max-input-files", env: "MAX_INPUT_FILES", usage: "jumlah file maksimal per batch, termasuk isi ZIP", ptr: func(c *Config) any { return &c.Jobs.MaxInputFiles }},
+	{flag: "max-batch-mb", env: "MAX_BATCH_MB", usage: "total ukuran maksimal satu batch (MB)", ptr: func(c *Config) any { return &c.Jobs.MaxBatchMB }},
+	{flag: "remote-max-download-mb", env: "REMOTE_MAX_DOWNLOAD_MB", usage: "ukuran maksimal audio yang diunduh dari URL (MB)", ptr: func(c *Config) any { return &c.Remote.MaxDownloadMB }},
+	{flag: "remote-timeout", env: "REMOTE_TIMEOUT", usage: "batas waktu unduhan audio dari URL", ptr: func(c *Config) any { return &c.Remote.Timeout }},
+	{flag: "remote-allow-private", env: "REMOTE_ALLOW_PRIVATE", usage: "izinkan URL ke alamat internal (development saja)", ptr: func(c *Config) any { return &c.Remote.AllowPrivate }},
+	{flag: "ffmpeg-path", env: "FFMPEG_PATH", usage: "path binary ffmpeg untuk file video", ptr: func(c *Config) any { return &c.Media.FFmpegPath }},
+	{flag: "ffprobe-path", env: "FFPROBE_PATH", usage: "path binary ffprobe untuk keyframe video", ptr: func(c *Config) any { return &c.Media.FFprobePath }},
+	{flag: "video-keyframes", env: "VIDEO_KEYFRAMES", usage: "catat timestamp keyframe video", ptr: func(c *Config) any { return &c.Media.Keyframes }},
+	{flag: "video-keyframe-interval", env: "VIDEO_KEYFRAME_INTERVAL", usage: "jarak minimal antar keyframe yang dicatat", ptr: func(c *Config) any { return &c.Media.KeyframeInterval }},
+	{flag: "live-summary-interval", env: "LIVE_SUMMARY_INTERVAL", usage: "jarak antar ringkasan berjalan pada sesi live", ptr: func(c *Config) any { return &c.Live.SummaryInterval }},
+	{flag: "live-max-duration", env: "LIVE_MAX_DURATION", usage: "batas lama satu sesi live", ptr: func(c *Config) any { return &c.Live.MaxDuration }},
+	{flag: "search-embedder", env: "SEARCH_EMBEDDER", usage: "embedder pencarian semantik: gemini, http, atau kosong untuk mematikan", ptr: func(c *Config) any { return &c.Search.Embedder }},
+	{flag: "search-embedding-model", env: "SEARCH_EMBEDDING_MODEL", usage: "nama model embedding", ptr: func(c *Config) any { return &c.Search.Model }},
+	{flag: "search-embedder-url", env: "SEARCH_EMBEDDER_URL", usage: "URL endpoint embeddings untuk embedder http", ptr: func(c *Config) any { return &c.Search.URL }},
+	{flag: "search-embedder-api-key", env: "SEARCH_EMBEDDER_API_KEY", usage: "API key untuk embedder http", secret: true, ptr: func(c *Config) any { return &c.Search.APIKey }},
+	{flag: "search-embedder-timeout", env: "SEARCH_EMBEDDER_TIMEOUT", usage: "batas waktu satu request embedder http", ptr: func(c *Config) any { return &c.Search.Timeout }},
+	{flag: "action-link-url", env: "ACTION_LINK_URL", usage: "tautan ringkasan untuk action item di tracker, {id} diganti ID ringkasan", ptr: func(c *Config) any { return &c.Actions.LinkURL }},
+	{flag: "smtp-host", env: "SMTP_HOST", usage: "host SMTP untuk email ringkasan, kosong untuk mematikan", ptr: func(c *Config) any { return &c.Email.SMTPHost }},
+	{flag: "smtp-port", env: "SMTP_PORT", usage: "port SMTP", ptr: func(c *Config) any { return &c.Email.SMTPPort }},
+	{flag: "smtp-username", env: "SMTP_USERNAME", usage: "username SMTP, kosong jika tanpa AUTH", ptr: func(c *Config) any { return &c.Email.Username }},
+	{flag: "smtp-password", env: "SMTP_PASSWORD", usage: "password SMTP", secret: true, ptr: func(c *Config) any { return &c.Email.Password }},
+	{flag: "email-from", env: "EMAIL_FROM", usage: "alamat pengirim email", ptr: func(c *Config) any { return &c.Email.From }},
+	{flag: "auth-provider", env: "AUTH_PROVIDER", usage: "provider autentikasi: firebase, oidc atau static", ptr: func(c *Config) any { return &c.Auth.Provider }},
+	{flag: "auth-admins", env: "AUTH_ADMINS", usage: "UID/email yang selalu menjadi admin, dipisah koma", ptr: func(c *Config) any { return &c.Auth.Admins }},
+	{flag: "oidc-issuer", env: "OIDC_ISSUER", usage: "issuer OIDC (mis. URL realm Keycloak)", ptr: func(c *Config) any { return &c.Auth.OIDC.Issuer }},
+	{flag: "oidc-audience", env: "OIDC_AUDIENCE", usage: "audience JWT yang diterima", ptr: func(c *Config) any { return &c.Auth.OIDC.Audience }},
+	{flag: "oidc-jwks-url", env: "OIDC_JWKS_URL", usage: "URL JWKS, default dari discovery issuer", ptr: func(c *Config) any { return &c.Auth.OIDC.JWKSURL }},
+	{flag: "oidc-jwks-file", env: "OIDC_JWKS_FILE", usage: "file JWKS lokal", ptr: func(c *Config) any { return &c.Auth.OIDC.JWKSFile }},
+	{flag: "oidc-uid-claim", env: "OIDC_UID_CLAIM", usage: "claim yang dipakai sebagai user ID", ptr: func(c *Config) any { return &c.Auth.OIDC.UIDClaim }},
+	{flag: "oidc-email-claim", env: "OIDC_EMAIL_CLAIM", usage: "claim yang dipakai sebagai email", ptr: func(c *Config) any { return &c.Auth.OIDC.EmailClaim }},
+}
+
+// LoadConfig memuat konfigurasi dari file YAML, environment variables dan flag CLI.
+//
+// Lokasi file YAML diambil dari flag -config atau env CONFIG_FILE. Jika tidak
+// diset, hanya default, env dan flag yang dipakai. Error validasi dikembalikan
+// ke pemanggil, bukan menghentikan proses.
+//
+// Env yang diset kosong tetap berlaku, sama seperti flag kosong: setting
+// teks dan daftar menjadi kosong (mis. SEARCH_EMBEDDER= mematikan
+// pencarian semantik), sedangkan angka, boolean dan durasi menolak nilai
+// kosong. Hapus env-nya untuk memakai nilai dari YAML atau default.
+func LoadConfig(args []string) (*Config, error) {
+	cfg, err := Load(flag.NewFlagSet("summarize-api", flag.ContinueOnError), args)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Load sama seperti LoadConfig tetapi tanpa validasi, dan memakai FlagSet
+// milik pemanggil agar binary lain (mis. cmd/summarize) bisa menambahkan
+// flag sendiri lalu memvalidasi bagian konfigurasi yang dibutuhkannya saja.
+func Load(fs *flag.FlagSet, args []string) (*Config, error) {
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "path ke file konfigurasi YAML")
+
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		name := s.flag
+		fs.Func(name, fmt.Sprintf("%s (env %s)", s.usage, s.env), func(v string) error {
+			flagValues[name] = v
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("gagal membaca flag: %w", err)
 	}
 
-	firebaseProjectID := os.Getenv("FIREBASE_PROJECT_ID")
-	if firebaseProjectID == "" {
-		log.Fatal("Environment variable FIREBASE_PROJECT_ID tidak ditemukan.")
+	cfg := Default()
+
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, s := range settings {
+		if v, ok := os.LookupEnv(s.env); ok {
+			if err := setValue(s.ptr(cfg), v); err != nil {
+				errs = append(errs, fmt.Errorf("env %s: %w", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if v, ok := flagValues[s.flag]; ok {
+			if err := setValue(s.ptr(cfg), v); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", s.flag, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
-	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if geminiAPIKey == "" {
-		log.Fatal("Environment variable GEMINI_API_KEY tidak ditemukan.")
+	if cfg.FrontendURL != "" && !contains(cfg.CORSOrigins, cfg.FrontendURL) {
+		cfg.CORSOrigins = append(cfg.CORSOrigins, cfg.FrontendURL)
 	}
+	return cfg, nil
+}
 
-	frontendURL := os.Getenv("FRONTEND_URL")
-	if frontendURL == "" {
-		frontendURL = "http://localhost:5173" // Default ke localhost untuk development
-		log.Printf("WARN: Environment variable FRONTEND_URL tidak diset, menggunakan default: %s", frontendURL)
+// loadFile menimpa nilai cfg dengan isi file YAML di path.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("gagal membuka file konfigurasi %s: %w", path, err)
 	}
+	defer f.Close()
 
-	return &Config{
-		Port:           port,
-		FirebaseProjectID: firebaseProjectID,
-		GeminiAPIKey:   geminiAPIKey,
-		FrontendURL:    frontendURL,
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return fmt.Errorf("gagal parse file konfigurasi %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate memeriksa nilai wajib dan nilai yang tidak masuk akal.
+// Semua masalah dikumpulkan sekaligus agar mudah diperbaiki.
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := strconv.Atoi(c.Port); err != nil {
+		errs = append(errs, fmt.Errorf("port harus berupa angka, didapat %q", c.Port))
+	}
+	switch c.Auth.Provider {
+	case AuthProviderFirebase:
+		if c.FirebaseProjectID == "" {
+			errs = append(errs, errors.New("firebaseProjectId wajib diisi (env FIREBASE_PROJECT_ID)"))
+		}
+	case AuthProviderOIDC:
+		if c.Auth.OIDC.Issuer == "" {
+			errs = append(errs, errors.New("auth.oidc.issuer wajib diisi untuk provider oidc (env OIDC_ISSUER)"))
+		}
+	case AuthProviderStatic:
+		if len(c.Auth.StaticTokens) == 0 {
+			errs = append(errs, errors.New("auth.staticTokens wajib diisi untuk provider static"))
+		}
+		for i, t := range c.Auth.StaticTokens {
+			if t.Token == "" || t.UID == "" {
+				errs = append(errs, fmt.Errorf("auth.staticTokens[%d]: token dan uid wajib diisi", i))
+			}
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auth.provider tidak dikenal: %q", c.Auth.Provider))
+	}
+	errs = append(errs, c.ValidateSummarizer())
+	if c.Health.ProbeTimeout <= 0 {
+		errs = append(errs, errors.New("health.probeTimeout harus lebih dari 0"))
+	}
+	if c.Webhooks.MaxAttempts < 1 || c.Webhooks.InitialBackoff <= 0 || c.Webhooks.MaxBackoff < c.Webhooks.InitialBackoff || c.Webhooks.Timeout <= 0 {
+		errs = append(errs, errors.New("webhooks: maxAttempts minimal 1, backoff dan timeout harus positif, maxBackoff >= initialBackoff"))
+	}
+	if c.Usage.Currency == "" || c.Usage.SpeechPerMinute < 0 || c.Usage.StoragePerGB < 0 {
+		errs = append(errs, errors.New("usage: currency wajib diisi dan harga tidak boleh negatif"))
+	}
+	for i, m := range c.Usage.Models {
+		if m.Model == "" || m.InputPerMillion < 0 || m.OutputPerMillion < 0 {
+			errs = append(errs, fmt.Errorf("usage.models[%d]: model wajib diisi dan harga tidak boleh negatif", i))
+		}
+	}
+	if c.Jobs.Workers < 1 || c.Jobs.QueueSize < 1 || c.Jobs.MaxInputFiles < 1 || c.Jobs.MaxBatchMB < 1 {
+		errs = append(errs, errors.New("jobs: workers, queueSize, maxInputFiles dan maxBatchMB minimal 1"))
 	}
-}
\ No newline at end of file
+	if c.Remote.MaxDownloadMB < 1 || c.Remote.Timeout <= 0 {
+		errs = append(errs, errors.New("remote: maxDownloadMB minimal 1 dan timeout harus positif"))
+	}
+	if c.Live.SummaryInterval <= 0 || c.Live.MaxDuration <= 0 {
+		errs = append(errs, errors.New("live: summaryInterval dan maxDuration harus positif"))
+	}
+	switch c.Search.Embedder {
+	case "":
+	case EmbedderGemini:
+		if c.Search.Model == "" {
+			errs = append(errs, errors.New("search.model wajib diisi untuk embedder gemini"))
+		}
+	case EmbedderHTTP:
+		if c.Search.URL == "" || c.Search.Model == "" || c.Search.Timeout <= 0 {
+			errs = append(errs, errors.New("search: url dan model wajib diisi dan timeout harus positif untuk embedder http"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("search.embedder tidak dikenal: %q", c.Search.Embedder))
+	}
+	names := make(map[string]bool)
+	for i, conn := range c.Actions.Connectors {
+		switch {
+		case conn.Name == "":
+			errs = append(errs, fmt.Errorf("actions.connectors[%d]: name wajib diisi", i))
+		case names[conn.Name]:
+			errs = append(errs, fmt.Errorf("actions.connectors[%d]: name %q dipakai lebih dari sekali", i, conn.Name))
+		}
+		names[conn.Name] = true
+		switch conn.Type {
+		case ActionConnectorGitHub, ActionConnectorJira, ActionConnectorWebhook:
+		default:
+			errs = append(errs, fmt.Errorf("actions.connectors[%d]: type tidak dikenal: %q", i, conn.Type))
+		}
+		if conn.Timeout < 0 {
+			errs = append(errs, fmt.Errorf("actions.connectors[%d]: timeout tidak boleh negatif", i))
+		}
+	}
+	if c.Email.SMTPHost != "" && (c.Email.SMTPPort <= 0 || c.Email.From == "") {
+		errs = append(errs, errors.New("email: smtpPort harus positif dan from wajib diisi jika smtpHost diisi"))
+	}
+	if len(c.Feedback.Sinks) == 0 {
+		errs = append(errs, errors.New("feedback.sinks minimal berisi satu sink"))
+	}
+	for _, sink := range c.Feedback.Sinks {
+		switch sink {
+		case FeedbackSinkSheets:
+			if c.Feedback.SheetID == "" || c.Feedback.SheetRange == "" {
+				errs = append(errs, errors.New("feedback.sheetId dan feedback.sheetRange wajib diisi untuk sink sheets"))
+			}
+		case FeedbackSinkFirestore:
+			if c.FirebaseProjectID == "" || c.Feedback.FirestoreCollection == "" {
+				errs = append(errs, errors.New("firebaseProjectId dan feedback.firestoreCollection wajib diisi untuk sink firestore"))
+			}
+		case FeedbackSinkNDJSON:
+			if c.Feedback.NDJSONPath == "" && c.Storage.DataDir == "" {
+				errs = append(errs, errors.New("feedback.ndjsonPath atau storage.dataDir wajib diisi untuk sink ndjson"))
+			}
+		case FeedbackSinkWebhook:
+			if c.Feedback.WebhookURL == "" {
+				errs = append(errs, errors.New("feedback.webhookUrl wajib diisi untuk sink webhook"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("feedback sink tidak dikenal: %q", sink))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateSummarizer memeriksa konfigurasi yang dibutuhkan SummarizeService
+// (Gemini, GCS dan Speech-to-Text), tanpa bagian khusus server HTTP.
+func (c *Config) ValidateSummarizer() error {
+	var errs []error
+	if c.GeminiAPIKey == "" {
+		errs = append(errs, errors.New("geminiApiKey wajib diisi (env GEMINI_API_KEY)"))
+	}
+	if c.GCSBucketName == "" {
+		errs = append(errs, errors.New("gcsBucketName wajib diisi (env GCS_BUCKET_NAME)"))
+	}
+	if c.Gemini.Model == "" {
+		errs = append(errs, errors.New("gemini.model tidak boleh kosong"))
+	}
+	for i, r := range c.Gemini.Routes {
+		if r.Model == "" || r.MinChars < 0 || r.MaxChars < 0 || (r.MaxChars > 0 && r.MaxChars < r.MinChars) {
+			errs = append(errs, fmt.Errorf("gemini.routes[%d]: model wajib diisi, minChars/maxChars tidak boleh negatif dan maxChars >= minChars", i))
+		}
+	}
+	for i, name := range c.Gemini.Fallbacks {
+		if name == "" {
+			errs = append(errs, fmt.Errorf("gemini.fallbacks[%d]: nama model tidak boleh kosong", i))
+		}
+	}
+	if c.Speech.LanguageCode == "" {
+		errs = append(errs, errors.New("speech.languageCode tidak boleh kosong"))
+	}
+	if c.Speech.SampleRateHertz < 0 {
+		errs = append(errs, fmt.Errorf("speech.sampleRateHertz tidak boleh negatif, didapat %d", c.Speech.SampleRateHertz))
+	}
+	if c.Media.FFmpegPath == "" {
+		errs = append(errs, errors.New("media.ffmpegPath tidak boleh kosong"))
+	}
+	if c.Media.Keyframes && (c.Media.FFprobePath == "" || c.Media.KeyframeInterval < 0) {
+		errs = append(errs, errors.New("media: ffprobePath wajib diisi dan keyframeInterval tidak boleh negatif jika keyframes aktif"))
+	}
+	policies := []struct {
+		name   string
+		policy RetryPolicyConfig
+	}{{"gemini", c.Resilience.Gemini}, {"speech", c.Resilience.Speech}, {"storage", c.Resilience.Storage}}
+	for _, p := range policies {
+		if !p.policy.valid() {
+			errs = append(errs, fmt.Errorf("resilience.%s: maxAttempts minimal 1, backoff dan timeout harus positif, maxBackoff >= initialBackoff", p.name))
+		}
+	}
+	if c.Resilience.BreakerThreshold < 0 || (c.Resilience.BreakerThreshold > 0 && c.Resilience.BreakerCooldown <= 0) {
+		errs = append(errs, errors.New("resilience: breakerThreshold tidak boleh negatif dan breakerCooldown harus positif jika breaker aktif"))
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted mengembalikan konfigurasi efektif dalam format "key = value",
+// dengan nilai rahasia disamarkan. Aman untuk ditulis ke log.
+func (c *Config) Redacted() string {
+	var b strings.Builder
+	for _, s := range settings {
+		v := formatValue(s.ptr(c))
+		if s.secret && v != "" {
+			v = redact(v)
+		}
+		fmt.Fprintf(&b, "  %s = %s\n", s.flag, v)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func redact(v string) string {
+	if len(v) <= 4 {
+		return "****"
+	}
+	return v[:2] + "****" + v[len(v)-2:]
+}
+
+func setValue(ptr any, raw string) error {
+	switch p := ptr.(type) {
+	case *string:
+		*p = raw
+	case *int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("nilai %q bukan angka", raw)
+		}
+		*p = n
+	case *bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("nilai %q bukan boolean", raw)
+		}
+		*p = b
+	case *[]string:
+		*p = splitList(raw)
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("nilai %q bukan durasi (contoh: 5s, 1m)", raw)
+		}
+		*p = d
+	default:
+		return fmt.Errorf("tipe setting %T tidak didukung", ptr)
+	}
+	return nil
+}
+
+func formatValue(ptr any) string {
+	switch p := ptr.(type) {
+	case *string:
+		return *p
+	case *int:
+		return strconv.Itoa(*p)
+	case *bool:
+		return strconv.FormatBool(*p)
+	case *[]string:
+		return strings.Join(*p, ",")
+	case *time.Duration:
+		return p.String()
+	default:
+		return fmt.Sprint(ptr)
+	}
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
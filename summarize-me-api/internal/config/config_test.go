@@ -0,0 +1,168 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// load menjalankan Load dengan file YAML sementara berisi yaml (jika tidak
+// kosong) dan flag args.
+func load(t *testing.T, yaml string, args ...string) (*Config, error) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	if yaml != "" {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		args = append([]string{"-config", path}, args...)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(new(strings.Builder))
+	return Load(fs, args)
+}
+
+// unsetEnv menghapus env selama test berjalan dan mengembalikannya setelahnya.
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	unsetEnv(t, "PORT", "GEMINI_MODEL", "SPEECH_LANGUAGE_CODE", "JOB_WORKERS")
+	t.Setenv("PORT", "7000")
+	t.Setenv("GEMINI_MODEL", "env-model")
+
+	cfg, err := load(t, `
+port: "9000"
+gemini:
+  model: yaml-model
+speech:
+  languageCode: en-US
+`, "-port", "6000")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"flag > env > YAML", cfg.Port, "6000"},
+		{"env > YAML", cfg.Gemini.Model, "env-model"},
+		{"YAML > default", cfg.Speech.LanguageCode, "en-US"},
+		{"default", cfg.Jobs.Workers, Default().Jobs.Workers},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadEmptyEnv(t *testing.T) {
+	unsetEnv(t, "SEARCH_EMBEDDER", "GEMINI_FALLBACK_MODELS", "JOB_WORKERS")
+	t.Setenv("SEARCH_EMBEDDER", "")
+	t.Setenv("GEMINI_FALLBACK_MODELS", "")
+
+	cfg, err := load(t, "search:\n  embedder: http\n")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Search.Embedder != "" {
+		t.Errorf("search.embedder = %q, want kosong dari env", cfg.Search.Embedder)
+	}
+	if len(cfg.Gemini.Fallbacks) != 0 {
+		t.Errorf("gemini.fallbacks = %v, want kosong dari env", cfg.Gemini.Fallbacks)
+	}
+
+	t.Setenv("JOB_WORKERS", "")
+	if _, err := load(t, ""); err == nil || !strings.Contains(err.Error(), "JOB_WORKERS") {
+		t.Errorf("JOB_WORKERS kosong: err = %v, want error angka", err)
+	}
+}
+
+func TestLoadRejectsUnknownYAMLKeys(t *testing.T) {
+	_, err := load(t, "gemini:\n  modle: gemini-2.5-pro\n")
+	if err == nil {
+		t.Fatal("Load seharusnya menolak key YAML yang tidak dikenal")
+	}
+	if !strings.Contains(err.Error(), "modle") {
+		t.Errorf("error %q tidak menyebut key yang salah", err)
+	}
+}
+
+func TestLoadInvalidValues(t *testing.T) {
+	unsetEnv(t, "WEBHOOK_TIMEOUT")
+	t.Setenv("WEBHOOK_TIMEOUT", "sebentar")
+
+	_, err := load(t, "", "-job-workers", "dua")
+	if err == nil {
+		t.Fatal("Load seharusnya error untuk nilai yang tidak valid")
+	}
+	for _, want := range []string{"env WEBHOOK_TIMEOUT", "flag -job-workers"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q tidak berisi %q", err, want)
+		}
+	}
+}
+
+func TestValidateCollectsAllErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Port = "http"
+	cfg.Auth.Provider = AuthProviderOIDC
+	cfg.Jobs.Workers = 0
+	cfg.Webhooks.Timeout = 0
+	cfg.Gemini.Fallbacks = []string{""}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate seharusnya error")
+	}
+	for _, want := range []string{
+		"port harus berupa angka",
+		"auth.oidc.issuer",
+		"geminiApiKey wajib diisi",
+		"gemini.fallbacks[0]",
+		"webhooks:",
+		"jobs:",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error tidak berisi %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.GeminiAPIKey = "AIzaSyRahasiaSekali"
+	cfg.Email.Password = "abc"
+	cfg.Search.APIKey = ""
+	cfg.Webhooks.Timeout = 15 * time.Second
+
+	out := cfg.Redacted()
+	for _, secret := range []string{"AIzaSyRahasiaSekali", "abc"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Redacted membocorkan %q:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{
+		"gemini-api-key = AI****li",
+		"smtp-password = ****",
+		"search-embedder-api-key = \n",
+		"webhook-timeout = 15s",
+		"gemini-model = " + cfg.Gemini.Model,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Redacted tidak berisi %q:\n%s", want, out)
+		}
+	}
+}
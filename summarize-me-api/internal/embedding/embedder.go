@@ -0,0 +1,73 @@
+// Package embedding mengubah teks menjadi vektor untuk pencarian semantik.
+// Implementasi tersedia untuk model embedding Gemini dan server model lokal
+// yang kompatibel dengan API embeddings OpenAI (mis. Ollama, LocalAI,
+// llama.cpp server).
+package embedding
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// Task membedakan teks dokumen yang diindeks dan teks query pencarian.
+// Sebagian model menghasilkan vektor yang lebih baik jika tahu perannya.
+type Task int
+
+const (
+	TaskDocument Task = iota
+	TaskQuery
+)
+
+// Embedder menghasilkan satu vektor untuk tiap teks, dengan urutan yang sama.
+type Embedder interface {
+	// Name mengidentifikasi model; vektor dari model berbeda tidak bisa
+	// dibandingkan.
+	Name() string
+	Embed(ctx context.Context, task Task, texts []string) ([][]float32, error)
+}
+
+// geminiBatchLimit adalah jumlah teks maksimal per BatchEmbedContents.
+const geminiBatchLimit = 100
+
+// GeminiEmbedder memakai model embedding Gemini (mis. text-embedding-004).
+type GeminiEmbedder struct {
+	client *genai.Client
+	model  string
+}
+
+// NewGeminiEmbedder membuat embedder dari client Gemini yang sudah ada.
+func NewGeminiEmbedder(client *genai.Client, model string) *GeminiEmbedder {
+	return &GeminiEmbedder{client: client, model: model}
+}
+
+func (e *GeminiEmbedder) Name() string { return "gemini:" + e.model }
+
+func (e *GeminiEmbedder) Embed(ctx context.Context, task Task, texts []string) ([][]float32, error) {
+	em := e.client.EmbeddingModel(e.model)
+	em.TaskType = genai.TaskTypeRetrievalDocument
+	if task == TaskQuery {
+		em.TaskType = genai.TaskTypeRetrievalQuery
+	}
+
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += geminiBatchLimit {
+		end := min(start+geminiBatchLimit, len(texts))
+		batch := em.NewBatch()
+		for _, t := range texts[start:end] {
+			batch.AddContent(genai.Text(t))
+		}
+		resp, err := em.BatchEmbedContents(ctx, batch)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuat embedding Gemini: %w", err)
+		}
+		if len(resp.Embeddings) != end-start {
+			return nil, fmt.Errorf("jumlah embedding Gemini tidak sesuai: %d dari %d", len(resp.Embeddings), end-start)
+		}
+		for _, emb := range resp.Embeddings {
+			out = append(out, emb.Values)
+		}
+	}
+	return out, nil
+}
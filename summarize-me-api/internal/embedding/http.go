@@ -0,0 +1,84 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPEmbedder memanggil server model lokal lewat endpoint yang kompatibel
+// dengan POST /v1/embeddings milik OpenAI.
+type HTTPEmbedder struct {
+	url    string
+	model  string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTPEmbedder membuat embedder untuk URL endpoint embeddings lengkap,
+// mis. http://localhost:11434/v1/embeddings. apiKey boleh kosong.
+func NewHTTPEmbedder(url, model, apiKey string, timeout time.Duration) *HTTPEmbedder {
+	return &HTTPEmbedder{url: url, model: model, apiKey: apiKey, client: &http.Client{Timeout: timeout}}
+}
+
+func (e *HTTPEmbedder) Name() string { return "http:" + e.model }
+
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Embed tidak membedakan task karena API OpenAI tidak mengenalnya.
+func (e *HTTPEmbedder) Embed(ctx context.Context, task Task, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat request embedding: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memanggil server embedding: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("server embedding membalas %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var parsed embeddingsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("respons server embedding tidak valid: %w", err)
+	}
+	out := make([][]float32, len(texts))
+	for _, d := range parsed.Data {
+		if d.Index < 0 || d.Index >= len(out) {
+			return nil, fmt.Errorf("index embedding di luar jangkauan: %d", d.Index)
+		}
+		out[d.Index] = d.Embedding
+	}
+	for i, v := range out {
+		if len(v) == 0 {
+			return nil, fmt.Errorf("embedding untuk teks ke-%d tidak ada di respons", i)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package feedback
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+)
+
+// FirestoreSink menyimpan feedback ke collection Firestore, dengan field
+// yang sama seperti yang selama ini ditulis frontend (project_feedback).
+type FirestoreSink struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewFirestoreSink membuat sink dari Firestore client yang sudah ada.
+func NewFirestoreSink(client *firestore.Client, collection string) *FirestoreSink {
+	return &FirestoreSink{client: client, collection: collection}
+}
+
+// Name mengembalikan nama sink.
+func (s *FirestoreSink) Name() string {
+	return "firestore"
+}
+
+// Submit menulis satu dokumen dengan ID feedback.
+func (s *FirestoreSink) Submit(ctx context.Context, fb Feedback) error {
+	_, err := s.client.Collection(s.collection).Doc(fb.ID).Set(ctx, map[string]interface{}{
+		"comment":   fb.Comment,
+		"createdAt": fb.CreatedAt,
+		"page":      fb.Page,
+		"userAgent": fb.UserAgent,
+		"userEmail": fb.Email,
+		"userId":    fb.UserID,
+	})
+	return err
+}
+
+// List membaca dokumen terbaru.
+func (s *FirestoreSink) List(ctx context.Context, limit int) ([]Feedback, error) {
+	query := s.client.Collection(s.collection).OrderBy("createdAt", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	var out []Feedback
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var data struct {
+			Comment   string      `firestore:"comment"`
+			CreatedAt interface{} `firestore:"createdAt"`
+			Page      string      `firestore:"page"`
+			UserAgent string      `firestore:"userAgent"`
+			UserEmail string      `firestore:"userEmail"`
+			UserID    string      `firestore:"userId"`
+		}
+		if err := doc.DataTo(&data); err != nil {
+			return nil, err
+		}
+		fb := Feedback{
+			ID:        doc.Ref.ID,
+			UserID:    data.UserID,
+			Email:     data.UserEmail,
+			Comment:   data.Comment,
+			Page:      data.Page,
+			UserAgent: data.UserAgent,
+			CreatedAt: doc.CreateTime,
+		}
+		// Dokumen lama dari frontend menyimpan createdAt sebagai string,
+		// jadi waktu pembuatan dokumen dipakai sebagai cadangan.
+		if t, ok := data.CreatedAt.(time.Time); ok {
+			fb.CreatedAt = t
+		}
+		out = append(out, fb)
+	}
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package feedback
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// NDJSONSink menambahkan feedback ke file lokal, satu objek JSON per baris.
+// Berguna sebagai cadangan yang tidak bergantung pada layanan eksternal.
+type NDJSONSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewNDJSONSink membuat sink file lokal di path.
+func NewNDJSONSink(path string) (*NDJSONSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori %s: %w", filepath.Dir(path), err)
+	}
+	return &NDJSONSink{path: path}, nil
+}
+
+// Name mengembalikan nama sink.
+func (s *NDJSONSink) Name() string {
+	return "ndjson"
+}
+
+// Submit menambahkan satu baris ke file.
+func (s *NDJSONSink) Submit(ctx context.Context, fb Feedback) error {
+	line, err := json.Marshal(fb)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// List membaca seluruh file dan mengembalikan baris terbaru lebih dulu.
+func (s *NDJSONSink) List(ctx context.Context, limit int) ([]Feedback, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var all []Feedback
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var fb Feedback
+		if err := json.Unmarshal(scanner.Bytes(), &fb); err != nil {
+			continue // lewati baris rusak
+		}
+		all = append(all, fb)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []Feedback
+	for i := len(all) - 1; i >= 0 && (limit <= 0 || len(out) < limit); i-- {
+		out = append(out, all[i])
+	}
+	return out, nil
+}
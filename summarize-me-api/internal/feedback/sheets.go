@@ -0,0 +1,73 @@
+package feedback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+const sheetTimeFormat = "2006-01-02/15:04:05"
+
+// SheetsSink menambahkan feedback sebagai baris baru di Google Sheet.
+type SheetsSink struct {
+	service    *sheets.Service
+	sheetID    string
+	sheetRange string
+}
+
+// NewSheetsSink membuat Sheets client sekali saat startup.
+// Otomatis menggunakan Application Default Credentials (ADC).
+func NewSheetsSink(ctx context.Context, sheetID, sheetRange string) (*SheetsSink, error) {
+	service, err := sheets.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat Sheets service: %w", err)
+	}
+	return &SheetsSink{service: service, sheetID: sheetID, sheetRange: sheetRange}, nil
+}
+
+// Name mengembalikan nama sink.
+func (s *SheetsSink) Name() string {
+	return "sheets"
+}
+
+// Submit menulis satu baris: waktu, email, komentar.
+func (s *SheetsSink) Submit(ctx context.Context, fb Feedback) error {
+	row := &sheets.ValueRange{
+		Values: [][]interface{}{
+			{fb.CreatedAt.Format(sheetTimeFormat), fb.Email, fb.Comment},
+		},
+	}
+	_, err := s.service.Spreadsheets.Values.Append(s.sheetID, s.sheetRange, row).
+		ValueInputOption("USER_ENTERED").
+		InsertDataOption("INSERT_ROWS").
+		Context(ctx).
+		Do()
+	return err
+}
+
+// List membaca baris dari sheet, terbaru lebih dulu.
+func (s *SheetsSink) List(ctx context.Context, limit int) ([]Feedback, error) {
+	resp, err := s.service.Spreadsheets.Values.Get(s.sheetID, s.sheetRange).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Feedback
+	for i := len(resp.Values) - 1; i >= 0 && (limit <= 0 || len(out) < limit); i-- {
+		row := resp.Values[i]
+		var fb Feedback
+		if len(row) > 0 {
+			fb.CreatedAt, _ = time.ParseInLocation(sheetTimeFormat, fmt.Sprint(row[0]), time.Local)
+		}
+		if len(row) > 1 {
+			fb.Email = fmt.Sprint(row[1])
+		}
+		if len(row) > 2 {
+			fb.Comment = fmt.Sprint(row[2])
+		}
+		out = append(out, fb)
+	}
+	return out, nil
+}
@@ -0,0 +1,92 @@
+package feedback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrListUnsupported dikembalikan jika tidak ada sink yang bisa membaca
+// kembali feedback.
+var ErrListUnsupported = errors.New("tidak ada feedback sink yang mendukung listing")
+
+// Feedback adalah satu masukan dari user.
+type Feedback struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	Email     string    `json:"email"`
+	Comment   string    `json:"comment"`
+	Page      string    `json:"page,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Sink adalah tujuan penyimpanan feedback.
+type Sink interface {
+	Name() string
+	Submit(ctx context.Context, fb Feedback) error
+}
+
+// Lister diimplementasikan Sink yang bisa membaca kembali feedback,
+// dipakai oleh endpoint admin. Hasil diurutkan dari yang terbaru.
+type Lister interface {
+	List(ctx context.Context, limit int) ([]Feedback, error)
+}
+
+// MultiSink mengirim feedback ke beberapa Sink sekaligus. Submit dianggap
+// berhasil jika minimal satu Sink berhasil, sehingga feedback tidak hilang
+// saat salah satu tujuan (mis. kuota Google Sheets) bermasalah.
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink membuat fan-out ke semua sinks.
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Name mengembalikan nama gabungan.
+func (m *MultiSink) Name() string {
+	return "multi"
+}
+
+// Submit mengirim ke semua Sink secara paralel.
+func (m *MultiSink) Submit(ctx context.Context, fb Feedback) error {
+	errs := make([]error, len(m.sinks))
+	var wg sync.WaitGroup
+	for i, sink := range m.sinks {
+		wg.Add(1)
+		go func(i int, sink Sink) {
+			defer wg.Done()
+			if err := sink.Submit(ctx, fb); err != nil {
+				log.Printf("WARN: Gagal menyimpan feedback %s ke %s: %v", fb.ID, sink.Name(), err)
+				errs[i] = fmt.Errorf("%s: %w", sink.Name(), err)
+			}
+		}(i, sink)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == len(m.sinks) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// List membaca dari Sink pertama yang mendukung Lister.
+func (m *MultiSink) List(ctx context.Context, limit int) ([]Feedback, error) {
+	for _, sink := range m.sinks {
+		if lister, ok := sink.(Lister); ok {
+			return lister.List(ctx, limit)
+		}
+	}
+	return nil, ErrListUnsupported
+}
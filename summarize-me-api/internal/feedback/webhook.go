@@ -0,0 +1,50 @@
+package feedback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink mengirim feedback sebagai JSON ke URL eksternal
+// (mis. Slack workflow, Zapier atau layanan internal).
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink membuat sink webhook.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Name mengembalikan nama sink.
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Submit mengirim POST dan menganggap status selain 2xx sebagai gagal.
+func (s *WebhookSink) Submit(ctx context.Context, fb Feedback) error {
+	body, err := json.Marshal(fb)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook mengembalikan status %d", resp.StatusCode)
+	}
+	return nil
+}
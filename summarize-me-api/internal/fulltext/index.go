@@ -0,0 +1,349 @@
+// Package fulltext menyediakan index pencarian kata kunci (full-text) atas
+// ringkasan dan transkrip, berbasis Bleve dengan analyzer bahasa Indonesia
+// (stop word + stemming).
+package fulltext
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/lang/id"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/format/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// AnalyzerName adalah analyzer teks bahasa Indonesia yang dipakai untuk
+// field text, baik saat indexing maupun query.
+const AnalyzerName = "indonesian"
+
+// analyzerVersion disimpan di index. Naikkan setiap kali Stem atau analyzer
+// berubah; index di disk dengan versi lain dibuat ulang lalu diisi Backfill.
+const analyzerVersion = "2"
+
+// analyzerVersionKey adalah key internal Bleve tempat analyzerVersion disimpan.
+var analyzerVersionKey = []byte("analyzerVersion")
+
+// maxDocsPerSummary membatasi pencarian dokumen lama saat indexing ulang.
+const maxDocsPerSummary = 10000
+
+// Document adalah satu unit yang diindeks: ringkasan, atau satu paragraf
+// transkrip. Speakers dan Tags disimpan huruf kecil untuk filter.
+type Document struct {
+	UserID       string    `json:"userId"`
+	SummaryID    string    `json:"summaryId"`
+	FileName     string    `json:"fileName"`
+	Kind         string    `json:"kind"`
+	Text         string    `json:"text"`
+	Speaker      string    `json:"speaker"`
+	Speakers     []string  `json:"speakers"`
+	Tags         []string  `json:"tags"`
+	Timestamp    string    `json:"timestamp"`
+	SegmentStart int       `json:"segmentStart"`
+	SegmentEnd   int       `json:"segmentEnd"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Query adalah parameter pencarian. Text mendukung frasa persis dalam tanda
+// kutip ganda; kata lain harus muncul semua (AND). Filter kosong diabaikan.
+type Query struct {
+	UserID  string
+	Text    string
+	From    time.Time
+	To      time.Time
+	Tags    []string
+	Speaker string
+	Kind    string
+	Limit   int
+	Offset  int
+}
+
+// Hit adalah satu hasil pencarian. Snippets berisi potongan teks dengan
+// kata yang cocok dibungkus <mark> (sudah di-escape HTML).
+type Hit struct {
+	ID       string
+	Score    float64
+	Document Document
+	Snippets []string
+}
+
+// Result adalah satu halaman hasil pencarian.
+type Result struct {
+	Total uint64
+	Hits  []Hit
+}
+
+// Index membungkus index Bleve.
+type Index struct {
+	index bleve.Index
+}
+
+// Open membuka index di path, membuatnya jika belum ada. path kosong
+// berarti index hanya di memori.
+func Open(path string) (*Index, error) {
+	if path == "" {
+		idx, err := bleve.NewMemOnly(newMapping())
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuat index full-text: %w", err)
+		}
+		return &Index{index: idx}, nil
+	}
+	idx, err := bleve.Open(path)
+	if err == nil {
+		if version, _ := idx.GetInternal(analyzerVersionKey); string(version) != analyzerVersion {
+			idx.Close()
+			if err := os.RemoveAll(path); err != nil {
+				return nil, fmt.Errorf("gagal menghapus index full-text lama %s: %w", path, err)
+			}
+			err = bleve.ErrorIndexPathDoesNotExist
+		}
+	}
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, err
+		}
+		idx, err = bleve.New(path, newMapping())
+		if err == nil {
+			err = idx.SetInternal(analyzerVersionKey, []byte(analyzerVersion))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka index full-text %s: %w", path, err)
+	}
+	return &Index{index: idx}, nil
+}
+
+// Close menutup index.
+func (x *Index) Close() error {
+	return x.index.Close()
+}
+
+// DocCount mengembalikan jumlah dokumen di index.
+func (x *Index) DocCount() (uint64, error) {
+	return x.index.DocCount()
+}
+
+func newMapping() *mapping.IndexMappingImpl {
+	im := bleve.NewIndexMapping()
+	err := im.AddCustomAnalyzer(AnalyzerName, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicode.Name,
+		"token_filters": []string{lowercase.Name, id.StopName, StemmerName},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	keyword := func() *mapping.FieldMapping {
+		fm := bleve.NewKeywordFieldMapping()
+		fm.Store = true
+		return fm
+	}
+	stored := func() *mapping.FieldMapping {
+		fm := bleve.NewTextFieldMapping()
+		fm.Index = false
+		fm.Store = true
+		return fm
+	}
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = AnalyzerName
+	text.Store = true
+	text.IncludeTermVectors = true
+	number := bleve.NewNumericFieldMapping()
+	number.Index = false
+	number.Store = true
+
+	doc := bleve.NewDocumentStaticMapping()
+	doc.AddFieldMappingsAt("userId", keyword())
+	doc.AddFieldMappingsAt("summaryId", keyword())
+	doc.AddFieldMappingsAt("kind", keyword())
+	doc.AddFieldMappingsAt("speakers", keyword())
+	doc.AddFieldMappingsAt("tags", keyword())
+	doc.AddFieldMappingsAt("text", text)
+	doc.AddFieldMappingsAt("fileName", stored())
+	doc.AddFieldMappingsAt("speaker", stored())
+	doc.AddFieldMappingsAt("timestamp", stored())
+	doc.AddFieldMappingsAt("segmentStart", number)
+	doc.AddFieldMappingsAt("segmentEnd", number)
+	doc.AddFieldMappingsAt("createdAt", bleve.NewDateTimeFieldMapping())
+	im.DefaultMapping = doc
+	im.DefaultAnalyzer = AnalyzerName
+	return im
+}
+
+// Replace mengganti semua dokumen milik summaryID dengan docs. ID dokumen
+// dibentuk dari summaryID dan posisinya.
+func (x *Index) Replace(summaryID string, docs []Document) error {
+	old, err := x.docIDs(summaryID)
+	if err != nil {
+		return err
+	}
+	batch := x.index.NewBatch()
+	for _, docID := range old {
+		batch.Delete(docID)
+	}
+	for i, d := range docs {
+		d.SummaryID = summaryID
+		if err := batch.Index(fmt.Sprintf("%s/%d", summaryID, i), d); err != nil {
+			return fmt.Errorf("gagal menyiapkan dokumen full-text: %w", err)
+		}
+	}
+	if err := x.index.Batch(batch); err != nil {
+		return fmt.Errorf("gagal menulis index full-text: %w", err)
+	}
+	return nil
+}
+
+// Has mengecek apakah summaryID sudah punya dokumen di index.
+func (x *Index) Has(summaryID string) (bool, error) {
+	ids, err := x.docIDs(summaryID)
+	return len(ids) > 0, err
+}
+
+func (x *Index) docIDs(summaryID string) ([]string, error) {
+	q := bleve.NewTermQuery(summaryID)
+	q.SetField("summaryId")
+	res, err := x.index.Search(bleve.NewSearchRequestOptions(q, maxDocsPerSummary, 0, false))
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencari dokumen lama: %w", err)
+	}
+	ids := make([]string, len(res.Hits))
+	for i, h := range res.Hits {
+		ids[i] = h.ID
+	}
+	return ids, nil
+}
+
+// Search menjalankan pencarian kata kunci untuk satu user.
+func (x *Index) Search(q Query) (Result, error) {
+	userQ := bleve.NewTermQuery(q.UserID)
+	userQ.SetField("userId")
+	conjuncts := []query.Query{userQ}
+
+	phrases, words := splitPhrases(q.Text)
+	for _, p := range phrases {
+		pq := bleve.NewMatchPhraseQuery(p)
+		pq.SetField("text")
+		conjuncts = append(conjuncts, pq)
+	}
+	if words != "" {
+		mq := bleve.NewMatchQuery(words)
+		mq.SetField("text")
+		mq.SetOperator(query.MatchQueryOperatorAnd)
+		conjuncts = append(conjuncts, mq)
+	}
+	if !q.From.IsZero() || !q.To.IsZero() {
+		inclusive := true
+		dq := bleve.NewDateRangeInclusiveQuery(q.From, q.To, &inclusive, &inclusive)
+		dq.SetField("createdAt")
+		conjuncts = append(conjuncts, dq)
+	}
+	for _, tag := range q.Tags {
+		tq := bleve.NewTermQuery(strings.ToLower(tag))
+		tq.SetField("tags")
+		conjuncts = append(conjuncts, tq)
+	}
+	if q.Speaker != "" {
+		sq := bleve.NewTermQuery(strings.ToLower(q.Speaker))
+		sq.SetField("speakers")
+		conjuncts = append(conjuncts, sq)
+	}
+	if q.Kind != "" {
+		kq := bleve.NewTermQuery(q.Kind)
+		kq.SetField("kind")
+		conjuncts = append(conjuncts, kq)
+	}
+
+	req := bleve.NewSearchRequestOptions(bleve.NewConjunctionQuery(conjuncts...), q.Limit, q.Offset, false)
+	req.Fields = []string{"*"}
+	req.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	req.Highlight.AddField("text")
+	if q.Text == "" {
+		req.SortBy([]string{"-createdAt"})
+	}
+
+	res, err := x.index.Search(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("gagal mencari di index full-text: %w", err)
+	}
+	out := Result{Total: res.Total, Hits: make([]Hit, 0, len(res.Hits))}
+	for _, h := range res.Hits {
+		out.Hits = append(out.Hits, Hit{
+			ID:       h.ID,
+			Score:    h.Score,
+			Document: documentFromFields(h.Fields),
+			Snippets: h.Fragments["text"],
+		})
+	}
+	return out, nil
+}
+
+// splitPhrases memisahkan bagian dalam tanda kutip ganda (frasa persis)
+// dari kata-kata lainnya. Kutip yang tidak ditutup dianggap frasa sampai
+// akhir teks.
+func splitPhrases(text string) (phrases []string, words string) {
+	var rest []string
+	for i, part := range strings.Split(text, `"`) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if i%2 == 1 {
+			phrases = append(phrases, part)
+		} else {
+			rest = append(rest, part)
+		}
+	}
+	return phrases, strings.Join(rest, " ")
+}
+
+func documentFromFields(fields map[string]interface{}) Document {
+	str := func(name string) string {
+		s, _ := fields[name].(string)
+		return s
+	}
+	num := func(name string) int {
+		f, _ := fields[name].(float64)
+		return int(f)
+	}
+	list := func(name string) []string {
+		switch v := fields[name].(type) {
+		case string:
+			return []string{v}
+		case []interface{}:
+			out := make([]string, 0, len(v))
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					out = append(out, s)
+				}
+			}
+			return out
+		}
+		return nil
+	}
+	doc := Document{
+		UserID:       str("userId"),
+		SummaryID:    str("summaryId"),
+		FileName:     str("fileName"),
+		Kind:         str("kind"),
+		Text:         str("text"),
+		Speaker:      str("speaker"),
+		Speakers:     list("speakers"),
+		Tags:         list("tags"),
+		Timestamp:    str("timestamp"),
+		SegmentStart: num("segmentStart"),
+		SegmentEnd:   num("segmentEnd"),
+	}
+	if t, err := time.Parse(time.RFC3339, str("createdAt")); err == nil {
+		doc.CreatedAt = t
+	}
+	return doc
+}
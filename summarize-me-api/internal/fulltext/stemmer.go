@@ -0,0 +1,232 @@
+package fulltext
+
+import (
+	"strings"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+)
+
+// StemmerName adalah nama token filter stemmer bahasa Indonesia di registry
+// Bleve.
+const StemmerName = "stemmer_id"
+
+// Penanda imbuhan yang sudah dibuang, untuk aturan kombinasi imbuhan yang
+// tidak diperbolehkan (mis. ke-...-kan tidak membuang -kan setelah ke-).
+const (
+	removedKe = 1 << iota
+	removedPeng
+	removedDi
+	removedMeng
+	removedTer
+	removedBer
+	removedPe
+	removedPer
+)
+
+// nasalRoots adalah kata dasar berawalan m/n yang umum. Awalan me-/pe- di
+// depannya tidak meluluhkan huruf awal (memakan -> makan, bukan pakan),
+// sedangkan kata dasar lain diberi huruf awal p/t (memukul -> pukul).
+var nasalRoots = map[string]bool{
+	"maaf": true, "main": true, "makan": true, "malu": true, "mandi": true,
+	"marah": true, "masak": true, "masuk": true, "mati": true, "milik": true,
+	"mimpi": true, "minta": true, "minum": true, "mohon": true, "muat": true,
+	"mudah": true, "mula": true, "mulai": true, "muncul": true, "mundur": true,
+	"murah": true, "nanti": true, "nasihat": true, "nikah": true, "nikmat": true,
+	"nilai": true,
+}
+
+// Stem mengembalikan kata dasar dari kata berimbuhan bahasa Indonesia
+// dengan algoritma stemming ringan (Tala): partikel (-kah, -lah, -pun),
+// kata ganti milik (-ku, -mu, -nya), awalan tingkat satu (meng-, peng-, di-,
+// ter-, ke-), awalan tingkat dua (ber-, per-, pe-) lalu akhiran (-kan, -an,
+// -i). Awalan sengau memulihkan huruf awal yang luluh: meny-/peny- -> s,
+// men-/pen- -> t dan mem-/pem- -> p sebelum vokal. Kata dengan dua suku
+// kata atau kurang tidak diubah. Input harus huruf kecil.
+func Stem(word string) string {
+	s := &stemmer{word: word, syllables: countVowels(word)}
+	s.removeParticle()
+	s.removePossessive()
+
+	before := len(s.word)
+	s.removeFirstOrderPrefix()
+	if len(s.word) != before {
+		before = len(s.word)
+		s.removeSuffix()
+		if len(s.word) != before {
+			s.removeSecondOrderPrefix()
+		}
+	} else {
+		s.removeSecondOrderPrefix()
+		s.removeSuffix()
+	}
+	return s.word
+}
+
+type stemmer struct {
+	word      string
+	syllables int
+	flags     int
+	// root bernilai true jika kata dasar sudah pasti (lihat keepNasalRoot).
+	root bool
+}
+
+func (s *stemmer) canStem() bool { return !s.root && s.syllables > 2 }
+
+func (s *stemmer) trimSuffix(suffix string) bool {
+	if !s.canStem() || !strings.HasSuffix(s.word, suffix) {
+		return false
+	}
+	s.word = s.word[:len(s.word)-len(suffix)]
+	s.syllables--
+	return true
+}
+
+func (s *stemmer) trimPrefix(prefix string, flag int) bool {
+	if !strings.HasPrefix(s.word, prefix) {
+		return false
+	}
+	s.word = s.word[len(prefix):]
+	s.flags |= flag
+	s.syllables--
+	return true
+}
+
+// replacePrefix mengganti awalan yang meluluhkan huruf awal kata dasar,
+// mis. "menyapu" -> "sapu", "penulis" -> "tulis".
+func (s *stemmer) replacePrefix(prefix, initial string, flag int) bool {
+	if len(s.word) <= len(prefix) || !strings.HasPrefix(s.word, prefix) || !isVowel(s.word[len(prefix)]) {
+		return false
+	}
+	s.word = initial + s.word[len(prefix):]
+	s.flags |= flag
+	s.syllables--
+	return true
+}
+
+// keepNasalRoot membuang awalan tanpa meluluhkan huruf awal jika sisanya
+// adalah salah satu nasalRoots (dengan atau tanpa akhiran). Akhiran ikut
+// dibuang dan stemming berhenti, agar "menilai" tidak menjadi "nila".
+func (s *stemmer) keepNasalRoot(prefix string, flag int) bool {
+	if !strings.HasPrefix(s.word, prefix) {
+		return false
+	}
+	rest := s.word[len(prefix):]
+	for _, suffix := range []string{"", "kan", "an", "i"} {
+		if !strings.HasSuffix(rest, suffix) {
+			continue
+		}
+		if root := rest[:len(rest)-len(suffix)]; nasalRoots[root] {
+			s.word = root
+			s.flags |= flag
+			s.root = true
+			return true
+		}
+	}
+	return false
+}
+
+func (s *stemmer) removeParticle() {
+	_ = s.trimSuffix("kah") || s.trimSuffix("lah") || s.trimSuffix("pun")
+}
+
+func (s *stemmer) removePossessive() {
+	_ = s.trimSuffix("ku") || s.trimSuffix("mu") || s.trimSuffix("nya")
+}
+
+func (s *stemmer) removeFirstOrderPrefix() {
+	if !s.canStem() {
+		return
+	}
+	_ = s.trimPrefix("meng", removedMeng) ||
+		s.replacePrefix("meny", "s", removedMeng) ||
+		s.keepNasalRoot("me", removedMeng) ||
+		s.replacePrefix("men", "t", removedMeng) ||
+		s.trimPrefix("men", removedMeng) ||
+		s.replacePrefix("mem", "p", removedMeng) ||
+		s.trimPrefix("mem", removedMeng) ||
+		s.trimPrefix("me", removedMeng) ||
+		s.trimPrefix("peng", removedPeng) ||
+		s.replacePrefix("peny", "s", removedPeng) ||
+		s.trimPrefix("peny", removedPeng) ||
+		s.keepNasalRoot("pe", removedPeng) ||
+		s.replacePrefix("pen", "t", removedPeng) ||
+		s.trimPrefix("pen", removedPeng) ||
+		s.replacePrefix("pem", "p", removedPeng) ||
+		s.trimPrefix("pem", removedPeng) ||
+		s.trimPrefix("di", removedDi) ||
+		s.trimPrefix("ter", removedTer) ||
+		s.trimPrefix("ke", removedKe)
+}
+
+func (s *stemmer) removeSecondOrderPrefix() {
+	if !s.canStem() {
+		return
+	}
+	switch {
+	case s.word == "belajar" || s.word == "pelajar":
+		s.word = s.word[3:]
+		s.syllables--
+	case strings.HasPrefix(s.word, "ber"):
+		s.trimPrefix("ber", removedBer)
+	case len(s.word) > 4 && strings.HasPrefix(s.word, "be") && !isVowel(s.word[2]) && s.word[3:5] == "er":
+		s.trimPrefix("be", removedBer)
+	case strings.HasPrefix(s.word, "per"):
+		s.trimPrefix("per", removedPer)
+	case strings.HasPrefix(s.word, "pe"):
+		s.trimPrefix("pe", removedPe)
+	}
+}
+
+func (s *stemmer) removeSuffix() {
+	switch {
+	// Konfiks per-an/pe-an jauh lebih umum daripada per-kan, jadi
+	// "perbaikan" menjadi "baik", bukan "bai".
+	case strings.HasSuffix(s.word, "kan") && s.flags&(removedKe|removedPeng|removedPe|removedPer) == 0:
+		s.trimSuffix("kan")
+	case strings.HasSuffix(s.word, "an") && s.flags&(removedMeng|removedDi|removedTer) == 0:
+		s.trimSuffix("an")
+	case strings.HasSuffix(s.word, "i") && !strings.HasSuffix(s.word, "si") && s.flags&(removedBer|removedKe|removedPeng) == 0:
+		s.trimSuffix("i")
+	}
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	}
+	return false
+}
+
+func countVowels(word string) int {
+	n := 0
+	for i := 0; i < len(word); i++ {
+		if isVowel(word[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+// stemFilter adalah token filter Bleve yang menerapkan Stem pada tiap token.
+type stemFilter struct{}
+
+func (stemFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		if token.KeyWord {
+			continue
+		}
+		token.Term = []byte(Stem(string(token.Term)))
+	}
+	return input
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(StemmerName, func(map[string]interface{}, *registry.Cache) (analysis.TokenFilter, error) {
+		return stemFilter{}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
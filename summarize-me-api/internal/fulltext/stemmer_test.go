@@ -0,0 +1,57 @@
+package fulltext
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		// Partikel dan kata ganti milik
+		{"bukunya", "buku"},
+		{"rapatlah", "rapat"},
+		// Kata pendek tidak diubah
+		{"rapat", "rapat"},
+		{"data", "data"},
+		// di-, ter-, ke-, ber-
+		{"dipukul", "pukul"},
+		{"dibahas", "bahas"},
+		{"terjual", "jual"},
+		{"bermain", "main"},
+		{"kesepakatan", "sepakat"},
+		// meng-/peng-
+		{"menggunakan", "guna"},
+		{"pengguna", "guna"},
+		// meny-/peny- -> s
+		{"menyapu", "sapu"},
+		{"penyusunan", "susun"},
+		// men-/pen- + vokal -> t
+		{"menulis", "tulis"},
+		{"menuliskan", "tulis"},
+		{"penulis", "tulis"},
+		{"menonton", "tonton"},
+		// mem-/pem- + vokal -> p
+		{"memukul", "pukul"},
+		{"memeriksa", "periksa"},
+		{"pemakai", "pakai"},
+		{"pemimpin", "pimpin"},
+		// mem-/pem- + konsonan
+		{"membaca", "baca"},
+		{"pembahasan", "bahas"},
+		{"memperbaiki", "baik"},
+		// Kata dasar berawalan m/n tidak diluluhkan
+		{"memakan", "makan"},
+		{"memasukkan", "masuk"},
+		{"menilai", "nilai"},
+		{"pemain", "main"},
+		// per-/pe- dengan akhiran -an
+		{"perbaikan", "baik"},
+		{"pertemuan", "temu"},
+		{"pelatihan", "latih"},
+	}
+	for _, tt := range tests {
+		if got := Stem(tt.word); got != tt.want {
+			t.Errorf("Stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
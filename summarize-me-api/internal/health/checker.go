@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status komponen dan status agregat.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Probe memeriksa satu dependensi. Mengembalikan error jika dependensi tidak siap.
+type Probe func(ctx context.Context) error
+
+// ComponentStatus adalah hasil probe untuk satu dependensi.
+type ComponentStatus struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
+// Report adalah hasil pemeriksaan seluruh dependensi.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+	CheckedAt  time.Time                  `json:"checkedAt"`
+}
+
+// Ready bernilai true jika semua komponen berstatus up.
+func (r Report) Ready() bool {
+	return r.Status == StatusUp
+}
+
+type namedProbe struct {
+	name  string
+	probe Probe
+}
+
+// Checker menjalankan probe secara paralel dengan timeout per probe,
+// dan menyimpan hasil terakhir selama cacheTTL agar dependensi tidak
+// dibanjiri request dari load balancer.
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	probes   []namedProbe
+
+	mu   sync.Mutex
+	last *Report
+}
+
+// NewChecker membuat Checker baru.
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Register menambahkan probe dengan nama komponen tertentu.
+// Dipanggil saat startup, sebelum Check dipakai.
+func (c *Checker) Register(name string, probe Probe) {
+	c.probes = append(c.probes, namedProbe{name: name, probe: probe})
+}
+
+// Check mengembalikan status semua komponen, memakai cache jika masih segar.
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.last != nil && time.Since(c.last.CheckedAt) < c.cacheTTL {
+		return *c.last
+	}
+
+	report := c.run(ctx)
+	c.last = &report
+	return report
+}
+
+func (c *Checker) run(ctx context.Context) Report {
+	// Hasil di-cache untuk request lain, jadi probe tidak boleh ikut batal
+	// hanya karena klien yang memicunya memutus koneksi.
+	ctx = context.WithoutCancel(ctx)
+	results := make([]ComponentStatus, len(c.probes))
+
+	var wg sync.WaitGroup
+	for i, p := range c.probes {
+		wg.Add(1)
+		go func(i int, p namedProbe) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := p.probe(probeCtx)
+			status := ComponentStatus{Status: StatusUp, LatencyMs: time.Since(start).Milliseconds()}
+			if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+			results[i] = status
+		}(i, p)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]ComponentStatus, len(c.probes)),
+		CheckedAt:  time.Now(),
+	}
+	for i, p := range c.probes {
+		report.Components[p.name] = results[i]
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"summarize-me-api/internal/media"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	speech "cloud.google.com/go/speech/apiv1"
+	"cloud.google.com/go/storage"
+	"firebase.google.com/go/v4/auth"
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FirebaseAuthProbe memastikan kredensial Firebase Admin valid dengan mencari
+// user yang pasti tidak ada. "User not found" berarti API bisa dijangkau.
+func FirebaseAuthProbe(client *auth.Client) Probe {
+	return func(ctx context.Context) error {
+		_, err := client.GetUser(ctx, "readiness-probe")
+		if err == nil || auth.IsUserNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("firebase auth tidak bisa dijangkau: %w", err)
+	}
+}
+
+// SpeechProbe memanggil GetOperation dengan nama fiktif. NotFound/InvalidArgument
+// berarti koneksi dan autentikasi ke Speech-to-Text berhasil.
+func SpeechProbe(client *speech.Client) Probe {
+	return func(ctx context.Context) error {
+		_, err := client.GetOperation(ctx, &longrunningpb.GetOperationRequest{Name: "readiness-probe"})
+		switch status.Code(err) {
+		case codes.OK, codes.NotFound, codes.InvalidArgument:
+			return nil
+		}
+		return fmt.Errorf("speech-to-text tidak bisa dijangkau: %w", err)
+	}
+}
+
+// GeminiProbe mengambil metadata model, yang juga memvalidasi API key.
+func GeminiProbe(model *genai.GenerativeModel) Probe {
+	return func(ctx context.Context) error {
+		if _, err := model.Info(ctx); err != nil {
+			return fmt.Errorf("gemini tidak bisa dijangkau: %w", err)
+		}
+		return nil
+	}
+}
+
+// BucketProbe memastikan bucket GCS ada dan bisa diakses.
+func BucketProbe(client *storage.Client, bucketName string) Probe {
+	return func(ctx context.Context) error {
+		if _, err := client.Bucket(bucketName).Attrs(ctx); err != nil {
+			return fmt.Errorf("bucket %s tidak bisa diakses: %w", bucketName, err)
+		}
+		return nil
+	}
+}
+
+// FFmpegProbe memastikan binary ffmpeg tersedia untuk file video.
+func FFmpegProbe(transcoder *media.Transcoder) Probe {
+	return func(ctx context.Context) error {
+		return transcoder.Available()
+	}
+}
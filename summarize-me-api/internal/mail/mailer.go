@@ -0,0 +1,150 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attachment adalah file yang dilampirkan ke email.
+type Attachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Message adalah email yang akan dikirim.
+type Message struct {
+	To          []string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Options mengatur koneksi ke server SMTP.
+type Options struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Mailer mengirim email lewat SMTP. STARTTLS dipakai otomatis jika server
+// mendukungnya; tanpa username, tidak ada AUTH (cocok untuk MailHog lokal).
+type Mailer struct {
+	opts Options
+}
+
+// NewMailer membuat Mailer baru.
+func NewMailer(opts Options) *Mailer {
+	return &Mailer{opts: opts}
+}
+
+// Send membangun pesan MIME dan mengirimnya ke semua penerima.
+func (m *Mailer) Send(msg Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("email tanpa penerima")
+	}
+	body, err := m.build(msg)
+	if err != nil {
+		return err
+	}
+
+	// Envelope sender harus alamat polos, tanpa nama tampilan.
+	from, err := netmail.ParseAddress(m.opts.From)
+	if err != nil {
+		return fmt.Errorf("alamat pengirim tidak valid: %w", err)
+	}
+
+	addr := net.JoinHostPort(m.opts.Host, strconv.Itoa(m.opts.Port))
+	var auth smtp.Auth
+	if m.opts.Username != "" {
+		auth = smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)
+	}
+	if err := smtp.SendMail(addr, auth, from.Address, msg.To, body); err != nil {
+		return fmt.Errorf("gagal mengirim email via %s: %w", addr, err)
+	}
+	return nil
+}
+
+// build menyusun multipart/mixed berisi multipart/alternative (teks + HTML)
+// diikuti lampiran.
+func (m *Mailer) build(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	mixed := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.opts.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary())
+
+	var altBuf bytes.Buffer
+	alt := multipart.NewWriter(&altBuf)
+	if err := writePart(alt, "text/plain; charset=utf-8", nil, []byte(msg.TextBody)); err != nil {
+		return nil, err
+	}
+	if msg.HTMLBody != "" {
+		if err := writePart(alt, "text/html; charset=utf-8", nil, []byte(msg.HTMLBody)); err != nil {
+			return nil, err
+		}
+	}
+	if err := alt.Close(); err != nil {
+		return nil, err
+	}
+	altHeader := textproto.MIMEHeader{"Content-Type": {"multipart/alternative; boundary=" + alt.Boundary()}}
+	altPart, err := mixed.CreatePart(altHeader)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := altPart.Write(altBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": a.FileName})
+		if err := writePart(mixed, a.ContentType, textproto.MIMEHeader{"Content-Disposition": {disposition}}, a.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writePart menulis satu bagian MIME dengan encoding base64.
+func writePart(w *multipart.Writer, contentType string, extra textproto.MIMEHeader, data []byte) error {
+	header := textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	for k, v := range extra {
+		header[k] = v
+	}
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := fmt.Fprintf(part, "%s\r\n", encoded[:76]); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = fmt.Fprintf(part, "%s\r\n", encoded)
+	return err
+}
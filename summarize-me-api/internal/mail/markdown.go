@@ -0,0 +1,74 @@
+package mail
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	boldPattern     = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	italicPattern   = regexp.MustCompile(`\*(.+?)\*`)
+	orderedPattern  = regexp.MustCompile(`^\d+[.)]\s+`)
+	headingPatterns = []string{"### ", "## ", "# "}
+)
+
+// MarkdownToHTML mengubah subset Markdown yang dihasilkan Gemini (heading,
+// daftar, paragraf, bold/italic) menjadi HTML sederhana untuk email.
+// Semua teks di-escape, jadi aman dari HTML injection.
+func MarkdownToHTML(md string) string {
+	var b strings.Builder
+	listTag := ""
+	closeList := func() {
+		if listTag != "" {
+			b.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			b.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for _, raw := range strings.Split(md, "\n") {
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "":
+			closeList()
+		case isHeading(line):
+			closeList()
+			level := strings.Count(strings.SplitN(line, " ", 2)[0], "#")
+			tag := "h" + string(rune('0'+level))
+			b.WriteString("<" + tag + ">" + inline(strings.TrimLeft(line, "# ")) + "</" + tag + ">\n")
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			openList("ul")
+			b.WriteString("<li>" + inline(line[2:]) + "</li>\n")
+		case orderedPattern.MatchString(line):
+			openList("ol")
+			b.WriteString("<li>" + inline(orderedPattern.ReplaceAllString(line, "")) + "</li>\n")
+		default:
+			closeList()
+			b.WriteString("<p>" + inline(line) + "</p>\n")
+		}
+	}
+	closeList()
+	return b.String()
+}
+
+func isHeading(line string) bool {
+	for _, prefix := range headingPatterns {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func inline(text string) string {
+	text = html.EscapeString(text)
+	text = boldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	return italicPattern.ReplaceAllString(text, "<em>$1</em>")
+}
@@ -0,0 +1,160 @@
+// Package media mengekstrak track audio dari file video memakai ffmpeg.
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VideoExtensions adalah container video yang diterima.
+var VideoExtensions = []string{".mp4", ".webm", ".mkv", ".mov"}
+
+// Options mengatur Transcoder.
+type Options struct {
+	FFmpegPath  string
+	FFprobePath string
+	// SampleRateHertz adalah sample rate audio hasil ekstraksi.
+	SampleRateHertz int
+	// Keyframes mengaktifkan pencatatan timestamp keyframe video.
+	Keyframes bool
+	// KeyframeInterval adalah jarak minimal antar keyframe yang dicatat.
+	KeyframeInterval time.Duration
+}
+
+// Result adalah audio hasil ekstraksi dan (opsional) keyframe video.
+type Result struct {
+	// Audio berformat FLAC mono.
+	Audio []byte
+	// Keyframes berisi timestamp keyframe dalam detik dari awal video.
+	Keyframes []float64
+}
+
+// Transcoder menjalankan ffmpeg/ffprobe sebagai proses terpisah.
+type Transcoder struct {
+	opts Options
+}
+
+// NewTranscoder membuat instance baru dari Transcoder.
+func NewTranscoder(opts Options) *Transcoder {
+	if opts.SampleRateHertz <= 0 {
+		opts.SampleRateHertz = 16000
+	}
+	return &Transcoder{opts: opts}
+}
+
+// IsVideo mengecek ekstensi file terhadap VideoExtensions.
+func IsVideo(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, v := range VideoExtensions {
+		if ext == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Available memastikan binary ffmpeg (dan ffprobe jika keyframe aktif)
+// ada di PATH.
+func (t *Transcoder) Available() error {
+	if _, err := exec.LookPath(t.opts.FFmpegPath); err != nil {
+		return fmt.Errorf("ffmpeg tidak ditemukan: %w", err)
+	}
+	if t.opts.Keyframes {
+		if _, err := exec.LookPath(t.opts.FFprobePath); err != nil {
+			return fmt.Errorf("ffprobe tidak ditemukan: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExtractAudio mengambil track audio pertama, di-downmix ke mono dan
+// di-resample. Video ditulis ke file sementara karena container seperti MP4
+// butuh seek yang tidak bisa dilakukan lewat stdin.
+func (t *Transcoder) ExtractAudio(ctx context.Context, data []byte, fileName string) (*Result, error) {
+	dir, err := os.MkdirTemp("", "summarize-media-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "input"+strings.ToLower(filepath.Ext(fileName)))
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, err
+	}
+	output := filepath.Join(dir, "audio.flac")
+
+	_, err = run(ctx, t.opts.FFmpegPath,
+		"-nostdin", "-hide_banner", "-loglevel", "error",
+		"-i", input,
+		"-map", "0:a:0", "-vn",
+		"-ac", "1", "-ar", strconv.Itoa(t.opts.SampleRateHertz),
+		"-c:a", "flac", output,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengekstrak audio dari %s: %w", fileName, err)
+	}
+	audio, err := os.ReadFile(output)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Audio: audio}
+	if t.opts.Keyframes {
+		// Keyframe bersifat pelengkap; kegagalannya tidak menggagalkan job.
+		if result.Keyframes, err = t.keyframes(ctx, input); err != nil {
+			result.Keyframes = nil
+		}
+	}
+	return result, nil
+}
+
+// keyframes membaca timestamp keyframe video lalu menjarangkannya sesuai
+// KeyframeInterval agar daftar tetap ringkas untuk rekaman panjang.
+func (t *Transcoder) keyframes(ctx context.Context, input string) ([]float64, error) {
+	out, err := run(ctx, t.opts.FFprobePath,
+		"-v", "error", "-select_streams", "v:0", "-skip_frame", "nokey",
+		"-show_entries", "frame=pts_time", "-of", "csv=p=0", input,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := t.opts.KeyframeInterval.Seconds()
+	var (
+		frames []float64
+		last   = -interval
+	)
+	for _, line := range strings.Split(string(out), "\n") {
+		ts, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(line, ",")), 64)
+		if err != nil {
+			continue
+		}
+		if ts-last >= interval {
+			frames = append(frames, ts)
+			last = ts
+		}
+	}
+	return frames, nil
+}
+
+func run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 500 {
+			msg = msg[len(msg)-500:]
+		}
+		return nil, fmt.Errorf("%s: %w: %s", filepath.Base(name), err, msg)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,348 @@
+// Package remote mengunduh audio dari URL publik (link langsung atau
+// enclosure RSS podcast) dengan batas ukuran, cek content-type dan
+// perlindungan SSRF.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrInvalidURL dikembalikan jika URL tidak bisa dipakai (skema, host).
+	ErrInvalidURL = errors.New("URL sumber tidak valid")
+	// ErrBlockedAddress dikembalikan jika host mengarah ke alamat internal.
+	ErrBlockedAddress = errors.New("alamat tujuan tidak diizinkan")
+	// ErrTooLarge dikembalikan jika file melebihi batas ukuran.
+	ErrTooLarge = errors.New("file sumber terlalu besar")
+	// ErrUnsupportedType dikembalikan jika content-type bukan audio atau RSS.
+	ErrUnsupportedType = errors.New("tipe konten sumber tidak didukung")
+)
+
+// maxFeedBytes membatasi ukuran dokumen RSS.
+const maxFeedBytes = 10 << 20
+
+// maxRedirects membatasi jumlah redirect yang diikuti.
+const maxRedirects = 5
+
+// Options mengatur Fetcher.
+type Options struct {
+	MaxBytes int64
+	Timeout  time.Duration
+	// AllowPrivate mematikan blokir alamat internal, hanya untuk development.
+	AllowPrivate bool
+}
+
+// Audio adalah file audio hasil unduhan.
+type Audio struct {
+	SourceURL   string
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// Fetcher mengunduh audio dari URL remote.
+type Fetcher struct {
+	client *http.Client
+	opts   Options
+}
+
+// NewFetcher membuat Fetcher. Pengecekan alamat dilakukan saat dial, setelah
+// DNS di-resolve, sehingga redirect dan DNS rebinding ikut tertahan.
+func NewFetcher(opts Options) *Fetcher {
+	return &Fetcher{client: NewClient(opts.Timeout, opts.AllowPrivate), opts: opts}
+}
+
+// NewClient membuat http.Client yang menolak alamat internal saat dial dan
+// memvalidasi ulang setiap redirect. Dipakai juga untuk request keluar lain
+// ke URL milik user, seperti webhook.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isBlocked(ip) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return nil
+		}
+	}
+	transport := &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("terlalu banyak redirect")
+			}
+			_, err := ValidateURL(req.URL.String())
+			return err
+		},
+	}
+}
+
+// ValidateURL memeriksa skema dan host URL sebelum diunduh.
+func ValidateURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: hanya http dan https yang didukung", ErrInvalidURL)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("%w: host kosong", ErrInvalidURL)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("%w: kredensial di URL tidak diizinkan", ErrInvalidURL)
+	}
+	return u, nil
+}
+
+// Check memvalidasi URL dan me-resolve host-nya lebih awal, agar URL ke
+// alamat internal bisa langsung ditolak saat request dibuat. Pengecekan
+// saat dial tetap berlaku ketika audio benar-benar diunduh.
+func (f *Fetcher) Check(ctx context.Context, rawURL string) error {
+	u, err := ValidateURL(rawURL)
+	if err != nil {
+		return err
+	}
+	if f.opts.AllowPrivate {
+		return nil
+	}
+	return CheckHost(ctx, u)
+}
+
+// CheckHost me-resolve host URL dan menolaknya jika salah satu alamatnya
+// termasuk jaringan internal.
+func CheckHost(ctx context.Context, u *url.URL) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("%w: host %s tidak bisa di-resolve", ErrInvalidURL, u.Hostname())
+	}
+	for _, addr := range addrs {
+		if isBlocked(addr.IP) {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, u.Hostname())
+		}
+	}
+	return nil
+}
+
+// FetchAudio mengunduh audio dari rawURL. Jika URL adalah feed RSS, item
+// dengan GUID itemGUID (atau item terbaru jika kosong) dipilih dan
+// enclosure-nya diunduh.
+func (f *Fetcher) FetchAudio(ctx context.Context, rawURL, itemGUID string) (*Audio, error) {
+	u, err := ValidateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	contentType := mediaType(resp.Header.Get("Content-Type"))
+	if isFeed(contentType) {
+		feed, err := readLimited(resp.Body, maxFeedBytes)
+		if err != nil {
+			return nil, err
+		}
+		item, err := pickItem(feed, itemGUID)
+		if err != nil {
+			return nil, err
+		}
+		enclosure, err := u.Parse(item.Enclosure.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: enclosure RSS: %v", ErrInvalidURL, err)
+		}
+		if _, err := ValidateURL(enclosure.String()); err != nil {
+			return nil, err
+		}
+		audio, err := f.fetchDirect(ctx, enclosure.String())
+		if err != nil {
+			return nil, err
+		}
+		if item.Title != "" {
+			audio.FileName = sanitizeName(item.Title) + path.Ext(audio.FileName)
+		}
+		return audio, nil
+	}
+	return f.readAudio(resp, u.String())
+}
+
+func (f *Fetcher) fetchDirect(ctx context.Context, rawURL string) (*Audio, error) {
+	resp, err := f.get(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return f.readAudio(resp, rawURL)
+}
+
+func (f *Fetcher) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	req.Header.Set("User-Agent", "SummarizeMe-Fetcher/1.0")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, ErrBlockedAddress
+		}
+		return nil, fmt.Errorf("gagal mengunduh %s: %w", rawURL, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("gagal mengunduh %s: status %d", rawURL, resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (f *Fetcher) readAudio(resp *http.Response, rawURL string) (*Audio, error) {
+	contentType := mediaType(resp.Header.Get("Content-Type"))
+	fileName := path.Base(resp.Request.URL.Path)
+	ext := audioExtension(contentType, fileName)
+	if ext == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	if resp.ContentLength > f.opts.MaxBytes {
+		return nil, fmt.Errorf("%w: %d MB, batas %d MB", ErrTooLarge, resp.ContentLength>>20, f.opts.MaxBytes>>20)
+	}
+	data, err := readLimited(resp.Body, f.opts.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if fileName == "" || fileName == "/" || fileName == "." {
+		fileName = "audio"
+	}
+	fileName = strings.TrimSuffix(sanitizeName(fileName), path.Ext(fileName)) + ext
+	return &Audio{SourceURL: rawURL, FileName: fileName, ContentType: contentType, Data: data}, nil
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca respons: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: batas %d MB", ErrTooLarge, limit>>20)
+	}
+	return data, nil
+}
+
+func mediaType(header string) string {
+	mt, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(header))
+	}
+	return mt
+}
+
+func isFeed(contentType string) bool {
+	switch contentType {
+	case "application/rss+xml", "application/xml", "text/xml", "application/atom+xml":
+		return true
+	}
+	return false
+}
+
+// audioContentTypes memetakan content-type audio dan video ke ekstensi yang
+// dikenali pipeline transkripsi.
+var audioContentTypes = map[string]string{
+	"audio/mpeg":       ".mp3",
+	"audio/mp3":        ".mp3",
+	"audio/mp4":        ".m4a",
+	"audio/x-m4a":      ".m4a",
+	"audio/aac":        ".m4a",
+	"audio/wav":        ".wav",
+	"audio/x-wav":      ".wav",
+	"audio/wave":       ".wav",
+	"audio/flac":       ".flac",
+	"audio/x-flac":     ".flac",
+	"audio/ogg":        ".ogg",
+	"audio/opus":       ".ogg",
+	"video/mp4":        ".mp4",
+	"video/webm":       ".webm",
+	"video/x-matroska": ".mkv",
+	"video/quicktime":  ".mov",
+}
+
+// audioExtension menentukan ekstensi dari content-type. Server yang
+// mengirim application/octet-stream diterima jika ekstensi URL-nya audio.
+func audioExtension(contentType, fileName string) string {
+	if ext, ok := audioContentTypes[contentType]; ok {
+		return ext
+	}
+	if contentType == "application/octet-stream" || contentType == "binary/octet-stream" || contentType == "" {
+		ext := strings.ToLower(path.Ext(fileName))
+		for _, known := range audioContentTypes {
+			if ext == known {
+				return ext
+			}
+		}
+	}
+	return ""
+}
+
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == '"' || r < 32 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if len(name) > 120 {
+		name = name[:120]
+	}
+	return name
+}
+
+// blockedNetworks adalah rentang alamat yang tidak boleh dihubungi server:
+// private, loopback, link-local (termasuk metadata cloud), CGNAT, dll.
+var blockedNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+		"172.16.0.0/12", "192.0.0.0/24", "192.168.0.0/16", "198.18.0.0/15", "224.0.0.0/4", "240.0.0.0/4",
+		"::/128", "::1/128", "fc00::/7", "fe80::/10", "ff00::/8", "64:ff9b::/96",
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+func isBlocked(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, n := range blockedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
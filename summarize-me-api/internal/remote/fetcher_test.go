@@ -0,0 +1,234 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// allowLoopback mengeluarkan 127.0.0.0/8 dari blockedNetworks selama test,
+// agar server httptest bisa menjadi hop pertama yang "publik" sementara
+// alamat internal lain tetap diblokir saat dial.
+func allowLoopback(t *testing.T) {
+	t.Helper()
+	orig := blockedNetworks
+	t.Cleanup(func() { blockedNetworks = orig })
+	var nets []*net.IPNet
+	for _, n := range orig {
+		if n.String() != "127.0.0.0/8" {
+			nets = append(nets, n)
+		}
+	}
+	blockedNetworks = nets
+}
+
+func newTestFetcher(allowPrivate bool) *Fetcher {
+	return NewFetcher(Options{MaxBytes: 1 << 10, Timeout: 5 * time.Second, AllowPrivate: allowPrivate})
+}
+
+func audioHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "audio/mpeg")
+		io.WriteString(w, body)
+	}
+}
+
+func TestIsBlocked(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.5.4", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"fe80::1", true},
+		{"fd00:ec2::254", true},
+		{"8.8.8.8", false},
+		{"172.32.0.1", false},
+		{"2001:4860:4860::8888", false},
+	}
+	for _, tt := range tests {
+		if got := isBlocked(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isBlocked(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestFetchBlocksInternalAddressesAtDial(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		audioHandler("rahasia")(w, r)
+	}))
+	defer srv.Close()
+
+	// Alamat selain loopback tidak benar-benar dihubungi: Control menolak
+	// sebelum connect.
+	targets := []string{
+		srv.URL + "/audio.mp3",
+		fmt.Sprintf("http://localhost:%d/audio.mp3", srv.Listener.Addr().(*net.TCPAddr).Port),
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/audio.mp3",
+		"http://192.168.0.10:8080/audio.mp3",
+		"http://0.0.0.0/audio.mp3",
+	}
+	f := newTestFetcher(false)
+	for _, target := range targets {
+		t.Run(target, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := f.FetchAudio(ctx, target, ""); !errors.Is(err, ErrBlockedAddress) {
+				t.Errorf("FetchAudio error = %v, want ErrBlockedAddress", err)
+			}
+		})
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("server internal dihubungi %d kali", n)
+	}
+}
+
+func TestFetchBlocksRedirectToInternalAddress(t *testing.T) {
+	allowLoopback(t)
+
+	targets := []string{
+		"http://169.254.169.254/latest/meta-data/iam/security-credentials/",
+		"http://10.0.0.1/admin",
+		"http://172.16.0.1/",
+		"http://192.168.1.1/",
+	}
+	for _, target := range targets {
+		t.Run(target, func(t *testing.T) {
+			srv := httptest.NewServer(http.RedirectHandler(target, http.StatusFound))
+			defer srv.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := newTestFetcher(false).FetchAudio(ctx, srv.URL+"/podcast.mp3", ""); !errors.Is(err, ErrBlockedAddress) {
+				t.Errorf("FetchAudio error = %v, want ErrBlockedAddress", err)
+			}
+		})
+	}
+}
+
+func TestFetchFollowsRedirect(t *testing.T) {
+	allowLoopback(t)
+	mux := http.NewServeMux()
+	mux.Handle("/go", http.RedirectHandler("/files/rapat.mp3", http.StatusFound))
+	mux.HandleFunc("/files/rapat.mp3", audioHandler("audio"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	audio, err := newTestFetcher(false).FetchAudio(context.Background(), srv.URL+"/go", "")
+	if err != nil {
+		t.Fatalf("FetchAudio: %v", err)
+	}
+	if string(audio.Data) != "audio" || audio.FileName != "rapat.mp3" || audio.ContentType != "audio/mpeg" {
+		t.Errorf("audio = %+v", audio)
+	}
+}
+
+func TestFetchSizeLimit(t *testing.T) {
+	big := strings.Repeat("a", 2<<10)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"content-length terlalu besar", audioHandler(big)},
+		{"chunked tanpa content-length", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "audio/mpeg")
+			for i := 0; i < 4; i++ {
+				io.WriteString(w, big[:1<<9+1])
+				w.(http.Flusher).Flush()
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+			if _, err := newTestFetcher(true).FetchAudio(context.Background(), srv.URL+"/a.mp3", ""); !errors.Is(err, ErrTooLarge) {
+				t.Errorf("FetchAudio error = %v, want ErrTooLarge", err)
+			}
+		})
+	}
+
+	srv := httptest.NewServer(audioHandler(big[:1<<10]))
+	defer srv.Close()
+	if _, err := newTestFetcher(true).FetchAudio(context.Background(), srv.URL+"/a.mp3", ""); err != nil {
+		t.Errorf("FetchAudio tepat di batas: %v", err)
+	}
+}
+
+func TestFetchRejectsUnsupportedType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		io.WriteString(w, "<html></html>")
+	}))
+	defer srv.Close()
+	if _, err := newTestFetcher(true).FetchAudio(context.Background(), srv.URL+"/index.html", ""); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("FetchAudio error = %v, want ErrUnsupportedType", err)
+	}
+}
+
+const testFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel>
+<title>Podcast Rapat</title>
+<item><title>Pengumuman tanpa audio</title><guid>news-1</guid></item>
+<item><title>Episode 3: Evaluasi/Q2</title><guid isPermaLink="false"> ep-3 </guid><enclosure url="/audio/ep3.mp3" type="audio/mpeg" length="3"/></item>
+<item><title>Episode 2</title><guid>ep-2</guid><enclosure url="%s/audio/ep2" type="audio/mpeg" length="3"/></item>
+</channel></rss>`
+
+func TestFetchRSS(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		fmt.Fprintf(w, testFeed, srv.URL)
+	})
+	mux.HandleFunc("/audio/ep3.mp3", audioHandler("ep3"))
+	mux.HandleFunc("/audio/ep2", audioHandler("ep2"))
+
+	tests := []struct {
+		name     string
+		guid     string
+		wantData string
+		wantName string
+	}{
+		{"episode terbaru", "", "ep3", "Episode 3: Evaluasi_Q2.mp3"},
+		{"guid tertentu", "ep-2", "ep2", "Episode 2.mp3"},
+		{"guid dengan spasi di feed", "ep-3", "ep3", "Episode 3: Evaluasi_Q2.mp3"},
+	}
+	f := newTestFetcher(true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audio, err := f.FetchAudio(context.Background(), srv.URL+"/feed.xml", tt.guid)
+			if err != nil {
+				t.Fatalf("FetchAudio: %v", err)
+			}
+			if string(audio.Data) != tt.wantData || audio.FileName != tt.wantName {
+				t.Errorf("audio = %q %q, want %q %q", audio.FileName, audio.Data, tt.wantName, tt.wantData)
+			}
+		})
+	}
+
+	for _, guid := range []string{"news-1", "tidak-ada"} {
+		if _, err := f.FetchAudio(context.Background(), srv.URL+"/feed.xml", guid); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("guid %q: error = %v, want ErrInvalidURL", guid, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package remote
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type rssFeed struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title     string `xml:"title"`
+	GUID      string `xml:"guid"`
+	Enclosure struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+// pickItem memilih item RSS dengan GUID tertentu, atau item pertama yang
+// punya enclosure (feed podcast biasanya terurut dari episode terbaru).
+func pickItem(data []byte, guid string) (rssItem, error) {
+	var feed rssFeed
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return rssItem{}, fmt.Errorf("%w: feed RSS tidak bisa dibaca: %v", ErrUnsupportedType, err)
+	}
+	for _, item := range feed.Channel.Items {
+		if item.Enclosure.URL == "" {
+			continue
+		}
+		if guid == "" || strings.TrimSpace(item.GUID) == guid {
+			return item, nil
+		}
+	}
+	if guid != "" {
+		return rssItem{}, fmt.Errorf("%w: item RSS dengan guid %q tidak ditemukan", ErrInvalidURL, guid)
+	}
+	return rssItem{}, fmt.Errorf("%w: feed RSS tidak punya enclosure audio", ErrInvalidURL)
+}
@@ -0,0 +1,115 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen dikembalikan tanpa memanggil provider selama circuit
+// breaker terbuka.
+var ErrCircuitOpen = errors.New("provider sedang tidak tersedia (circuit breaker terbuka)")
+
+// State circuit breaker.
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// BreakerOptions mengatur circuit breaker. Threshold 0 mematikan breaker.
+type BreakerOptions struct {
+	// Threshold adalah jumlah kegagalan sementara berturut-turut yang
+	// membuka breaker.
+	Threshold int
+	// Cooldown adalah lama breaker terbuka sebelum satu panggilan percobaan
+	// (half-open) diizinkan.
+	Cooldown time.Duration
+}
+
+// breaker adalah circuit breaker sederhana berbasis kegagalan berturut-turut.
+// Hanya kegagalan sementara yang dihitung; error permanen (mis. input tidak
+// valid) tidak menandakan provider bermasalah.
+type breaker struct {
+	opts BreakerOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openUntil time.Time
+	trial     bool // sudah ada panggilan percobaan saat half-open
+	opens     int64
+}
+
+func newBreaker(opts BreakerOptions) *breaker {
+	return &breaker{opts: opts, now: time.Now, state: StateClosed}
+}
+
+// allow mengecek apakah panggilan boleh dilakukan.
+func (b *breaker) allow() error {
+	if b.opts.Threshold <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateOpen:
+		if b.now().Before(b.openUntil) {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.trial = true
+		return nil
+	case StateHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.trial = true
+	}
+	return nil
+}
+
+// record mencatat hasil panggilan. transientFailure false berarti berhasil
+// atau gagal permanen.
+func (b *breaker) record(transientFailure bool) {
+	if b.opts.Threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !transientFailure {
+		b.state, b.failures, b.trial = StateClosed, 0, false
+		return
+	}
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.opts.Threshold {
+		b.state = StateOpen
+		b.openUntil = b.now().Add(b.opts.Cooldown)
+		b.trial = false
+		b.opens++
+	}
+}
+
+// release membatalkan panggilan percobaan yang tidak menghasilkan apa pun
+// tentang provider (mis. dibatalkan pemanggil), agar panggilan berikutnya
+// boleh menjadi percobaan half-open.
+func (b *breaker) release() {
+	if b.opts.Threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateHalfOpen {
+		b.trial = false
+	}
+}
+
+func (b *breaker) snapshot() (state string, opens int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && !b.now().Before(b.openUntil) {
+		return StateHalfOpen, b.opens
+	}
+	return b.state, b.opens
+}
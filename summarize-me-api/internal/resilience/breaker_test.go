@@ -0,0 +1,134 @@
+package resilience
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock adalah jam manual untuk menguji cooldown breaker.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(threshold int) (*breaker, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := newBreaker(BreakerOptions{Threshold: threshold, Cooldown: time.Minute})
+	b.now = clock.now
+	return b, clock
+}
+
+func TestBreaker(t *testing.T) {
+	// step adalah satu langkah: aksi lalu state yang diharapkan sesudahnya.
+	type step struct {
+		action    string // "allow", "ok", "fail", "permanent", "release" atau "wait"
+		wantErr   bool   // untuk "allow"
+		wantState string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "terbuka setelah threshold kegagalan berturut-turut",
+			steps: []step{
+				{action: "allow", wantState: StateClosed},
+				{action: "fail", wantState: StateClosed},
+				{action: "allow", wantState: StateClosed},
+				{action: "fail", wantState: StateClosed},
+				{action: "allow", wantState: StateClosed},
+				{action: "fail", wantState: StateOpen},
+				{action: "allow", wantErr: true, wantState: StateOpen},
+			},
+		},
+		{
+			name: "berhasil dan gagal permanen mereset hitungan",
+			steps: []step{
+				{action: "fail", wantState: StateClosed},
+				{action: "fail", wantState: StateClosed},
+				{action: "ok", wantState: StateClosed},
+				{action: "fail", wantState: StateClosed},
+				{action: "fail", wantState: StateClosed},
+				{action: "permanent", wantState: StateClosed},
+				{action: "fail", wantState: StateClosed},
+				{action: "allow", wantState: StateClosed},
+			},
+		},
+		{
+			name: "percobaan half-open berhasil menutup breaker",
+			steps: []step{
+				{action: "fail"}, {action: "fail"}, {action: "fail", wantState: StateOpen},
+				{action: "wait", wantState: StateHalfOpen},
+				{action: "allow", wantState: StateHalfOpen},
+				// Hanya satu percobaan sekaligus selama half-open.
+				{action: "allow", wantErr: true, wantState: StateHalfOpen},
+				{action: "ok", wantState: StateClosed},
+				{action: "allow", wantState: StateClosed},
+			},
+		},
+		{
+			name: "percobaan half-open gagal membuka lagi",
+			steps: []step{
+				{action: "fail"}, {action: "fail"}, {action: "fail", wantState: StateOpen},
+				{action: "wait"},
+				{action: "allow", wantState: StateHalfOpen},
+				{action: "fail", wantState: StateOpen},
+				{action: "allow", wantErr: true, wantState: StateOpen},
+			},
+		},
+		{
+			name: "percobaan half-open yang dibatalkan tidak menutup breaker",
+			steps: []step{
+				{action: "fail"}, {action: "fail"}, {action: "fail", wantState: StateOpen},
+				{action: "wait"},
+				{action: "allow", wantState: StateHalfOpen},
+				{action: "release", wantState: StateHalfOpen},
+				// Percobaan baru diizinkan setelah yang lama dibatalkan.
+				{action: "allow", wantState: StateHalfOpen},
+				{action: "allow", wantErr: true, wantState: StateHalfOpen},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, clock := newTestBreaker(3)
+			for i, s := range tt.steps {
+				switch s.action {
+				case "allow":
+					err := b.allow()
+					if (err != nil) != s.wantErr {
+						t.Fatalf("langkah %d: allow() = %v, wantErr %v", i, err, s.wantErr)
+					}
+					if err != nil && !errors.Is(err, ErrCircuitOpen) {
+						t.Fatalf("langkah %d: allow() = %v, want ErrCircuitOpen", i, err)
+					}
+				case "ok", "permanent":
+					b.record(false)
+				case "fail":
+					b.record(true)
+				case "release":
+					b.release()
+				case "wait":
+					clock.advance(time.Minute)
+				}
+				if s.wantState == "" {
+					continue
+				}
+				if got, _ := b.snapshot(); got != s.wantState {
+					t.Fatalf("langkah %d (%s): state = %s, want %s", i, s.action, got, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b, _ := newTestBreaker(0)
+	for i := 0; i < 10; i++ {
+		b.record(true)
+	}
+	if err := b.allow(); err != nil {
+		t.Fatalf("allow() dengan threshold 0 = %v, want nil", err)
+	}
+}
@@ -0,0 +1,97 @@
+// Package resilience membungkus pemanggilan provider eksternal (Gemini,
+// Speech-to-Text, GCS) dengan retry berbasis klasifikasi error, exponential
+// backoff dengan jitter, circuit breaker per provider, timeout per tahap,
+// dan statistik percobaan untuk dipantau admin.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// markedError memaksa klasifikasi sebuah error.
+type markedError struct {
+	err       error
+	retryable bool
+}
+
+func (e *markedError) Error() string { return e.err.Error() }
+func (e *markedError) Unwrap() error { return e.err }
+
+// Transient menandai err sebagai gangguan sementara yang layak dicoba ulang,
+// mis. respons model kosong tanpa alasan yang jelas.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &markedError{err: err, retryable: true}
+}
+
+// Permanent menandai err sebagai kegagalan yang tidak akan berubah jika
+// dicoba ulang, mis. konten diblokir filter keamanan.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &markedError{err: err, retryable: false}
+}
+
+// retryableGRPC adalah kode gRPC yang biasanya muncul saat provider sedang
+// kelebihan beban atau gangguan jaringan.
+var retryableGRPC = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.ResourceExhausted: true,
+	codes.DeadlineExceeded:  true,
+	codes.Aborted:           true,
+	codes.Internal:          true,
+}
+
+// retryableHTTP adalah status HTTP yang layak dicoba ulang.
+var retryableHTTP = map[int]bool{
+	http.StatusRequestTimeout:      true,
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+}
+
+// Retryable mengklasifikasikan err: true jika kegagalannya sementara
+// (503, 429, timeout, koneksi terputus) sehingga layak dicoba ulang.
+// Pembatalan oleh pemanggil (context.Canceled) tidak pernah dicoba ulang.
+func Retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var marked *markedError
+	if errors.As(err, &marked) {
+		return marked.retryable
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if st, ok := status.FromError(err); ok && st.Code() != codes.OK && st.Code() != codes.Unknown {
+		return retryableGRPC[st.Code()]
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return retryableHTTP[gerr.Code]
+	}
+	var httpErr interface{ HTTPCode() int }
+	if errors.As(err, &httpErr) && httpErr.HTTPCode() > 0 {
+		return retryableHTTP[httpErr.HTTPCode()]
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED)
+}
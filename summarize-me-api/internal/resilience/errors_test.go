@@ -0,0 +1,81 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall"
+	"testing"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// timeoutError meniru net.Error yang timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// httpCodeError meniru error yang membawa status HTTP, mis. dari genai.
+type httpCodeError int
+
+func (e httpCodeError) Error() string { return fmt.Sprintf("status %d", int(e)) }
+func (e httpCodeError) HTTPCode() int { return int(e) }
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"error biasa", errors.New("input tidak valid"), false},
+
+		{"grpc unavailable", status.Error(codes.Unavailable, "coba lagi"), true},
+		{"grpc resource exhausted", status.Error(codes.ResourceExhausted, "kuota"), true},
+		{"grpc deadline exceeded", status.Error(codes.DeadlineExceeded, "lambat"), true},
+		{"grpc aborted", status.Error(codes.Aborted, "konflik"), true},
+		{"grpc internal", status.Error(codes.Internal, "server"), true},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, "audio rusak"), false},
+		{"grpc permission denied", status.Error(codes.PermissionDenied, "akses"), false},
+		{"grpc not found", status.Error(codes.NotFound, "tidak ada"), false},
+		{"grpc dibungkus", fmt.Errorf("gagal memulai: %w", status.Error(codes.Unavailable, "")), true},
+
+		{"googleapi 503", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"googleapi 429", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"googleapi 500", &googleapi.Error{Code: http.StatusInternalServerError}, true},
+		{"googleapi 400", &googleapi.Error{Code: http.StatusBadRequest}, false},
+		{"googleapi 403", &googleapi.Error{Code: http.StatusForbidden}, false},
+		{"googleapi dibungkus", fmt.Errorf("gagal upload: %w", &googleapi.Error{Code: http.StatusBadGateway}), true},
+
+		{"HTTPCode 504", httpCodeError(http.StatusGatewayTimeout), true},
+		{"HTTPCode 404", httpCodeError(http.StatusNotFound), false},
+
+		{"transient", Transient(errors.New("respons kosong")), true},
+		{"permanent", Permanent(errors.New("diblokir")), false},
+		{"permanent menimpa grpc", Permanent(status.Error(codes.Unavailable, "")), false},
+		{"transient menimpa error biasa", fmt.Errorf("gagal: %w", Transient(io.EOF)), true},
+
+		{"ctx dibatalkan pemanggil", context.Canceled, false},
+		{"ctx dibatalkan dibungkus", fmt.Errorf("gagal: %w", context.Canceled), false},
+		{"ctx dibatalkan walau transient", Transient(context.Canceled), false},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+
+		{"net timeout", timeoutError{}, true},
+		{"unexpected EOF", io.ErrUnexpectedEOF, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Retryable(tt.err); got != tt.want {
+				t.Errorf("Retryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package resilience
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"log"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Policy mengatur retry dan timeout untuk satu tahap pemanggilan provider.
+// Urutan field sengaja sama dengan config.RetryPolicyConfig agar bisa
+// dikonversi langsung.
+type Policy struct {
+	// MaxAttempts adalah jumlah percobaan total, termasuk yang pertama.
+	MaxAttempts int
+	// InitialBackoff adalah jeda sebelum percobaan kedua; berlipat dua
+	// setiap percobaan berikutnya hingga MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout membatasi setiap percobaan. 0 berarti hanya mengikuti
+	// context pemanggil.
+	Timeout time.Duration
+}
+
+// Executor menjalankan pemanggilan ke satu provider dengan retry, backoff
+// dan circuit breaker. Executor nil aman dipakai: operasi dijalankan sekali
+// tanpa perlindungan apa pun.
+type Executor struct {
+	name    string
+	policy  Policy
+	breaker *breaker
+
+	calls     atomic.Int64
+	successes atomic.Int64
+	failures  atomic.Int64
+	retries   atomic.Int64
+	rejected  atomic.Int64
+
+	mu          sync.Mutex
+	lastError   string
+	lastFailure time.Time
+}
+
+// NewExecutor membuat executor tanpa mendaftarkannya ke Registry.
+func NewExecutor(name string, policy Policy, opts BreakerOptions) *Executor {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Executor{name: name, policy: policy, breaker: newBreaker(opts)}
+}
+
+// Name mengembalikan nama provider.
+func (e *Executor) Name() string {
+	if e == nil {
+		return ""
+	}
+	return e.name
+}
+
+// Do menjalankan op hingga berhasil, gagal permanen, percobaan habis, atau
+// ctx selesai. Setiap percobaan mendapat context dengan Policy.Timeout.
+// Error yang dikembalikan adalah error percobaan terakhir.
+func (e *Executor) Do(ctx context.Context, op func(ctx context.Context) error) error {
+	if e == nil {
+		return op(ctx)
+	}
+	e.calls.Add(1)
+	var err error
+	for attempt := 1; ; attempt++ {
+		if rejectErr := e.breaker.allow(); rejectErr != nil {
+			e.rejected.Add(1)
+			// Breaker bisa terbuka di tengah retry; sertakan penyebabnya.
+			err = errors.Join(err, rejectErr)
+			e.recordFailure(err)
+			return err
+		}
+		err = e.attempt(ctx, op)
+		retryable := Retryable(err)
+		if err != nil && ctx.Err() != nil {
+			// Pembatalan atau timeout ctx pemanggil bukan salah provider,
+			// jadi tidak dicatat sebagai berhasil maupun gagal.
+			retryable = false
+			e.breaker.release()
+		} else {
+			e.breaker.record(err != nil && retryable)
+		}
+		if err == nil {
+			e.successes.Add(1)
+			return nil
+		}
+		if !retryable || attempt >= e.policy.MaxAttempts {
+			e.recordFailure(err)
+			return err
+		}
+
+		wait := Backoff(e.policy.InitialBackoff, e.policy.MaxBackoff, attempt)
+		e.retries.Add(1)
+		log.Printf("WARN: Panggilan %s gagal (percobaan %d/%d), mencoba lagi dalam %v: %v", e.name, attempt, e.policy.MaxAttempts, wait.Round(time.Millisecond), err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			e.recordFailure(err)
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Timeout mengembalikan Policy.Timeout, untuk pemanggil yang perlu membatasi
+// total tahap di luar Do (mis. menunggu operasi yang berjalan lama).
+func (e *Executor) Timeout() time.Duration {
+	if e == nil {
+		return 0
+	}
+	return e.policy.Timeout
+}
+
+func (e *Executor) attempt(ctx context.Context, op func(ctx context.Context) error) error {
+	if e.policy.Timeout <= 0 {
+		return op(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, e.policy.Timeout)
+	defer cancel()
+	return op(attemptCtx)
+}
+
+func (e *Executor) recordFailure(err error) {
+	e.failures.Add(1)
+	e.mu.Lock()
+	e.lastError = err.Error()
+	e.lastFailure = time.Now()
+	e.mu.Unlock()
+}
+
+// Stats adalah statistik satu provider sejak server dijalankan.
+type Stats struct {
+	Name string `json:"name"`
+	// State adalah status circuit breaker: closed, open atau half-open.
+	State string `json:"state"`
+	// Calls adalah jumlah pemanggilan Do; Retries jumlah percobaan ulang.
+	Calls     int64 `json:"calls"`
+	Successes int64 `json:"successes"`
+	Failures  int64 `json:"failures"`
+	Retries   int64 `json:"retries"`
+	// Rejected adalah pemanggilan yang ditolak karena breaker terbuka.
+	Rejected      int64      `json:"rejected"`
+	BreakerOpens  int64      `json:"breakerOpens"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastFailureAt *time.Time `json:"lastFailureAt,omitempty"`
+}
+
+// Stats mengembalikan statistik executor saat ini.
+func (e *Executor) Stats() Stats {
+	state, opens := e.breaker.snapshot()
+	s := Stats{
+		Name:         e.name,
+		State:        state,
+		Calls:        e.calls.Load(),
+		Successes:    e.successes.Load(),
+		Failures:     e.failures.Load(),
+		Retries:      e.retries.Load(),
+		Rejected:     e.rejected.Load(),
+		BreakerOpens: opens,
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s.LastError = e.lastError
+	if !e.lastFailure.IsZero() {
+		t := e.lastFailure
+		s.LastFailureAt = &t
+	}
+	return s
+}
+
+// Backoff menghitung jeda sebelum percobaan ke attempt+1: exponential dengan
+// jitter di rentang [d/2, d). Dipakai juga untuk jadwal retry webhook.
+func Backoff(initial, max time.Duration, attempt int) time.Duration {
+	d := initial << (attempt - 1)
+	if d <= 0 || d > max {
+		d = max
+	}
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+	jitter, err := rand.Int(rand.Reader, big.NewInt(half))
+	if err != nil {
+		return d
+	}
+	return time.Duration(half + jitter.Int64())
+}
+
+// Registry menyimpan executor semua provider agar statistiknya bisa
+// ditampilkan di satu endpoint admin.
+type Registry struct {
+	breaker BreakerOptions
+
+	mu        sync.Mutex
+	executors []*Executor
+}
+
+// NewRegistry membuat registry; opts dipakai untuk breaker setiap executor.
+func NewRegistry(opts BreakerOptions) *Registry {
+	return &Registry{breaker: opts}
+}
+
+// Executor mengembalikan executor untuk provider name, dan membuatnya
+// dengan policy jika belum terdaftar.
+func (r *Registry) Executor(name string, policy Policy) *Executor {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.executors {
+		if e.name == name {
+			return e
+		}
+	}
+	e := NewExecutor(name, policy, r.breaker)
+	r.executors = append(r.executors, e)
+	return e
+}
+
+// Snapshot mengembalikan statistik semua executor sesuai urutan pendaftaran.
+func (r *Registry) Snapshot() []Stats {
+	if r == nil {
+		return []Stats{}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Stats, 0, len(r.executors))
+	for _, e := range r.executors {
+		out = append(out, e.Stats())
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		initial, max time.Duration
+		attempt      int
+		// Jeda selalu di rentang [want/2, want).
+		want time.Duration
+	}{
+		{time.Second, time.Minute, 1, time.Second},
+		{time.Second, time.Minute, 2, 2 * time.Second},
+		{time.Second, time.Minute, 4, 8 * time.Second},
+		{time.Second, 10 * time.Second, 5, 10 * time.Second},
+		// Geseran yang meluap tetap dibatasi max.
+		{time.Second, 10 * time.Second, 80, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 50; i++ {
+			got := Backoff(tt.initial, tt.max, tt.attempt)
+			if got < tt.want/2 || got >= tt.want {
+				t.Fatalf("Backoff(%v, %v, %d) = %v, want [%v, %v)", tt.initial, tt.max, tt.attempt, got, tt.want/2, tt.want)
+			}
+		}
+	}
+	if got := Backoff(time.Nanosecond, time.Nanosecond, 1); got != time.Nanosecond {
+		t.Errorf("Backoff tanpa ruang jitter = %v, want 1ns", got)
+	}
+}
+
+func testPolicy(attempts int) Policy {
+	return Policy{MaxAttempts: attempts, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+}
+
+func TestExecutorRetries(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "sibuk")
+	tests := []struct {
+		name      string
+		errs      []error // error per percobaan; habis berarti berhasil
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"langsung berhasil", nil, 3, 1, false},
+		{"berhasil setelah retry", []error{unavailable, unavailable}, 3, 3, false},
+		{"percobaan habis", []error{unavailable, unavailable, unavailable}, 3, 3, true},
+		{"error permanen tidak dicoba ulang", []error{status.Error(codes.InvalidArgument, "rusak")}, 3, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor("uji", testPolicy(tt.attempts), BreakerOptions{})
+			calls := 0
+			err := e.Do(context.Background(), func(context.Context) error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Do() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("op dipanggil %d kali, want %d", calls, tt.wantCalls)
+			}
+			stats := e.Stats()
+			if stats.Retries != int64(tt.wantCalls-1) {
+				t.Errorf("Retries = %d, want %d", stats.Retries, tt.wantCalls-1)
+			}
+		})
+	}
+}
+
+func TestExecutorBreakerOpens(t *testing.T) {
+	e := NewExecutor("uji", testPolicy(1), BreakerOptions{Threshold: 2, Cooldown: time.Hour})
+	fail := func(context.Context) error { return status.Error(codes.Unavailable, "sibuk") }
+	_ = e.Do(context.Background(), fail)
+	_ = e.Do(context.Background(), fail)
+
+	called := false
+	err := e.Do(context.Background(), func(context.Context) error { called = true; return nil })
+	if !errors.Is(err, ErrCircuitOpen) || called {
+		t.Fatalf("Do() saat breaker terbuka = %v (op dipanggil: %v), want ErrCircuitOpen", err, called)
+	}
+	if s := e.Stats(); s.State != StateOpen || s.Rejected != 1 || s.BreakerOpens != 1 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestExecutorCancelledTrialKeepsBreakerHalfOpen(t *testing.T) {
+	e := NewExecutor("uji", testPolicy(1), BreakerOptions{Threshold: 1, Cooldown: time.Minute})
+	clock := &fakeClock{t: time.Now()}
+	e.breaker.now = clock.now
+
+	_ = e.Do(context.Background(), func(context.Context) error { return status.Error(codes.Unavailable, "sibuk") })
+	clock.advance(time.Minute)
+
+	// Percobaan half-open dibatalkan pemanggil: breaker tidak boleh
+	// tertutup, dan percobaan berikutnya tetap diizinkan.
+	ctx, cancel := context.WithCancel(context.Background())
+	err := e.Do(ctx, func(context.Context) error {
+		cancel()
+		return status.Error(codes.Unavailable, "terputus")
+	})
+	if err == nil {
+		t.Fatal("Do() dengan ctx dibatalkan seharusnya error")
+	}
+	if state, _ := e.breaker.snapshot(); state != StateHalfOpen {
+		t.Fatalf("state setelah percobaan dibatalkan = %s, want %s", state, StateHalfOpen)
+	}
+
+	called := false
+	if err := e.Do(context.Background(), func(context.Context) error { called = true; return nil }); err != nil || !called {
+		t.Fatalf("percobaan half-open berikutnya = %v (op dipanggil: %v), want berhasil", err, called)
+	}
+	if state, _ := e.breaker.snapshot(); state != StateClosed {
+		t.Errorf("state = %s, want %s", state, StateClosed)
+	}
+}
+
+func TestExecutorNil(t *testing.T) {
+	var e *Executor
+	calls := 0
+	err := e.Do(context.Background(), func(context.Context) error { calls++; return status.Error(codes.Unavailable, "") })
+	if err == nil || calls != 1 {
+		t.Fatalf("Do() pada executor nil = %v setelah %d panggilan, want error setelah 1", err, calls)
+	}
+	if e.Timeout() != 0 {
+		t.Errorf("Timeout() = %v, want 0", e.Timeout())
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"summarize-me-api/internal/actions"
+	"summarize-me-api/internal/store"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownConnector dikembalikan jika nama connector tidak dikonfigurasi.
+	ErrUnknownConnector = errors.New("connector tidak dikenal")
+	// ErrInvalidActionExport dikembalikan jika parameter ekspor tidak valid.
+	ErrInvalidActionExport = errors.New("parameter ekspor action item tidak valid")
+	// ErrNoActionItems dikembalikan jika ringkasan tidak punya action item.
+	ErrNoActionItems = errors.New("ringkasan tidak memiliki action item")
+)
+
+// Status hasil push satu action item.
+const (
+	PushCreated = "created"
+	PushSkipped = "skipped"
+	PushFailed  = "failed"
+)
+
+// ActionExport mencatat action item yang sudah dikirim ke sebuah connector,
+// agar push berikutnya tidak membuat issue ganda.
+type ActionExport struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"userId"`
+	SummaryID  string    `json:"summaryId"`
+	Connector  string    `json:"connector"`
+	ItemIndex  int       `json:"itemIndex"`
+	Title      string    `json:"title"`
+	ExternalID string    `json:"externalId"`
+	URL        string    `json:"url,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ActionItem adalah action item beserta riwayat ekspornya.
+type ActionItem struct {
+	actions.Item
+	Exports []ActionExport `json:"exports,omitempty"`
+}
+
+// PushResult adalah hasil push satu action item.
+type PushResult struct {
+	Index      int    `json:"index"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	ExternalID string `json:"externalId,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ConnectorInfo adalah connector yang tersedia untuk user.
+type ConnectorInfo struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ActionService mengambil action item dari ringkasan lalu mengekspornya
+// ke file kalender atau issue tracker.
+type ActionService struct {
+	summaries  *SummaryService
+	exports    *store.Collection[ActionExport]
+	connectors []actions.Connector
+	linkURL    string
+
+	mu      sync.Mutex
+	pushing map[string]bool // ID ekspor yang sedang dikirim, agar push paralel tidak membuat issue ganda
+}
+
+// NewActionService membuat instance baru dari ActionService. linkURL boleh
+// kosong; "{id}" di dalamnya diganti ID ringkasan.
+func NewActionService(summaries *SummaryService, exports *store.Collection[ActionExport], connectors []actions.Connector, linkURL string) *ActionService {
+	return &ActionService{summaries: summaries, exports: exports, connectors: connectors, linkURL: linkURL, pushing: make(map[string]bool)}
+}
+
+// Connectors mengembalikan connector yang dikonfigurasi, tanpa kredensial.
+func (s *ActionService) Connectors() []ConnectorInfo {
+	out := make([]ConnectorInfo, 0, len(s.connectors))
+	for _, c := range s.connectors {
+		out = append(out, ConnectorInfo{Name: c.Name(), Type: c.Type()})
+	}
+	return out
+}
+
+// Items mengembalikan action item ringkasan beserta riwayat ekspornya.
+func (s *ActionService) Items(userID, summaryID string) ([]ActionItem, error) {
+	summary, err := s.summaries.GetSummary(userID, summaryID)
+	if err != nil {
+		return nil, err
+	}
+	exports := s.exports.List(func(e ActionExport) bool { return e.SummaryID == summaryID })
+	items := actions.Extract(summary.Text)
+	out := make([]ActionItem, 0, len(items))
+	for _, item := range items {
+		ai := ActionItem{Item: item}
+		for _, e := range exports {
+			if e.ItemIndex == item.Index && e.Title == item.Title {
+				ai.Exports = append(ai.Exports, e)
+			}
+		}
+		out = append(out, ai)
+	}
+	return out, nil
+}
+
+// ICS membuat file iCalendar dari action item ringkasan. kind adalah
+// actions.KindTodo (VTODO) atau actions.KindEvent (VEVENT pada tanggal tenggat).
+func (s *ActionService) ICS(userID, summaryID, kind string) ([]byte, Summary, error) {
+	if kind != actions.KindTodo && kind != actions.KindEvent {
+		return nil, Summary{}, fmt.Errorf("%w: kind harus '%s' atau '%s'", ErrInvalidActionExport, actions.KindTodo, actions.KindEvent)
+	}
+	summary, err := s.summaries.GetSummary(userID, summaryID)
+	if err != nil {
+		return nil, Summary{}, err
+	}
+	items := actions.Extract(summary.Text)
+	if len(items) == 0 {
+		return nil, summary, ErrNoActionItems
+	}
+	return actions.ICS(items, kind, s.meeting(summary), time.Now()), summary, nil
+}
+
+// Push mengirim action item ke connector. indexes kosong berarti semua item
+// yang belum dicentang selesai di ringkasan. Item yang sudah pernah dikirim
+// ke connector yang sama dilewati; satu item yang gagal tidak menghentikan
+// item lainnya.
+func (s *ActionService) Push(ctx context.Context, userID, summaryID, connectorName string, indexes []int) ([]PushResult, error) {
+	conn := s.connector(connectorName)
+	if conn == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownConnector, connectorName)
+	}
+	summary, err := s.summaries.GetSummary(userID, summaryID)
+	if err != nil {
+		return nil, err
+	}
+	items := actions.Extract(summary.Text)
+	if len(items) == 0 {
+		return nil, ErrNoActionItems
+	}
+	selected, err := selectItems(items, indexes)
+	if err != nil {
+		return nil, err
+	}
+
+	meeting := s.meeting(summary)
+	results := make([]PushResult, 0, len(selected))
+	for _, item := range selected {
+		res := PushResult{Index: item.Index, Title: item.Title}
+		id := exportID(summaryID, conn.Name(), item.Index)
+		prev, claimed := s.claim(id, item.Title)
+		if !claimed {
+			res.Status = PushSkipped
+			if prev != nil {
+				res.ExternalID, res.URL = prev.ExternalID, prev.URL
+			}
+			results = append(results, res)
+			continue
+		}
+
+		// Request ke connector dilakukan tanpa memegang s.mu, supaya push
+		// lain (termasuk ke ringkasan lain) tidak ikut menunggu.
+		ref, err := conn.Push(ctx, item, meeting)
+		if err != nil {
+			s.release(id)
+			log.Printf("WARN: Gagal push action item %d ringkasan %s ke %s: %v", item.Index, summaryID, conn.Name(), err)
+			res.Status, res.Error = PushFailed, err.Error()
+			results = append(results, res)
+			continue
+		}
+		res.Status, res.ExternalID, res.URL = PushCreated, ref.ExternalID, ref.URL
+		results = append(results, res)
+
+		export := ActionExport{
+			ID:         id,
+			UserID:     userID,
+			SummaryID:  summaryID,
+			Connector:  conn.Name(),
+			ItemIndex:  item.Index,
+			Title:      item.Title,
+			ExternalID: ref.ExternalID,
+			URL:        ref.URL,
+			CreatedAt:  time.Now(),
+		}
+		if err := s.exports.Put(id, export); err != nil {
+			log.Printf("ERROR: Gagal mencatat ekspor action item %s: %v", id, err)
+		}
+		s.release(id)
+	}
+	return results, nil
+}
+
+// claim menandai ekspor id sedang dikirim. Hasilnya false jika item yang
+// sama sudah pernah diekspor (prev berisi ekspor tersebut) atau sedang
+// dikirim oleh request lain.
+func (s *ActionService) claim(id, title string) (*ActionExport, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pushing[id] {
+		return nil, false
+	}
+	if prev, err := s.exports.Get(id); err == nil && prev.Title == title {
+		return &prev, false
+	}
+	s.pushing[id] = true
+	return nil, true
+}
+
+func (s *ActionService) release(id string) {
+	s.mu.Lock()
+	delete(s.pushing, id)
+	s.mu.Unlock()
+}
+
+func (s *ActionService) connector(name string) actions.Connector {
+	for _, c := range s.connectors {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *ActionService) meeting(summary Summary) actions.Meeting {
+	m := actions.Meeting{SummaryID: summary.ID, FileName: summary.FileName, Date: summary.CreatedAt}
+	if s.linkURL != "" {
+		m.URL = strings.ReplaceAll(s.linkURL, "{id}", summary.ID)
+	}
+	return m
+}
+
+// selectItems memilih item berdasarkan index (dimulai dari 1).
+func selectItems(items []actions.Item, indexes []int) ([]actions.Item, error) {
+	var out []actions.Item
+	if len(indexes) == 0 {
+		for _, item := range items {
+			if !item.Done {
+				out = append(out, item)
+			}
+		}
+		return out, nil
+	}
+	seen := make(map[int]bool)
+	for _, idx := range indexes {
+		if idx < 1 || idx > len(items) {
+			return nil, fmt.Errorf("%w: item %d tidak ada (1-%d)", ErrInvalidActionExport, idx, len(items))
+		}
+		if !seen[idx] {
+			seen[idx] = true
+			out = append(out, items[idx-1])
+		}
+	}
+	return out, nil
+}
+
+func exportID(summaryID, connector string, index int) string {
+	return fmt.Sprintf("%s:%s:%d", summaryID, connector, index)
+}
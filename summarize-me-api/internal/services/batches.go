@@ -0,0 +1,228 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"summarize-me-api/internal/media"
+	"summarize-me-api/internal/store"
+	"summarize-me-api/internal/transcript"
+	"time"
+)
+
+// ErrInvalidBatch dikembalikan jika isi batch tidak valid (kosong, terlalu
+// besar, archive rusak, dll).
+var ErrInvalidBatch = errors.New("batch tidak valid")
+
+// BatchStatus adalah status agregat semua job dalam batch.
+type BatchStatus string
+
+const (
+	BatchProcessing BatchStatus = "processing"
+	BatchCompleted  BatchStatus = "completed"
+	BatchFailed     BatchStatus = "failed"
+	// BatchPartial berarti semua job selesai tetapi sebagian gagal.
+	BatchPartial BatchStatus = "partial"
+)
+
+// Batch mengelompokkan beberapa job yang dikirim sekaligus.
+type Batch struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	Name      string    `json:"name,omitempty"`
+	JobIDs    []string  `json:"jobIds"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BatchLimits membatasi ukuran satu batch.
+type BatchLimits struct {
+	MaxFiles int
+	MaxBytes int64
+}
+
+// BatchReport adalah status batch beserta job-jobnya.
+type BatchReport struct {
+	Batch
+	Status BatchStatus       `json:"status"`
+	Counts map[JobStatus]int `json:"counts"`
+	Jobs   []Job             `json:"jobs"`
+}
+
+// BatchExportItem adalah hasil satu job dalam ekspor gabungan.
+type BatchExportItem struct {
+	JobID     string    `json:"jobId"`
+	FileName  string    `json:"fileName"`
+	Status    JobStatus `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	SummaryID string    `json:"summaryId,omitempty"`
+	Summary   string    `json:"summary,omitempty"`
+}
+
+// BatchService membuat batch dan menghitung status agregatnya.
+type BatchService struct {
+	batches   *store.Collection[Batch]
+	jobs      *JobService
+	summaries *SummaryService
+	runner    *JobRunner
+	limits    BatchLimits
+}
+
+// NewBatchService membuat instance baru dari BatchService.
+func NewBatchService(batches *store.Collection[Batch], jobs *JobService, summaries *SummaryService, runner *JobRunner, limits BatchLimits) *BatchService {
+	return &BatchService{batches: batches, jobs: jobs, summaries: summaries, runner: runner, limits: limits}
+}
+
+// Limits mengembalikan batas ukuran batch.
+func (s *BatchService) Limits() BatchLimits {
+	return s.limits
+}
+
+// CreateBatch membuat satu job antrean per file. Job yang gagal masuk antrean
+// tetap tercatat (berstatus failed) agar terlihat di laporan batch.
+// glossary (boleh nil) berlaku untuk semua job batch.
+func (s *BatchService) CreateBatch(userID, name string, files []InputFile, notifyEmails []string, force bool, glossary *GlossaryOverride) (BatchReport, error) {
+	if len(files) == 0 {
+		return BatchReport{}, fmt.Errorf("%w: tidak ada file audio", ErrInvalidBatch)
+	}
+	if len(files) > s.limits.MaxFiles {
+		return BatchReport{}, fmt.Errorf("%w: maksimal %d file per batch", ErrInvalidBatch, s.limits.MaxFiles)
+	}
+
+	if _, err := s.runner.ResolveGlossary(userID, glossary); err != nil {
+		return BatchReport{}, err
+	}
+
+	batch := Batch{ID: store.NewID(), UserID: userID, Name: name, CreatedAt: time.Now()}
+	for _, f := range files {
+		job, err := s.runner.Enqueue(Job{
+			UserID:       userID,
+			FileName:     f.Name,
+			FileSize:     len(f.Data),
+			BatchID:      batch.ID,
+			NotifyEmails: notifyEmails,
+			Force:        force,
+			Glossary:     glossary,
+		}, f.Data)
+		if job.ID == "" {
+			return BatchReport{}, err
+		}
+		batch.JobIDs = append(batch.JobIDs, job.ID)
+	}
+	if err := s.batches.Put(batch.ID, batch); err != nil {
+		return BatchReport{}, err
+	}
+	return s.report(batch), nil
+}
+
+// GetBatch mengambil laporan batch milik user. Batch milik user lain
+// diperlakukan sebagai tidak ditemukan.
+func (s *BatchService) GetBatch(userID, id string) (BatchReport, error) {
+	batch, err := s.batches.Get(id)
+	if err != nil {
+		return BatchReport{}, err
+	}
+	if batch.UserID != userID {
+		return BatchReport{}, store.ErrNotFound
+	}
+	return s.report(batch), nil
+}
+
+// ListBatches mengembalikan batch milik user, terbaru lebih dulu.
+func (s *BatchService) ListBatches(userID string) []Batch {
+	batches := s.batches.List(func(b Batch) bool { return b.UserID == userID })
+	sort.Slice(batches, func(i, j int) bool { return batches[i].CreatedAt.After(batches[j].CreatedAt) })
+	return batches
+}
+
+// Export mengumpulkan hasil semua job batch, diurutkan per nama file.
+func (s *BatchService) Export(userID, id string) (BatchReport, []BatchExportItem, error) {
+	report, err := s.GetBatch(userID, id)
+	if err != nil {
+		return BatchReport{}, nil, err
+	}
+	items := make([]BatchExportItem, 0, len(report.Jobs))
+	for _, j := range report.Jobs {
+		item := BatchExportItem{JobID: j.ID, FileName: j.FileName, Status: j.Status, Error: j.Error, SummaryID: j.SummaryID}
+		if j.SummaryID != "" {
+			if summary, err := s.summaries.GetSummary(userID, j.SummaryID); err == nil {
+				item.Summary = summary.Text
+			}
+		}
+		items = append(items, item)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].FileName < items[j].FileName })
+	return report, items, nil
+}
+
+func (s *BatchService) report(batch Batch) BatchReport {
+	report := BatchReport{Batch: batch, Counts: make(map[JobStatus]int)}
+	for _, id := range batch.JobIDs {
+		job, err := s.jobs.GetJob(id)
+		if err != nil {
+			continue
+		}
+		report.Jobs = append(report.Jobs, job)
+		report.Counts[job.Status]++
+	}
+
+	total := len(report.Jobs)
+	switch {
+	case report.Counts[JobQueued]+report.Counts[JobProcessing] > 0:
+		report.Status = BatchProcessing
+	case report.Counts[JobCompleted] == total:
+		report.Status = BatchCompleted
+	case report.Counts[JobFailed] == total:
+		report.Status = BatchFailed
+	default:
+		report.Status = BatchPartial
+	}
+	return report
+}
+
+// ExtractAudioArchive membaca file audio, video dan transkrip dari archive
+// ZIP. Direktori, file metadata macOS dan file lain dilewati. Total ukuran
+// hasil ekstraksi dibatasi maxBytes untuk mencegah zip bomb.
+func ExtractAudioArchive(data []byte, maxBytes int64) ([]InputFile, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: archive ZIP tidak bisa dibaca: %v", ErrInvalidBatch, err)
+	}
+
+	var (
+		files []InputFile
+		total int64
+	)
+	for _, f := range r.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(name, ".") || !IsSupportedFile(name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("%w: gagal membuka %s: %v", ErrInvalidBatch, f.Name, err)
+		}
+		content, err := io.ReadAll(io.LimitReader(rc, maxBytes-total+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: gagal membaca %s: %v", ErrInvalidBatch, f.Name, err)
+		}
+		total += int64(len(content))
+		if total > maxBytes {
+			return nil, fmt.Errorf("%w: isi archive melebihi %d MB", ErrInvalidBatch, maxBytes>>20)
+		}
+		files = append(files, InputFile{Name: name, Data: content})
+	}
+	return files, nil
+}
+
+// IsSupportedFile mengecek apakah file bisa diproses: audio
+// (AudioExtensions), video yang audionya bisa diekstrak, atau transkrip.
+func IsSupportedFile(name string) bool {
+	return contains(AudioExtensions, strings.ToLower(filepath.Ext(name))) || media.IsVideo(name) || transcript.IsTranscriptFile(name)
+}
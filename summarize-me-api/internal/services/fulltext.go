@@ -0,0 +1,225 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"summarize-me-api/internal/fulltext"
+	"sync"
+	"time"
+)
+
+const (
+	// maxKeywordOffset membatasi paging hasil pencarian kata kunci.
+	maxKeywordOffset = 1000
+	// snippetLength adalah panjang cuplikan jika tidak ada kata yang disorot.
+	snippetLength = 200
+)
+
+// KeywordQuery adalah parameter pencarian kata kunci. Text mendukung frasa
+// persis dalam tanda kutip ganda. Filter kosong diabaikan.
+type KeywordQuery struct {
+	Text    string
+	From    time.Time
+	To      time.Time
+	Tags    []string
+	Speaker string
+	Kind    string
+	Limit   int
+	Offset  int
+}
+
+// KeywordHit adalah satu hasil pencarian kata kunci. Snippets berisi
+// cuplikan dengan kata yang cocok dibungkus <mark> (sudah di-escape HTML).
+type KeywordHit struct {
+	SummaryID    string   `json:"summaryId"`
+	SummaryURL   string   `json:"summaryUrl"`
+	FileName     string   `json:"fileName"`
+	Kind         string   `json:"kind"`
+	Speaker      string   `json:"speaker,omitempty"`
+	Timestamp    string   `json:"timestamp,omitempty"`
+	SegmentStart int      `json:"segmentStart,omitempty"`
+	SegmentEnd   int      `json:"segmentEnd,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+	Score        float64  `json:"score"`
+	Snippets     []string `json:"snippets"`
+	// MeetingAt adalah waktu ringkasan rapat dibuat.
+	MeetingAt time.Time `json:"meetingAt"`
+}
+
+// KeywordResults adalah satu halaman hasil pencarian kata kunci.
+type KeywordResults struct {
+	Total  uint64       `json:"total"`
+	Offset int          `json:"offset"`
+	Hits   []KeywordHit `json:"hits"`
+}
+
+// FullTextService menjaga index full-text ringkasan dan transkrip tetap
+// sinkron dengan SummaryService, lalu melayani pencarian kata kunci.
+type FullTextService struct {
+	index     *fulltext.Index
+	summaries *SummaryService
+	mu        sync.Mutex // indexing ulang satu per satu agar urutan terjaga
+}
+
+// NewFullTextService membuat instance baru dari FullTextService.
+func NewFullTextService(index *fulltext.Index, summaries *SummaryService) *FullTextService {
+	return &FullTextService{index: index, summaries: summaries}
+}
+
+// HandleSummarySaved mengindeks ulang ringkasan yang dibuat atau diubah.
+// Ringkasan dibaca ulang dari store agar perubahan beruntun tidak tertukar.
+func (s *FullTextService) HandleSummarySaved(summary Summary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latest, err := s.summaries.GetSummary(summary.UserID, summary.ID)
+	if err != nil {
+		log.Printf("WARN: Ringkasan %s untuk index full-text tidak ditemukan: %v", summary.ID, err)
+		return
+	}
+	if err := s.index.Replace(latest.ID, fullTextDocuments(latest)); err != nil {
+		log.Printf("ERROR: Gagal mengindeks ringkasan %s ke full-text: %v", latest.ID, err)
+	}
+}
+
+// Backfill mengindeks ringkasan yang belum ada di index, mis. saat index
+// baru dibuat atau server memakai penyimpanan in-memory.
+func (s *FullTextService) Backfill() {
+	done := 0
+	for _, summary := range s.summaries.ListSummaries(nil) {
+		ok, err := s.index.Has(summary.ID)
+		if err != nil {
+			log.Printf("WARN: Gagal mengecek index full-text untuk %s: %v", summary.ID, err)
+			continue
+		}
+		if ok {
+			continue
+		}
+		s.mu.Lock()
+		err = s.index.Replace(summary.ID, fullTextDocuments(summary))
+		s.mu.Unlock()
+		if err != nil {
+			log.Printf("WARN: Gagal mengindeks ringkasan %s ke full-text: %v", summary.ID, err)
+			continue
+		}
+		done++
+	}
+	if done > 0 {
+		log.Printf("Backfill full-text selesai: %d ringkasan diindeks", done)
+	}
+}
+
+// Search mencari kata kunci di ringkasan dan transkrip milik user.
+func (s *FullTextService) Search(userID string, q KeywordQuery) (KeywordResults, error) {
+	q.Text = strings.TrimSpace(q.Text)
+	if len(q.Text) > maxQueryLength {
+		return KeywordResults{}, fmt.Errorf("%w: maksimal %d karakter", ErrInvalidQuery, maxQueryLength)
+	}
+	if q.Text == "" && len(q.Tags) == 0 && q.Speaker == "" && q.From.IsZero() && q.To.IsZero() {
+		return KeywordResults{}, fmt.Errorf("%w: isi q atau minimal satu filter", ErrInvalidQuery)
+	}
+	if q.Kind != "" && q.Kind != ChunkSummary && q.Kind != ChunkTranscript {
+		return KeywordResults{}, fmt.Errorf("%w: kind harus '%s' atau '%s'", ErrInvalidQuery, ChunkSummary, ChunkTranscript)
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return KeywordResults{}, fmt.Errorf("%w: tanggal akhir sebelum tanggal awal", ErrInvalidQuery)
+	}
+	if q.Offset < 0 || q.Offset > maxKeywordOffset {
+		return KeywordResults{}, fmt.Errorf("%w: offset harus 0 sampai %d", ErrInvalidQuery, maxKeywordOffset)
+	}
+
+	res, err := s.index.Search(fulltext.Query{
+		UserID:  userID,
+		Text:    q.Text,
+		From:    q.From,
+		To:      q.To,
+		Tags:    q.Tags,
+		Speaker: q.Speaker,
+		Kind:    q.Kind,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+	})
+	if err != nil {
+		return KeywordResults{}, err
+	}
+
+	out := KeywordResults{Total: res.Total, Offset: q.Offset, Hits: make([]KeywordHit, 0, len(res.Hits))}
+	for _, h := range res.Hits {
+		d := h.Document
+		snippets := h.Snippets
+		if len(snippets) == 0 {
+			snippets = []string{html.EscapeString(truncate(d.Text, snippetLength))}
+		}
+		out.Hits = append(out.Hits, KeywordHit{
+			SummaryID:    d.SummaryID,
+			SummaryURL:   "/api/summaries/" + d.SummaryID,
+			FileName:     d.FileName,
+			Kind:         d.Kind,
+			Speaker:      d.Speaker,
+			Timestamp:    d.Timestamp,
+			SegmentStart: d.SegmentStart,
+			SegmentEnd:   d.SegmentEnd,
+			Tags:         d.Tags,
+			Score:        h.Score,
+			Snippets:     snippets,
+			MeetingAt:    d.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// fullTextDocuments memecah ringkasan menjadi satu dokumen ringkasan dan
+// satu dokumen per segmen transkrip. Dokumen ringkasan membawa semua nama
+// pembicara agar filter speaker juga menemukan rapatnya.
+func fullTextDocuments(summary Summary) []fulltext.Document {
+	segments := SegmentTranscript(summary.Transcript)
+	var speakers []string
+	for _, seg := range segments {
+		if seg.Speaker != "" {
+			speakers = append(speakers, strings.ToLower(seg.Speaker))
+		}
+	}
+	speakers = dedupe(speakers)
+
+	base := fulltext.Document{
+		UserID:    summary.UserID,
+		FileName:  summary.FileName,
+		Tags:      summary.Tags,
+		CreatedAt: summary.CreatedAt,
+	}
+	var docs []fulltext.Document
+	if strings.TrimSpace(summary.Text) != "" {
+		d := base
+		d.Kind = ChunkSummary
+		d.Text = summary.Text
+		d.Speakers = speakers
+		docs = append(docs, d)
+	}
+	for _, seg := range segments {
+		d := base
+		d.Kind = ChunkTranscript
+		d.Text = seg.Text
+		d.Speaker = seg.Speaker
+		if seg.Speaker != "" {
+			d.Speakers = []string{strings.ToLower(seg.Speaker)}
+		}
+		d.Timestamp = seg.Timestamp
+		d.SegmentStart = seg.Index
+		d.SegmentEnd = seg.Index
+		docs = append(docs, d)
+	}
+	return docs
+}
+
+// truncate memotong teks pada batas kata terdekat sebelum max byte.
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	cut := strings.LastIndex(text[:max], " ")
+	if cut <= 0 {
+		cut = max
+	}
+	return text[:cut] + "…"
+}
@@ -0,0 +1,376 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"summarize-me-api/internal/store"
+	"time"
+
+	"cloud.google.com/go/speech/apiv1/speechpb"
+)
+
+// ErrInvalidGlossary dikembalikan jika isi glosarium atau override-nya tidak valid.
+var ErrInvalidGlossary = errors.New("glosarium tidak valid")
+
+// GlossaryNone di GlossaryOverride.IDs mematikan semua glosarium tersimpan.
+const GlossaryNone = "none"
+
+// Batas glosarium. Speech-to-Text menerima maksimal 5000 frasa per request
+// dan 100 karakter per frasa.
+const (
+	maxGlossariesPerUser  = 50
+	maxGlossaryTerms      = 500
+	maxPronunciations     = 5
+	maxPhraseLength       = 100
+	maxSpeechPhrases      = 5000
+	maxGlossaryBoost      = 20
+	defaultGlossaryBoost  = 10
+	maxPromptGlossaryTerm = 300
+)
+
+// GlossaryTerm adalah satu istilah dengan ejaan bakunya.
+type GlossaryTerm struct {
+	Term string `json:"term"`
+	// Boost 0 berarti mengikuti boost glosarium.
+	Boost float32 `json:"boost,omitempty"`
+	// Pronunciations adalah cara istilah biasa terdengar atau salah ditulis
+	// Speech, mis. "kiu ar" untuk "QRIS". Ikut dikirim sebagai frasa dan
+	// diganti ke Term di transkrip.
+	Pronunciations []string `json:"pronunciations,omitempty"`
+}
+
+// Glossary adalah kumpulan istilah milik user. Glosarium Shared dikelola
+// admin dan berlaku untuk semua user.
+type Glossary struct {
+	ID     string `json:"id"`
+	UserID string `json:"userId,omitempty"`
+	Name   string `json:"name"`
+	Shared bool   `json:"shared,omitempty"`
+	// Boost bawaan untuk istilah yang tidak mengisi boost sendiri (0-20).
+	Boost     float32        `json:"boost,omitempty"`
+	Terms     []GlossaryTerm `json:"terms"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt *time.Time     `json:"updatedAt,omitempty"`
+}
+
+// GlossaryInput adalah isi glosarium yang bisa diubah user.
+type GlossaryInput struct {
+	Name  string         `json:"name"`
+	Boost float32        `json:"boost"`
+	Terms []GlossaryTerm `json:"terms"`
+}
+
+// GlossaryOverride mengganti glosarium untuk satu permintaan. Tanpa
+// override, semua glosarium milik user dan glosarium bersama dipakai.
+type GlossaryOverride struct {
+	// IDs memilih glosarium tertentu sebagai pengganti glosarium bawaan;
+	// ["none"] berarti tanpa glosarium tersimpan.
+	IDs []string `json:"ids,omitempty"`
+	// Terms adalah istilah tambahan khusus permintaan ini dan menimpa
+	// istilah yang sama dari glosarium tersimpan.
+	Terms []GlossaryTerm `json:"terms,omitempty"`
+}
+
+// GlossaryService menyimpan glosarium dan menyusun kosakata untuk
+// Speech-to-Text dan prompt peringkasan.
+type GlossaryService struct {
+	glossaries *store.Collection[Glossary]
+}
+
+// NewGlossaryService membuat instance baru dari GlossaryService.
+func NewGlossaryService(glossaries *store.Collection[Glossary]) *GlossaryService {
+	return &GlossaryService{glossaries: glossaries}
+}
+
+// ListGlossaries mengembalikan glosarium milik user beserta glosarium
+// bersama, diurutkan menurut nama.
+func (s *GlossaryService) ListGlossaries(userID string) []Glossary {
+	list := s.glossaries.List(func(g Glossary) bool { return g.Shared || g.UserID == userID })
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Shared != list[j].Shared {
+			return !list[i].Shared
+		}
+		return strings.ToLower(list[i].Name) < strings.ToLower(list[j].Name)
+	})
+	return list
+}
+
+// GetGlossary mengambil glosarium milik user atau glosarium bersama.
+func (s *GlossaryService) GetGlossary(userID, id string) (Glossary, error) {
+	g, err := s.glossaries.Get(id)
+	if err != nil {
+		return Glossary{}, err
+	}
+	if !g.Shared && g.UserID != userID {
+		return Glossary{}, store.ErrNotFound
+	}
+	return g, nil
+}
+
+// CreateGlossary membuat glosarium baru. shared hanya boleh diisi admin.
+func (s *GlossaryService) CreateGlossary(userID string, input GlossaryInput, shared bool) (Glossary, error) {
+	input, err := normalizeGlossary(input)
+	if err != nil {
+		return Glossary{}, err
+	}
+	if !shared {
+		owned := s.glossaries.List(func(g Glossary) bool { return !g.Shared && g.UserID == userID })
+		if len(owned) >= maxGlossariesPerUser {
+			return Glossary{}, fmt.Errorf("%w: maksimal %d glosarium per user", ErrInvalidGlossary, maxGlossariesPerUser)
+		}
+	}
+	g := Glossary{
+		ID:        store.NewID(),
+		UserID:    userID,
+		Name:      input.Name,
+		Shared:    shared,
+		Boost:     input.Boost,
+		Terms:     input.Terms,
+		CreatedAt: time.Now(),
+	}
+	return g, s.glossaries.Put(g.ID, g)
+}
+
+// UpdateGlossary mengganti isi glosarium. Dengan shared, hanya glosarium
+// bersama yang bisa diubah; tanpa shared, hanya glosarium milik user.
+func (s *GlossaryService) UpdateGlossary(userID, id string, input GlossaryInput, shared bool) (Glossary, error) {
+	input, err := normalizeGlossary(input)
+	if err != nil {
+		return Glossary{}, err
+	}
+	if _, err := s.editable(userID, id, shared); err != nil {
+		return Glossary{}, err
+	}
+	return s.glossaries.Update(id, func(g *Glossary) error {
+		g.Name, g.Boost, g.Terms = input.Name, input.Boost, input.Terms
+		now := time.Now()
+		g.UpdatedAt = &now
+		return nil
+	})
+}
+
+// DeleteGlossary menghapus glosarium, dengan aturan akses seperti UpdateGlossary.
+func (s *GlossaryService) DeleteGlossary(userID, id string, shared bool) error {
+	if _, err := s.editable(userID, id, shared); err != nil {
+		return err
+	}
+	return s.glossaries.Delete(id)
+}
+
+func (s *GlossaryService) editable(userID, id string, shared bool) (Glossary, error) {
+	g, err := s.glossaries.Get(id)
+	if err != nil {
+		return Glossary{}, err
+	}
+	if g.Shared != shared || (!shared && g.UserID != userID) {
+		return Glossary{}, store.ErrNotFound
+	}
+	return g, nil
+}
+
+// Resolve menyusun kosakata untuk user dari glosarium tersimpan dan
+// override. Mengembalikan nil jika tidak ada istilah sama sekali.
+func (s *GlossaryService) Resolve(userID string, override *GlossaryOverride) (*Vocabulary, error) {
+	var selected []Glossary
+	switch {
+	case override != nil && len(override.IDs) == 1 && override.IDs[0] == GlossaryNone:
+	case override != nil && len(override.IDs) > 0:
+		for _, id := range override.IDs {
+			g, err := s.GetGlossary(userID, id)
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, fmt.Errorf("%w: glosarium %q tidak ditemukan", ErrInvalidGlossary, id)
+			}
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, g)
+		}
+	default:
+		selected = s.ListGlossaries(userID)
+	}
+
+	vocab := &Vocabulary{}
+	for _, g := range selected {
+		for _, t := range g.Terms {
+			vocab.add(t, g.Boost)
+		}
+	}
+	if override != nil && len(override.Terms) > 0 {
+		terms, err := normalizeTerms(override.Terms)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range terms {
+			vocab.add(t, 0)
+		}
+	}
+	if len(vocab.Terms) == 0 {
+		return nil, nil
+	}
+	return vocab, nil
+}
+
+// normalizeGlossary merapikan dan memvalidasi input glosarium.
+func normalizeGlossary(input GlossaryInput) (GlossaryInput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || len(input.Name) > maxPhraseLength {
+		return input, fmt.Errorf("%w: nama wajib diisi, maksimal %d karakter", ErrInvalidGlossary, maxPhraseLength)
+	}
+	if input.Boost < 0 || input.Boost > maxGlossaryBoost {
+		return input, fmt.Errorf("%w: boost harus 0 sampai %d", ErrInvalidGlossary, maxGlossaryBoost)
+	}
+	if len(input.Terms) == 0 {
+		return input, fmt.Errorf("%w: minimal satu istilah", ErrInvalidGlossary)
+	}
+	terms, err := normalizeTerms(input.Terms)
+	input.Terms = terms
+	return input, err
+}
+
+// normalizeTerms merapikan istilah dan membuang duplikat (tanpa membedakan
+// huruf besar/kecil); istilah yang muncul belakangan menang.
+func normalizeTerms(terms []GlossaryTerm) ([]GlossaryTerm, error) {
+	if len(terms) > maxGlossaryTerms {
+		return nil, fmt.Errorf("%w: maksimal %d istilah", ErrInvalidGlossary, maxGlossaryTerms)
+	}
+	out := make([]GlossaryTerm, 0, len(terms))
+	index := make(map[string]int)
+	for _, t := range terms {
+		t.Term = strings.TrimSpace(t.Term)
+		if t.Term == "" || len(t.Term) > maxPhraseLength {
+			return nil, fmt.Errorf("%w: istilah wajib diisi, maksimal %d karakter", ErrInvalidGlossary, maxPhraseLength)
+		}
+		if t.Boost < 0 || t.Boost > maxGlossaryBoost {
+			return nil, fmt.Errorf("%w: boost istilah %q harus 0 sampai %d", ErrInvalidGlossary, t.Term, maxGlossaryBoost)
+		}
+		if len(t.Pronunciations) > maxPronunciations {
+			return nil, fmt.Errorf("%w: maksimal %d pelafalan untuk istilah %q", ErrInvalidGlossary, maxPronunciations, t.Term)
+		}
+		var prons []string
+		for _, p := range t.Pronunciations {
+			p = strings.TrimSpace(p)
+			if len(p) > maxPhraseLength {
+				return nil, fmt.Errorf("%w: pelafalan %q terlalu panjang", ErrInvalidGlossary, p)
+			}
+			if p != "" && !strings.EqualFold(p, t.Term) {
+				prons = append(prons, p)
+			}
+		}
+		t.Pronunciations = prons
+
+		key := strings.ToLower(t.Term)
+		if i, ok := index[key]; ok {
+			out[i] = t
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// Vocabulary adalah gabungan istilah yang berlaku untuk satu permintaan,
+// dengan boost yang sudah ditentukan.
+type Vocabulary struct {
+	Terms []GlossaryTerm `json:"terms"`
+}
+
+// add menambahkan t; istilah yang sama (tanpa membedakan huruf besar/kecil)
+// ditimpa. defaultBoost dipakai jika t tidak punya boost sendiri.
+func (v *Vocabulary) add(t GlossaryTerm, defaultBoost float32) {
+	if t.Boost == 0 {
+		t.Boost = defaultBoost
+	}
+	if t.Boost == 0 {
+		t.Boost = defaultGlossaryBoost
+	}
+	for i, existing := range v.Terms {
+		if strings.EqualFold(existing.Term, t.Term) {
+			v.Terms[i] = t
+			return
+		}
+	}
+	v.Terms = append(v.Terms, t)
+}
+
+// hash adalah sidik jari pendek isi kosakata (istilah, boost dan
+// pelafalan, sesuai urutan), atau "" jika kosakata kosong.
+func (v *Vocabulary) hash() string {
+	if v == nil || len(v.Terms) == 0 {
+		return ""
+	}
+	h := sha256.New()
+	for _, t := range v.Terms {
+		fmt.Fprintf(h, "%q %g %q\n", t.Term, t.Boost, t.Pronunciations)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// speechContexts mengelompokkan istilah dan pelafalannya per boost untuk
+// RecognitionConfig.SpeechContexts.
+func (v *Vocabulary) speechContexts() []*speechpb.SpeechContext {
+	if v == nil {
+		return nil
+	}
+	byBoost := make(map[float32]*speechpb.SpeechContext)
+	var contexts []*speechpb.SpeechContext
+	phrases := 0
+	for _, t := range v.Terms {
+		ctx, ok := byBoost[t.Boost]
+		if !ok {
+			ctx = &speechpb.SpeechContext{Boost: t.Boost}
+			byBoost[t.Boost] = ctx
+			contexts = append(contexts, ctx)
+		}
+		for _, p := range append([]string{t.Term}, t.Pronunciations...) {
+			if phrases >= maxSpeechPhrases {
+				log.Printf("WARN: Glosarium melebihi %d frasa, sisanya tidak dikirim ke Speech-to-Text", maxSpeechPhrases)
+				return contexts
+			}
+			ctx.Phrases = append(ctx.Phrases, p)
+			phrases++
+		}
+	}
+	return contexts
+}
+
+// normalize menyeragamkan ejaan istilah di transkrip: pelafalan dan variasi
+// huruf besar/kecil diganti dengan ejaan baku.
+func (v *Vocabulary) normalize(text string) string {
+	if v == nil {
+		return text
+	}
+	for _, t := range v.Terms {
+		for _, variant := range append([]string{t.Term}, t.Pronunciations...) {
+			re, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(variant) + `\b`)
+			if err != nil {
+				continue
+			}
+			text = re.ReplaceAllLiteralString(text, t.Term)
+		}
+	}
+	return text
+}
+
+// promptInstruction adalah instruksi tambahan agar ringkasan memakai
+// ejaan istilah yang sama dengan glosarium. Kosong jika tanpa glosarium.
+func (v *Vocabulary) promptInstruction() string {
+	if v == nil || len(v.Terms) == 0 {
+		return ""
+	}
+	terms := v.Terms
+	if len(terms) > maxPromptGlossaryTerm {
+		terms = terms[:maxPromptGlossaryTerm]
+	}
+	quoted := make([]string, 0, len(terms))
+	for _, t := range terms {
+		quoted = append(quoted, fmt.Sprintf("%q", t.Term))
+	}
+	return " Gunakan ejaan istilah berikut persis seperti tertulis (glosarium): " + strings.Join(quoted, ", ") + "."
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"summarize-me-api/internal/store"
+	"sync"
+	"time"
+)
+
+// JobStatus adalah tahap sebuah job peringkasan.
+type JobStatus string
+
+const (
+	JobQueued     JobStatus = "queued"
+	JobProcessing JobStatus = "processing"
+	JobCompleted  JobStatus = "completed"
+	JobFailed     JobStatus = "failed"
+)
+
+// Job mencatat satu permintaan transkripsi + peringkasan.
+type Job struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"userId"`
+	FileName     string     `json:"fileName"`
+	FileSize     int        `json:"fileSize"`
+	BatchID      string     `json:"batchId,omitempty"`
+	SourceURL    string     `json:"sourceUrl,omitempty"`
+	Status       JobStatus  `json:"status"`
+	Error        string     `json:"error,omitempty"`
+	SummaryID    string     `json:"summaryId,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	DurationMs   int64      `json:"durationMs,omitempty"`
+	NotifyEmails []string   `json:"notifyEmails,omitempty"`
+	// Force melewati deduplikasi: audio selalu ditranskrip ulang.
+	Force bool `json:"force,omitempty"`
+	// Glossary mengganti glosarium bawaan user untuk job ini.
+	Glossary *GlossaryOverride `json:"glossary,omitempty"`
+	// ContentHash adalah SHA-256 (hex) input job.
+	ContentHash string `json:"contentHash,omitempty"`
+	// TranscriptFrom adalah ID ringkasan yang transkripnya dipakai ulang
+	// karena audio yang sama pernah diunggah.
+	TranscriptFrom string `json:"transcriptFrom,omitempty"`
+}
+
+// JobFilter membatasi hasil ListJobs. Field kosong berarti tidak difilter.
+type JobFilter struct {
+	UserID string
+	Status JobStatus
+	Since  time.Time
+	Limit  int
+}
+
+// JobOutcome adalah hasil akhir sebuah job. Err nil berarti berhasil.
+type JobOutcome struct {
+	Result    *SummarizeResult
+	SummaryID string
+	Err       error
+}
+
+// JobListener dipanggil setiap kali job selesai, di goroutine tersendiri.
+type JobListener func(job Job, outcome JobOutcome)
+
+// JobService mencatat siklus hidup job.
+type JobService struct {
+	jobs *store.Collection[Job]
+
+	mu        sync.RWMutex
+	listeners []JobListener
+}
+
+// NewJobService membuat instance baru dari JobService.
+func NewJobService(jobs *store.Collection[Job]) *JobService {
+	return &JobService{jobs: jobs}
+}
+
+// StartJob mencatat job baru. Pemanggil mengisi data permintaan (UserID,
+// FileName, dll); ID dan waktu diisi di sini. Status default processing,
+// kecuali pemanggil sudah mengisinya (mis. queued untuk antrean).
+func (s *JobService) StartJob(job Job) (Job, error) {
+	job.ID = store.NewID()
+	if job.Status == "" {
+		job.Status = JobProcessing
+	}
+	job.CreatedAt = time.Now()
+	return job, s.jobs.Put(job.ID, job)
+}
+
+// MarkProcessing menandai job antrean mulai dikerjakan worker.
+func (s *JobService) MarkProcessing(id string) (Job, error) {
+	return s.jobs.Update(id, func(j *Job) error {
+		j.Status = JobProcessing
+		return nil
+	})
+}
+
+// SetInput mencatat nama dan ukuran file yang baru diketahui setelah input
+// job diambil (mis. diunduh dari URL).
+func (s *JobService) SetInput(id, fileName string, size int) (Job, error) {
+	return s.jobs.Update(id, func(j *Job) error {
+		j.FileName = fileName
+		j.FileSize = size
+		return nil
+	})
+}
+
+// FailStale menandai job yang masih queued/processing sebagai gagal. Dipanggil
+// saat server start karena antrean hanya hidup di memori proses sebelumnya.
+func (s *JobService) FailStale() int {
+	stale := s.jobs.List(func(j Job) bool { return j.Status == JobQueued || j.Status == JobProcessing })
+	for _, j := range stale {
+		if _, err := s.FinishJob(j.ID, JobOutcome{Err: errServerRestarted}); err != nil {
+			log.Printf("WARN: Gagal menandai job %s sebagai gagal: %v", j.ID, err)
+		}
+	}
+	return len(stale)
+}
+
+var errServerRestarted = errors.New("server dimulai ulang sebelum job selesai")
+
+// Subscribe mendaftarkan listener untuk event job selesai.
+func (s *JobService) Subscribe(listener JobListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// FinishJob menandai job selesai lalu memberi tahu semua listener.
+func (s *JobService) FinishJob(id string, outcome JobOutcome) (Job, error) {
+	job, err := s.jobs.Update(id, func(j *Job) error {
+		now := time.Now()
+		j.CompletedAt = &now
+		j.DurationMs = now.Sub(j.CreatedAt).Milliseconds()
+		j.Status = JobCompleted
+		j.SummaryID = outcome.SummaryID
+		if outcome.Result != nil {
+			j.ContentHash = outcome.Result.ContentHash
+			j.TranscriptFrom = outcome.Result.TranscriptFrom
+		}
+		if outcome.Err != nil {
+			j.Status = JobFailed
+			j.Error = outcome.Err.Error()
+		}
+		return nil
+	})
+	if err != nil {
+		return job, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, listener := range s.listeners {
+		go listener(job, outcome)
+	}
+	return job, nil
+}
+
+// GetJob mengambil job berdasarkan ID.
+func (s *JobService) GetJob(id string) (Job, error) {
+	return s.jobs.Get(id)
+}
+
+// ListJobs mengembalikan job terbaru lebih dulu.
+func (s *JobService) ListJobs(filter JobFilter) []Job {
+	jobs := s.jobs.List(func(j Job) bool {
+		return (filter.UserID == "" || j.UserID == filter.UserID) &&
+			(filter.Status == "" || j.Status == filter.Status) &&
+			(filter.Since.IsZero() || !j.CreatedAt.Before(filter.Since))
+	})
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if filter.Limit > 0 && len(jobs) > filter.Limit {
+		jobs = jobs[:filter.Limit]
+	}
+	return jobs
+}
+
+// UsageStats adalah ringkasan pemakaian untuk admin.
+type UsageStats struct {
+	Since       time.Time           `json:"since"`
+	TotalJobs   int                 `json:"totalJobs"`
+	ByStatus    map[JobStatus]int   `json:"byStatus"`
+	ActiveUsers int                 `json:"activeUsers"`
+	TotalBytes  int64               `json:"totalBytes"`
+	PerUser     map[string]UserStat `json:"perUser"`
+}
+
+// UserStat adalah pemakaian satu user.
+type UserStat struct {
+	Jobs   int   `json:"jobs"`
+	Failed int   `json:"failed"`
+	Bytes  int64 `json:"bytes"`
+}
+
+// Stats menghitung pemakaian sejak waktu tertentu.
+func (s *JobService) Stats(since time.Time) UsageStats {
+	stats := UsageStats{
+		Since:    since,
+		ByStatus: make(map[JobStatus]int),
+		PerUser:  make(map[string]UserStat),
+	}
+	for _, j := range s.ListJobs(JobFilter{Since: since}) {
+		stats.TotalJobs++
+		stats.ByStatus[j.Status]++
+		stats.TotalBytes += int64(j.FileSize)
+
+		u := stats.PerUser[j.UserID]
+		u.Jobs++
+		u.Bytes += int64(j.FileSize)
+		if j.Status == JobFailed {
+			u.Failed++
+		}
+		stats.PerUser[j.UserID] = u
+	}
+	stats.ActiveUsers = len(stats.PerUser)
+	return stats
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Jenis LiveEvent yang dikirim ke klien.
+const (
+	LiveEventTranscript = "transcript"
+	LiveEventSummary    = "summary"
+	LiveEventFinal      = "final"
+	LiveEventError      = "error"
+)
+
+// LiveEvent adalah pesan server ke klien selama sesi live.
+type LiveEvent struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	Final     bool   `json:"final,omitempty"`
+	OffsetMs  int64  `json:"offsetMs,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+	JobID     string `json:"jobId,omitempty"`
+	SummaryID string `json:"summaryId,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// LiveOptions mengatur sesi live.
+type LiveOptions struct {
+	// SummaryInterval adalah jarak antar ringkasan berjalan.
+	SummaryInterval time.Duration
+	MaxDuration     time.Duration
+	LanguageCode    string
+}
+
+// LiveService membuat sesi transkripsi live dengan ringkasan berjalan.
+type LiveService struct {
+	transcriber StreamTranscriber
+	summarizer  *SummarizeService
+	jobs        *JobService
+	runner      *JobRunner
+	usage       *UsageService
+	opts        LiveOptions
+}
+
+// NewLiveService membuat instance baru dari LiveService.
+func NewLiveService(transcriber StreamTranscriber, summarizer *SummarizeService, jobs *JobService, runner *JobRunner, opts LiveOptions) *LiveService {
+	return &LiveService{transcriber: transcriber, summarizer: summarizer, jobs: jobs, runner: runner, opts: opts}
+}
+
+// TrackUsage mencatat menit audio sesi live dan ringkasan berjalannya ke
+// ledger usage. Ringkasan final sudah tercatat lewat job.
+func (s *LiveService) TrackUsage(usage *UsageService) {
+	s.usage = usage
+}
+
+// MaxDuration adalah batas lama satu sesi.
+func (s *LiveService) MaxDuration() time.Duration {
+	return s.opts.MaxDuration
+}
+
+// Start membuka sesi live baru untuk user. Glosarium bawaan user dipakai
+// sebagai frasa Speech-to-Text dan acuan ejaan sepanjang sesi.
+func (s *LiveService) Start(userID string, cfg StreamConfig) (*LiveSession, error) {
+	if cfg.LanguageCode == "" {
+		cfg.LanguageCode = s.opts.LanguageCode
+	}
+	vocab, err := s.runner.ResolveGlossary(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Vocabulary = vocab
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := s.transcriber.Start(ctx, cfg)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	l := &LiveSession{
+		svc:      s,
+		userID:   userID,
+		vocab:    vocab,
+		ctx:      ctx,
+		cancel:   cancel,
+		stream:   stream,
+		started:  time.Now(),
+		events:   make(chan LiveEvent, 64),
+		received: make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+	go l.receive()
+	l.workers.Add(1)
+	go l.summarizePeriodically()
+	return l, nil
+}
+
+// LiveSession adalah satu sesi live. Audio ditulis lewat Write, hasil
+// dibaca dari Events sampai channel ditutup setelah Finish.
+type LiveSession struct {
+	svc     *LiveService
+	userID  string
+	vocab   *Vocabulary
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stream  TranscriptStream
+	started time.Time
+
+	mu         sync.Mutex
+	paragraphs []string
+	bytes      int
+	summarized int
+
+	events   chan LiveEvent
+	received chan struct{}
+	stop     chan struct{}
+	workers  sync.WaitGroup
+	finish   sync.Once
+}
+
+// Events mengembalikan channel event untuk dikirim ke klien.
+func (l *LiveSession) Events() <-chan LiveEvent {
+	return l.events
+}
+
+// Write meneruskan potongan audio ke stream transkripsi.
+func (l *LiveSession) Write(audio []byte) error {
+	l.mu.Lock()
+	l.bytes += len(audio)
+	l.mu.Unlock()
+	return l.stream.Send(audio)
+}
+
+func (l *LiveSession) receive() {
+	defer close(l.received)
+	for {
+		seg, err := l.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			log.Printf("ERROR: Sesi live user %s: %v", l.userID, err)
+			l.emit(LiveEvent{Type: LiveEventError, Error: "Transkripsi live terhenti"})
+			return
+		}
+		if seg.Text == "" {
+			continue
+		}
+		seg.Text = l.vocab.normalize(seg.Text)
+		if seg.Final {
+			l.mu.Lock()
+			l.paragraphs = append(l.paragraphs, fmt.Sprintf("[%s] %s", clock(seg.Offset), seg.Text))
+			l.mu.Unlock()
+		}
+		l.emit(LiveEvent{Type: LiveEventTranscript, Text: seg.Text, Final: seg.Final, OffsetMs: seg.Offset.Milliseconds()})
+	}
+}
+
+func (l *LiveSession) summarizePeriodically() {
+	defer l.workers.Done()
+	ticker := time.NewTicker(l.svc.opts.SummaryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.rollingSummary()
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// rollingSummary meringkas transkrip sejauh ini jika ada teks final baru.
+func (l *LiveSession) rollingSummary() {
+	text, n := l.transcript()
+	l.mu.Lock()
+	unchanged := n == l.summarized
+	l.summarized = n
+	l.mu.Unlock()
+	if text == "" || unchanged {
+		return
+	}
+
+	result, err := l.svc.summarizer.SummarizeTranscriptWith(l.ctx, text, l.vocab)
+	if l.svc.usage != nil {
+		l.svc.usage.RecordOutcome(UsageRecord{UserID: l.userID, Kind: UsageKindLiveSummary}, result, err)
+	}
+	if err != nil {
+		log.Printf("WARN: Gagal membuat ringkasan berjalan untuk user %s: %v", l.userID, err)
+		return
+	}
+	l.emit(LiveEvent{Type: LiveEventSummary, Summary: result.Summary})
+}
+
+func (l *LiveSession) transcript() (string, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.paragraphs, "\n\n"), len(l.paragraphs)
+}
+
+// Finish menutup audio, menunggu hasil terakhir, membuat ringkasan final
+// yang dicatat sebagai job biasa, lalu menutup channel Events. Aman
+// dipanggil lebih dari sekali.
+func (l *LiveSession) Finish() {
+	l.finish.Do(func() {
+		defer l.cancel()
+		defer close(l.events)
+
+		if err := l.stream.CloseSend(); err != nil {
+			log.Printf("WARN: Gagal menutup stream live user %s: %v", l.userID, err)
+		}
+		select {
+		case <-l.received:
+		case <-time.After(30 * time.Second):
+			log.Printf("WARN: Hasil akhir stream live user %s tidak kunjung datang", l.userID)
+		}
+		close(l.stop)
+		l.workers.Wait()
+		l.recordStreamUsage()
+
+		text, _ := l.transcript()
+		if text == "" {
+			l.emit(LiveEvent{Type: LiveEventError, Error: "Tidak ada ucapan yang terdeteksi"})
+			return
+		}
+
+		l.mu.Lock()
+		size := l.bytes
+		l.mu.Unlock()
+		job, err := l.svc.jobs.StartJob(Job{
+			UserID:   l.userID,
+			FileName: "live-" + l.started.Format("2006-01-02-1504") + ".txt",
+			FileSize: size,
+		})
+		if err != nil {
+			log.Printf("WARN: Gagal mencatat job live untuk user %s: %v", l.userID, err)
+		}
+		result, summary, err := l.svc.runner.RunTranscript(context.Background(), job, text)
+		if err != nil {
+			log.Printf("ERROR: Gagal membuat ringkasan final live untuk user %s: %v", l.userID, err)
+			l.emit(LiveEvent{Type: LiveEventError, Error: "Gagal membuat ringkasan final", JobID: job.ID})
+			return
+		}
+		l.emit(LiveEvent{Type: LiveEventFinal, Text: text, Summary: result.Summary, JobID: job.ID, SummaryID: summary.ID})
+	})
+}
+
+// recordStreamUsage mencatat durasi sesi sebagai menit audio Speech
+// streaming. Durasi diukur dari waktu sesi karena audio terkompresi (WebM,
+// Ogg) tidak bisa dihitung durasinya dari jumlah byte.
+func (l *LiveSession) recordStreamUsage() {
+	if l.svc.usage == nil {
+		return
+	}
+	l.mu.Lock()
+	size := l.bytes
+	l.mu.Unlock()
+	if size == 0 {
+		return
+	}
+	_, err := l.svc.usage.Record(UsageRecord{
+		UserID: l.userID,
+		Kind:   UsageKindLive,
+		Usage:  Usage{AudioSeconds: time.Since(l.started).Seconds()},
+	})
+	if err != nil {
+		log.Printf("ERROR: Gagal mencatat pemakaian sesi live user %s: %v", l.userID, err)
+	}
+}
+
+// emit mengirim event tanpa memblokir selamanya jika klien lambat; event
+// transkrip interim boleh hilang, event lain ditunggu.
+func (l *LiveSession) emit(ev LiveEvent) {
+	if ev.Type == LiveEventTranscript && !ev.Final {
+		select {
+		case l.events <- ev:
+		default:
+		}
+		return
+	}
+	l.events <- ev
+}
+
+func clock(d time.Duration) string {
+	s := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	netmail "net/mail"
+	"path/filepath"
+	"strings"
+	"summarize-me-api/internal/mail"
+	"summarize-me-api/internal/store"
+)
+
+// MaxNotifyEmails membatasi jumlah alamat peserta per permintaan.
+const MaxNotifyEmails = 20
+
+// ErrInvalidEmail dikembalikan jika daftar alamat email tidak valid.
+var ErrInvalidEmail = errors.New("alamat email tidak valid")
+
+// ParseNotifyEmails memecah daftar alamat yang dipisah koma atau baris baru
+// dan memvalidasi masing-masing.
+func ParseNotifyEmails(raw string) ([]string, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ';' || r == '\n' })
+	var out []string
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		addr, err := netmail.ParseAddress(field)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidEmail, field)
+		}
+		out = append(out, strings.ToLower(addr.Address))
+	}
+	out = dedupe(out)
+	if len(out) > MaxNotifyEmails {
+		return nil, fmt.Errorf("%w: maksimal %d alamat", ErrInvalidEmail, MaxNotifyEmails)
+	}
+	return out, nil
+}
+
+// Sender adalah pengirim email; diimplementasikan oleh mail.Mailer.
+type Sender interface {
+	Send(msg mail.Message) error
+}
+
+// EmailNotifier mengirim ringkasan job yang selesai lewat email ke pemilik
+// (jika preferensinya aktif) dan ke alamat peserta yang disertakan pada job.
+type EmailNotifier struct {
+	sender Sender
+	users  *store.Collection[User]
+}
+
+// NewEmailNotifier membuat instance baru dari EmailNotifier.
+func NewEmailNotifier(sender Sender, users *store.Collection[User]) *EmailNotifier {
+	return &EmailNotifier{sender: sender, users: users}
+}
+
+// HandleJobFinished adalah JobListener yang mengirim email untuk job berhasil.
+func (n *EmailNotifier) HandleJobFinished(job Job, outcome JobOutcome) {
+	if outcome.Err != nil || outcome.Result == nil {
+		return
+	}
+	recipients := n.recipients(job)
+	if len(recipients) == 0 {
+		return
+	}
+
+	msg, err := buildSummaryEmail(job, outcome.Result, recipients)
+	if err != nil {
+		log.Printf("ERROR: Gagal menyusun email untuk job %s: %v", job.ID, err)
+		return
+	}
+	if err := n.sender.Send(msg); err != nil {
+		log.Printf("ERROR: Gagal mengirim email untuk job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("Email ringkasan job %s terkirim ke %d penerima", job.ID, len(recipients))
+}
+
+func (n *EmailNotifier) recipients(job Job) []string {
+	var out []string
+	user, err := n.users.Get(job.UserID)
+	if err == nil && user.Preferences.EmailOnComplete && user.Email != "" {
+		out = append(out, strings.ToLower(user.Email))
+	}
+	return dedupe(append(out, job.NotifyEmails...))
+}
+
+var summaryEmailTemplate = template.Must(template.New("summary").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; line-height: 1.5; max-width: 720px;">
+<p>Ringkasan untuk <strong>{{.FileName}}</strong> sudah selesai.</p>
+{{.Summary}}
+<p style="color: #888; font-size: 12px;">Transkrip lengkap terlampir. Model: {{.Model}}, prompt {{.PromptVersion}}.</p>
+</body>
+</html>
+`))
+
+func buildSummaryEmail(job Job, result *SummarizeResult, to []string) (mail.Message, error) {
+	var html bytes.Buffer
+	err := summaryEmailTemplate.Execute(&html, map[string]any{
+		"FileName":      job.FileName,
+		"Summary":       template.HTML(mail.MarkdownToHTML(result.Summary)),
+		"Model":         result.Model,
+		"PromptVersion": result.PromptVersion,
+	})
+	if err != nil {
+		return mail.Message{}, err
+	}
+
+	return mail.Message{
+		To:       to,
+		Subject:  "Ringkasan siap: " + job.FileName,
+		TextBody: result.Summary,
+		HTMLBody: html.String(),
+		Attachments: []mail.Attachment{{
+			FileName:    strings.TrimSuffix(job.FileName, filepath.Ext(job.FileName)) + "-transkrip.txt",
+			ContentType: "text/plain; charset=utf-8",
+			Data:        []byte(result.Transcript),
+		}},
+	}, nil
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"summarize-me-api/internal/resilience"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// UseResilience membungkus pemanggilan Gemini, Speech-to-Text dan GCS
+// dengan retry, backoff dan circuit breaker. gemini mengembalikan executor
+// per model agar breaker satu model tidak menghalangi fallback ke model
+// lain. Executor nil berarti provider tersebut dipanggil sekali tanpa retry.
+func (s *SummarizeService) UseResilience(gemini func(model string) *resilience.Executor, speech, storage *resilience.Executor) {
+	s.gemini = gemini
+	s.speech = speech
+	s.storage = storage
+}
+
+// summaryText mengambil teks ringkasan dari respons Gemini dan
+// mengklasifikasikan kegagalannya: konten yang diblokir bersifat permanen,
+// sedangkan respons kosong tanpa alasan jelas layak dicoba ulang. Respons
+// yang terpotong karena batas token tetap dipakai.
+func summaryText(resp *genai.GenerateContentResponse) (string, error) {
+	if len(resp.Candidates) == 0 {
+		return "", resilience.Transient(errors.New("gagal mendapatkan respons dari AI (kandidat kosong)"))
+	}
+	candidate := resp.Candidates[0]
+	var text strings.Builder
+	if candidate.Content != nil {
+		for _, part := range candidate.Content.Parts {
+			if txt, ok := part.(genai.Text); ok {
+				text.WriteString(string(txt))
+			}
+		}
+	}
+	summary := strings.TrimSpace(text.String())
+
+	switch candidate.FinishReason {
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation:
+		return "", resilience.Permanent(fmt.Errorf("respons AI diblokir: %s", candidate.FinishReason))
+	case genai.FinishReasonMaxTokens:
+		if summary == "" {
+			return "", resilience.Permanent(errors.New("respons AI kosong karena melebihi batas token"))
+		}
+		log.Printf("WARN: Ringkasan terpotong karena batas token output (%d karakter)", len(summary))
+	case genai.FinishReasonOther:
+		if summary == "" {
+			return "", resilience.Transient(fmt.Errorf("gagal mendapatkan respons AI: %s", candidate.FinishReason))
+		}
+		log.Printf("WARN: Gemini berhenti dengan alasan %s, ringkasan tetap dipakai", candidate.FinishReason)
+	}
+	if summary == "" {
+		return "", resilience.Transient(errors.New("gagal mendapatkan respons dari AI (parts kosong)"))
+	}
+	return summary, nil
+}
+
+// geminiError mengklasifikasikan error dari GenerateContent. BlockedError
+// tidak akan berubah jika dicoba ulang.
+func geminiError(err error) error {
+	var blocked *genai.BlockedError
+	if errors.As(err, &blocked) {
+		return resilience.Permanent(fmt.Errorf("respons AI diblokir: %w", err))
+	}
+	return fmt.Errorf("gagal GenerateContent Gemini: %w", err)
+}
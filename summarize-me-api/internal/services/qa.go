@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"summarize-me-api/internal/store"
+	"sync"
+	"time"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ErrNoTranscript dikembalikan jika ringkasan tidak menyimpan transkrip,
+// mis. ringkasan lama yang dibuat sebelum transkrip ikut disimpan.
+var ErrNoTranscript = errors.New("transkrip untuk ringkasan ini tidak tersedia")
+
+// ErrInvalidQuestion dikembalikan jika pertanyaan kosong atau terlalu panjang.
+var ErrInvalidQuestion = errors.New("pertanyaan tidak valid")
+
+const (
+	// maxQuestionLength membatasi panjang satu pertanyaan (karakter).
+	maxQuestionLength = 2000
+	// maxChatHistory adalah jumlah pesan terakhir yang dikirim ulang ke
+	// Gemini sebagai konteks percakapan.
+	maxChatHistory = 20
+	// maxStoredMessages adalah jumlah pesan yang disimpan per percakapan.
+	maxStoredMessages = 200
+	// maxSegmentLength adalah panjang maksimum satu segmen transkrip;
+	// paragraf yang lebih panjang dipecah per kalimat.
+	maxSegmentLength = 600
+)
+
+// Peran pesan dalam percakapan.
+const (
+	ChatRoleUser  = "user"
+	ChatRoleModel = "model"
+)
+
+// TranscriptSegment adalah potongan transkrip bernomor yang bisa dikutip
+// dalam jawaban.
+type TranscriptSegment struct {
+	Index int `json:"index"`
+	// Timestamp adalah posisi "hh:mm:ss" dalam rekaman jika transkrip
+	// menyimpannya (subtitle dan sesi live).
+	Timestamp string `json:"timestamp,omitempty"`
+	Speaker   string `json:"speaker,omitempty"`
+	Text      string `json:"text"`
+}
+
+// ChatMessage adalah satu pesan dalam percakapan tanya-jawab.
+type ChatMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+	// Citations berisi segmen transkrip yang dikutip jawaban.
+	Citations []TranscriptSegment `json:"citations,omitempty"`
+	CreatedAt time.Time           `json:"createdAt"`
+}
+
+// Conversation adalah riwayat tanya-jawab seorang user atas satu ringkasan.
+type Conversation struct {
+	ID        string        `json:"id"`
+	SummaryID string        `json:"summaryId"`
+	UserID    string        `json:"userId"`
+	Messages  []ChatMessage `json:"messages"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+// Answer adalah jawaban atas satu pertanyaan beserta segmen yang dikutip.
+type Answer struct {
+	Answer   string              `json:"answer"`
+	Segments []TranscriptSegment `json:"segments"`
+	Model    string              `json:"model"`
+}
+
+// QAService menjawab pertanyaan tentang transkrip sebuah ringkasan lewat
+// sesi chat Gemini. Riwayat percakapan disimpan per ringkasan per user.
+type QAService struct {
+	geminiModel   *genai.GenerativeModel
+	modelName     string
+	summaries     *SummaryService
+	conversations *store.Collection[Conversation]
+	usage         *UsageService
+	mu            sync.Mutex // melindungi read-modify-write riwayat
+}
+
+// NewQAService membuat instance baru dari QAService.
+func NewQAService(geminiModel *genai.GenerativeModel, modelName string, summaries *SummaryService, conversations *store.Collection[Conversation]) *QAService {
+	return &QAService{
+		geminiModel:   geminiModel,
+		modelName:     modelName,
+		summaries:     summaries,
+		conversations: conversations,
+	}
+}
+
+// TrackUsage mencatat token setiap pertanyaan ke ledger usage.
+func (s *QAService) TrackUsage(usage *UsageService) {
+	s.usage = usage
+}
+
+// Ask menjawab pertanyaan user tentang transkrip ringkasan summaryID dan
+// menambahkan pertanyaan serta jawabannya ke riwayat percakapan.
+func (s *QAService) Ask(ctx context.Context, userID, summaryID, question string) (Answer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return Answer{}, fmt.Errorf("%w: pertanyaan kosong", ErrInvalidQuestion)
+	}
+	if len(question) > maxQuestionLength {
+		return Answer{}, fmt.Errorf("%w: maksimal %d karakter", ErrInvalidQuestion, maxQuestionLength)
+	}
+
+	summary, err := s.summaries.GetSummary(userID, summaryID)
+	if err != nil {
+		return Answer{}, err
+	}
+	if strings.TrimSpace(summary.Transcript) == "" {
+		return Answer{}, ErrNoTranscript
+	}
+	segments := SegmentTranscript(summary.Transcript)
+
+	conv, err := s.conversation(userID, summaryID)
+	if err != nil {
+		return Answer{}, err
+	}
+
+	cs := s.geminiModel.StartChat()
+	cs.History = chatHistory(summary, segments, conv.Messages)
+
+	log.Printf("Mengirim pertanyaan untuk ringkasan %s ke Gemini (%d pesan riwayat)...", summaryID, len(conv.Messages))
+	resp, err := cs.SendMessage(ctx, genai.Text(question))
+	if err != nil {
+		return Answer{}, fmt.Errorf("gagal mengirim pesan ke Gemini: %w", err)
+	}
+	text, err := responseText(resp)
+	s.recordUsage(userID, summaryID, resp, err != nil)
+	if err != nil {
+		return Answer{}, err
+	}
+
+	citations := pickSegments(segments, parseCitations(text, len(segments)))
+	now := time.Now()
+	s.appendMessages(userID, summaryID,
+		ChatMessage{Role: ChatRoleUser, Text: question, CreatedAt: now},
+		ChatMessage{Role: ChatRoleModel, Text: text, Citations: citations, CreatedAt: now},
+	)
+
+	return Answer{Answer: text, Segments: citations, Model: s.modelName}, nil
+}
+
+// recordUsage mencatat token satu pertanyaan, juga jika jawabannya tidak
+// bisa dipakai karena token tetap ditagih.
+func (s *QAService) recordUsage(userID, summaryID string, resp *genai.GenerateContentResponse, failed bool) {
+	if s.usage == nil || resp.UsageMetadata == nil {
+		return
+	}
+	_, err := s.usage.Record(UsageRecord{
+		UserID:    userID,
+		Kind:      UsageKindQA,
+		SummaryID: summaryID,
+		Model:     s.modelName,
+		Usage: Usage{
+			InputTokens:  int64(resp.UsageMetadata.PromptTokenCount),
+			OutputTokens: int64(resp.UsageMetadata.CandidatesTokenCount),
+		},
+		Failed: failed,
+	})
+	if err != nil {
+		log.Printf("ERROR: Gagal mencatat pemakaian tanya-jawab ringkasan %s: %v", summaryID, err)
+	}
+}
+
+// Conversation mengembalikan riwayat percakapan user atas ringkasan. Riwayat
+// yang belum ada dikembalikan kosong.
+func (s *QAService) Conversation(userID, summaryID string) (Conversation, error) {
+	if _, err := s.summaries.GetSummary(userID, summaryID); err != nil {
+		return Conversation{}, err
+	}
+	return s.conversation(userID, summaryID)
+}
+
+// ResetConversation menghapus riwayat percakapan user atas ringkasan.
+func (s *QAService) ResetConversation(userID, summaryID string) error {
+	if _, err := s.summaries.GetSummary(userID, summaryID); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.conversations.Delete(conversationID(userID, summaryID))
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (s *QAService) conversation(userID, summaryID string) (Conversation, error) {
+	id := conversationID(userID, summaryID)
+	conv, err := s.conversations.Get(id)
+	if errors.Is(err, store.ErrNotFound) {
+		now := time.Now()
+		return Conversation{ID: id, SummaryID: summaryID, UserID: userID, Messages: []ChatMessage{}, CreatedAt: now, UpdatedAt: now}, nil
+	}
+	return conv, err
+}
+
+// appendMessages menambahkan pesan ke riwayat yang dibaca ulang dari store,
+// agar pertanyaan paralel tidak saling menimpa. Kegagalan hanya dicatat.
+func (s *QAService) appendMessages(userID, summaryID string, messages ...ChatMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, err := s.conversation(userID, summaryID)
+	if err != nil {
+		log.Printf("WARN: Gagal membaca riwayat percakapan ringkasan %s: %v", summaryID, err)
+		return
+	}
+	conv.Messages = append(conv.Messages, messages...)
+	if len(conv.Messages) > maxStoredMessages {
+		conv.Messages = conv.Messages[len(conv.Messages)-maxStoredMessages:]
+	}
+	conv.UpdatedAt = time.Now()
+	if err := s.conversations.Put(conv.ID, conv); err != nil {
+		log.Printf("WARN: Gagal menyimpan riwayat percakapan %s: %v", conv.ID, err)
+	}
+}
+
+func conversationID(userID, summaryID string) string {
+	return summaryID + ":" + userID
+}
+
+// chatHistory menyusun riwayat chat untuk Gemini: instruksi + transkrip
+// bernomor sebagai giliran pertama, lalu pesan-pesan terakhir percakapan.
+func chatHistory(summary Summary, segments []TranscriptSegment, messages []ChatMessage) []*genai.Content {
+	var b strings.Builder
+	b.WriteString(`Kamu adalah asisten yang menjawab pertanyaan tentang sebuah rapat HANYA berdasarkan transkrip di bawah. Transkrip dibagi menjadi segmen bernomor seperti [S1], [S2], dst. Setiap kali menyebut fakta dari transkrip, cantumkan nomor segmen sumbernya dalam format [S<nomor>], misalnya [S3] atau [S3][S7]. Jika jawabannya tidak ada di transkrip, katakan dengan jujur bahwa informasinya tidak ditemukan. Jawab dalam bahasa yang sama dengan pertanyaan, ringkas dan dalam format Markdown.
+
+TRANSKRIP:
+`)
+	for _, seg := range segments {
+		if seg.Timestamp != "" {
+			fmt.Fprintf(&b, "[S%d] [%s] %s\n", seg.Index, seg.Timestamp, seg.Text)
+		} else {
+			fmt.Fprintf(&b, "[S%d] %s\n", seg.Index, seg.Text)
+		}
+	}
+	if summary.Text != "" {
+		b.WriteString("\nRINGKASAN (hanya sebagai konteks, kutip transkripnya):\n")
+		b.WriteString(summary.Text)
+	}
+
+	history := []*genai.Content{
+		{Role: ChatRoleUser, Parts: []genai.Part{genai.Text(b.String())}},
+		{Role: ChatRoleModel, Parts: []genai.Part{genai.Text("Baik, saya akan menjawab berdasarkan transkrip tersebut dan mencantumkan nomor segmennya.")}},
+	}
+	if len(messages) > maxChatHistory {
+		messages = messages[len(messages)-maxChatHistory:]
+	}
+	// Riwayat harus diawali giliran user agar urutannya tetap bergantian.
+	if len(messages) > 0 && messages[0].Role != ChatRoleUser {
+		messages = messages[1:]
+	}
+	for _, m := range messages {
+		history = append(history, &genai.Content{Role: m.Role, Parts: []genai.Part{genai.Text(m.Text)}})
+	}
+	return history
+}
+
+// responseText mengambil teks dari kandidat pertama respons Gemini.
+func responseText(resp *genai.GenerateContentResponse) (string, error) {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		if len(resp.Candidates) > 0 && resp.Candidates[0].FinishReason != genai.FinishReasonStop {
+			return "", fmt.Errorf("gagal mendapatkan respons AI: %s", resp.Candidates[0].FinishReason)
+		}
+		return "", fmt.Errorf("gagal mendapatkan respons dari AI (kandidat/parts kosong)")
+	}
+	var b strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			b.WriteString(string(txt))
+		}
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("respons AI bukan format teks yang diharapkan")
+	}
+	return b.String(), nil
+}
+
+var (
+	citationPattern = regexp.MustCompile(`\[S(\d+)\]`)
+	timestampPrefix = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2})\]\s*`)
+	speakerPattern  = regexp.MustCompile(`^([^:\n.?!\[\]]{1,40}):\s`)
+	sentenceEnd     = regexp.MustCompile(`[.?!]\s+`)
+)
+
+// parseCitations mengambil nomor segmen unik yang dikutip, terurut naik.
+// Nomor di luar jangkauan segmen diabaikan.
+func parseCitations(text string, segmentCount int) []int {
+	seen := make(map[int]bool)
+	var out []int
+	for _, m := range citationPattern.FindAllStringSubmatch(text, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 || n > segmentCount || seen[n] {
+			continue
+		}
+		seen[n] = true
+		out = append(out, n)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func pickSegments(segments []TranscriptSegment, indexes []int) []TranscriptSegment {
+	out := make([]TranscriptSegment, 0, len(indexes))
+	for _, i := range indexes {
+		if i >= 1 && i <= len(segments) {
+			out = append(out, segments[i-1])
+		}
+	}
+	return out
+}
+
+// SegmentTranscript memecah transkrip menjadi segmen bernomor mulai dari 1.
+// Satu paragraf (giliran bicara) menjadi satu segmen; paragraf yang terlalu
+// panjang dipecah per kalimat dengan label pembicara yang sama. Timestamp
+// "[hh:mm:ss]" di awal paragraf dipisahkan dari teks segmen.
+func SegmentTranscript(text string) []TranscriptSegment {
+	var segments []TranscriptSegment
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.Join(strings.Fields(para), " ")
+		if para == "" {
+			continue
+		}
+		timestamp, speaker := "", ""
+		body := para
+		if m := timestampPrefix.FindStringSubmatch(body); m != nil {
+			timestamp = m[1]
+			body = body[len(m[0]):]
+		}
+		if m := speakerPattern.FindStringSubmatch(body); m != nil {
+			speaker = strings.TrimSpace(m[1])
+			body = strings.TrimSpace(body[len(m[0]):])
+		}
+		for _, chunk := range splitLong(body, maxSegmentLength) {
+			seg := TranscriptSegment{Index: len(segments) + 1, Timestamp: timestamp, Speaker: speaker, Text: chunk}
+			if speaker != "" {
+				seg.Text = speaker + ": " + chunk
+			}
+			segments = append(segments, seg)
+		}
+	}
+	return segments
+}
+
+// splitLong memecah teks per kalimat menjadi potongan paling panjang max
+// karakter. Kalimat yang sendirinya lebih panjang dari max tidak dipotong.
+func splitLong(text string, max int) []string {
+	if len(text) <= max {
+		return []string{text}
+	}
+	var chunks []string
+	var current strings.Builder
+	start := 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+	add := func(sentence string) {
+		if current.Len() > 0 && current.Len()+len(sentence) > max {
+			flush()
+		}
+		current.WriteString(sentence)
+	}
+	for _, loc := range sentenceEnd.FindAllStringIndex(text, -1) {
+		add(text[start:loc[1]])
+		start = loc[1]
+	}
+	add(text[start:])
+	flush()
+	return chunks
+}
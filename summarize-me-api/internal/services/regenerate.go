@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"summarize-me-api/internal/store"
+	"time"
+)
+
+// RegenerateService membuat ulang ringkasan dari transkrip yang tersimpan,
+// tanpa upload dan transkripsi ulang. Setiap hasil disimpan sebagai varian
+// pada ringkasan yang sama.
+type RegenerateService struct {
+	summarizer *SummarizeService
+	summaries  *SummaryService
+	usage      *UsageService
+	glossaries *GlossaryService
+	jobs       *JobService
+}
+
+// NewRegenerateService membuat instance baru dari RegenerateService.
+func NewRegenerateService(summarizer *SummarizeService, summaries *SummaryService) *RegenerateService {
+	return &RegenerateService{summarizer: summarizer, summaries: summaries}
+}
+
+// TrackUsage mencatat pemakaian setiap pembuatan ulang ke ledger usage.
+func (r *RegenerateService) TrackUsage(usage *UsageService) {
+	r.usage = usage
+}
+
+// UseGlossaries memakai glosarium yang sama dengan job asal ringkasan (dari
+// jobs) agar ejaan istilah di varian baru sama dengan ringkasan aslinya.
+func (r *RegenerateService) UseGlossaries(glossaries *GlossaryService, jobs *JobService) {
+	r.glossaries = glossaries
+	r.jobs = jobs
+}
+
+// Options mengembalikan pilihan template, panjang, bahasa dan model.
+func (r *RegenerateService) Options() SummaryOptionsCatalog {
+	return r.summarizer.Options()
+}
+
+// Regenerate meringkas ulang transkrip ringkasan dengan opts. Jika activate,
+// varian baru langsung menggantikan isi ringkasan.
+func (r *RegenerateService) Regenerate(ctx context.Context, userID, summaryID string, opts SummaryOptions, activate bool) (SummaryVariant, Summary, error) {
+	opts, err := r.summarizer.ValidateOptions(opts)
+	if err != nil {
+		return SummaryVariant{}, Summary{}, err
+	}
+	summary, err := r.summaries.GetSummary(userID, summaryID)
+	if err != nil {
+		return SummaryVariant{}, Summary{}, err
+	}
+	if strings.TrimSpace(summary.Transcript) == "" {
+		return SummaryVariant{}, Summary{}, ErrNoTranscript
+	}
+	if len(summary.Variants) >= maxVariants {
+		return SummaryVariant{}, Summary{}, ErrTooManyVariants
+	}
+
+	vocab, err := r.vocabulary(summary)
+	if err != nil {
+		return SummaryVariant{}, Summary{}, err
+	}
+	result, err := r.summarizer.SummarizeWithOptions(ctx, summary.Transcript, opts, vocab)
+	if err != nil {
+		r.recordUsage(UsageRecord{UserID: userID, SummaryID: summaryID}, nil, err)
+		return SummaryVariant{}, Summary{}, err
+	}
+	// Catat model yang benar-benar dipakai, termasuk hasil routing/fallback.
+	opts.Model = result.Model
+	variant := SummaryVariant{
+		ID:             store.NewID(),
+		SummaryOptions: opts,
+		FallbackFrom:   result.FallbackFrom,
+		PromptVersion:  result.PromptVersion,
+		Text:           result.Summary,
+		CreatedAt:      time.Now(),
+	}
+	summary, err = r.summaries.AddVariant(userID, summaryID, variant, activate)
+	if err != nil {
+		return SummaryVariant{}, Summary{}, err
+	}
+	r.recordUsage(UsageRecord{ID: variant.ID, UserID: userID, SummaryID: summaryID}, result, nil)
+	return variant, summary, nil
+}
+
+// vocabulary menyusun kosakata dengan override glosarium job asal ringkasan.
+// Glosarium yang sudah dihapus sejak job itu dilewati dengan peringatan,
+// bukan menggagalkan pembuatan ulang.
+func (r *RegenerateService) vocabulary(summary Summary) (*Vocabulary, error) {
+	if r.glossaries == nil {
+		return nil, nil
+	}
+	var override *GlossaryOverride
+	if summary.JobID != "" {
+		if job, err := r.jobs.GetJob(summary.JobID); err == nil && job.UserID == summary.UserID {
+			override = job.Glossary
+		}
+	}
+	vocab, err := r.glossaries.Resolve(summary.UserID, override)
+	if errors.Is(err, ErrInvalidGlossary) && override != nil && len(override.IDs) > 0 {
+		log.Printf("WARN: Glosarium job %s tidak lagi valid, hanya istilah tambahannya yang dipakai: %v", summary.JobID, err)
+		return r.glossaries.Resolve(summary.UserID, &GlossaryOverride{IDs: []string{GlossaryNone}, Terms: override.Terms})
+	}
+	return vocab, err
+}
+
+// recordUsage mencatat pemakaian pembuatan ulang, termasuk yang gagal.
+func (r *RegenerateService) recordUsage(rec UsageRecord, result *SummarizeResult, err error) {
+	if r.usage == nil {
+		return
+	}
+	rec.Kind = UsageKindRegenerate
+	r.usage.RecordOutcome(rec, result, err)
+}
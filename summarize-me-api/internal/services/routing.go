@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"summarize-me-api/internal/resilience"
+	"unicode/utf8"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ModelRoute memilih model awal peringkasan saat user tidak memilih model
+// sendiri. Semua kondisi yang diisi harus terpenuhi; kondisi kosong atau 0
+// diabaikan. Urutan field sama dengan config.ModelRouteConfig.
+type ModelRoute struct {
+	Model string
+	// MinChars dan MaxChars membatasi panjang transkrip (karakter).
+	MinChars  int
+	MaxChars  int
+	Lengths   []string
+	Templates []string
+}
+
+func (r ModelRoute) matches(chars int, opts SummaryOptions) bool {
+	if r.MinChars > 0 && chars < r.MinChars {
+		return false
+	}
+	if r.MaxChars > 0 && chars > r.MaxChars {
+		return false
+	}
+	if len(r.Lengths) > 0 && !slices.Contains(r.Lengths, opts.Length) {
+		return false
+	}
+	return len(r.Templates) == 0 || slices.Contains(r.Templates, opts.Template)
+}
+
+// UseModelChain mengatur routing model dan urutan fallback. routes dicek
+// berurutan dan yang pertama cocok menentukan model awal; tanpa yang cocok,
+// model utama dipakai. Jika model awal gagal (kuota habis, konten diblokir,
+// provider down), model utama lalu fallbacks dicoba berurutan.
+func (s *SummarizeService) UseModelChain(client *genai.Client, fallbacks []string, routes []ModelRoute) error {
+	for i, r := range routes {
+		if r.Model == "" {
+			return fmt.Errorf("route %d: model wajib diisi", i+1)
+		}
+		for _, l := range r.Lengths {
+			if _, ok := lengthInstructions[l]; !ok {
+				return fmt.Errorf("route %d: length %q tidak dikenal", i+1, l)
+			}
+		}
+		for _, t := range r.Templates {
+			if _, ok := templateInstructions[t]; !ok {
+				return fmt.Errorf("route %d: template %q tidak dikenal", i+1, t)
+			}
+		}
+		s.addModel(client, r.Model)
+	}
+	s.fallbacks = nil
+	for _, name := range fallbacks {
+		if name != "" && !slices.Contains(s.fallbacks, name) {
+			s.addModel(client, name)
+			s.fallbacks = append(s.fallbacks, name)
+		}
+	}
+	s.routes = routes
+	return nil
+}
+
+// modelChain mengembalikan urutan model yang dicoba untuk transkrip text.
+func (s *SummarizeService) modelChain(text string, opts SummaryOptions) []string {
+	first := opts.Model
+	if first == "" {
+		first = s.modelName
+		chars := utf8.RuneCountInString(text)
+		for _, r := range s.routes {
+			if r.matches(chars, opts) {
+				first = r.Model
+				break
+			}
+		}
+	}
+	// Model utama menjadi cadangan pertama bagi model hasil routing atau
+	// pilihan user.
+	chain := []string{first}
+	if first != s.modelName {
+		chain = append(chain, s.modelName)
+	}
+	for _, name := range s.fallbacks {
+		if !slices.Contains(chain, name) {
+			chain = append(chain, name)
+		}
+	}
+	return chain
+}
+
+// generated adalah teks ringkasan beserta model yang menghasilkannya dan
+// token yang dipakai.
+type generated struct {
+	Text         string
+	Model        string
+	FallbackFrom string
+	Usage        Usage
+	// Failed adalah token model di chain yang gagal; diisi juga jika
+	// semua model gagal.
+	Failed []ModelUsage
+}
+
+// generate mencoba setiap model di chain berurutan hingga salah satu
+// berhasil. Retry untuk error sementara sudah ditangani executor per model,
+// jadi model berikutnya hanya dicoba setelah model sebelumnya benar-benar
+// gagal atau circuit breaker-nya terbuka.
+func (s *SummarizeService) generate(ctx context.Context, chain []string, prompt string) (generated, error) {
+	var errs []error
+	var failed []ModelUsage
+	for i, name := range chain {
+		text, usage, err := s.generateWith(ctx, name, prompt)
+		if err == nil {
+			out := generated{Text: text, Model: name, Usage: usage, Failed: failed}
+			if i > 0 {
+				out.FallbackFrom = chain[0]
+				log.Printf("WARN: Ringkasan dibuat oleh model fallback %s (model awal %s)", name, chain[0])
+			}
+			return out, nil
+		}
+		if !usage.isZero() {
+			failed = append(failed, ModelUsage{Model: name, Usage: usage})
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(chain)-1 {
+			log.Printf("WARN: Model %s gagal, beralih ke %s: %v", name, chain[i+1], err)
+		}
+	}
+	return generated{Failed: failed}, errors.Join(errs...)
+}
+
+// geminiExecutor mengembalikan executor untuk model name, atau nil jika
+// UseResilience belum dipanggil.
+func (s *SummarizeService) geminiExecutor(name string) *resilience.Executor {
+	if s.gemini == nil {
+		return nil
+	}
+	return s.gemini(name)
+}
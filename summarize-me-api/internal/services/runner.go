@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"summarize-me-api/internal/transcript"
+	"sync"
+)
+
+// ErrQueueFull dikembalikan jika antrean job sedang penuh.
+var ErrQueueFull = errors.New("antrean job penuh, coba lagi nanti")
+
+// InputFile adalah satu file audio yang akan diproses sebuah job.
+type InputFile struct {
+	Name string
+	Data []byte
+}
+
+// FetchFunc mengambil input job yang belum tersedia saat job dibuat, mis.
+// audio dari URL remote. Dijalankan oleh worker.
+type FetchFunc func(ctx context.Context) (InputFile, error)
+
+type queuedJob struct {
+	job   Job
+	data  []byte
+	fetch FetchFunc
+}
+
+// JobRunner menjalankan transkripsi + peringkasan untuk sebuah job, baik
+// langsung di request (Run) maupun di background lewat antrean (Enqueue).
+// Antrean hanya di memori; job yang tertinggal saat restart ditandai gagal
+// oleh JobService.FailStale.
+type JobRunner struct {
+	summarizer *SummarizeService
+	jobs       *JobService
+	summaries  *SummaryService
+	glossaries *GlossaryService
+	queue      chan queuedJob
+
+	mu       sync.Mutex
+	inflight map[string]chan struct{} // userID + hash audio yang sedang ditranskrip
+}
+
+// NewJobRunner membuat JobRunner dan menjalankan worker sebanyak workers.
+func NewJobRunner(summarizer *SummarizeService, jobs *JobService, summaries *SummaryService, workers, queueSize int) *JobRunner {
+	r := &JobRunner{
+		summarizer: summarizer,
+		jobs:       jobs,
+		summaries:  summaries,
+		queue:      make(chan queuedJob, queueSize),
+		inflight:   make(map[string]chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		go r.worker()
+	}
+	return r
+}
+
+// UseGlossaries mengaktifkan glosarium user untuk frasa Speech-to-Text dan
+// ejaan istilah di ringkasan.
+func (r *JobRunner) UseGlossaries(glossaries *GlossaryService) {
+	r.glossaries = glossaries
+}
+
+// ResolveGlossary menyusun kosakata job milik userID dengan override o.
+// Dipakai juga handler untuk menolak override yang tidak valid sebelum job
+// dibuat. Tanpa GlossaryService hasilnya selalu nil.
+func (r *JobRunner) ResolveGlossary(userID string, o *GlossaryOverride) (*Vocabulary, error) {
+	if r.glossaries == nil {
+		return nil, nil
+	}
+	return r.glossaries.Resolve(userID, o)
+}
+
+// Run memproses job yang sudah dicatat lalu menandainya selesai. Ringkasan
+// yang gagal dicatat tidak menggagalkan job; Summary kosong dikembalikan.
+//
+// Jika user pernah mengunggah audio yang sama (SHA-256 identik) dengan
+// glosarium yang sama, transkrip lamanya dipakai ulang sehingga
+// Speech-to-Text dilewati; ringkasannya tetap dibuat baru. Jika audio yang
+// sama sedang diproses job lain milik user, Run menunggu job itu selesai
+// lalu memakai transkripnya. job.Force mematikan perilaku ini.
+func (r *JobRunner) Run(ctx context.Context, job Job, data []byte) (*SummarizeResult, Summary, error) {
+	hash := ContentHash(data)
+	dedup := !job.Force && !transcript.IsTranscriptFile(job.FileName)
+	if dedup {
+		// Slot dilepas setelah run mencatat ringkasan, agar job yang
+		// menunggu bisa menemukannya lewat FindByContentHash.
+		release, err := r.acquire(ctx, job, hash)
+		if err != nil {
+			r.finish(job, JobOutcome{Err: err})
+			return nil, Summary{}, err
+		}
+		defer release()
+	}
+	return r.run(job, func() (*SummarizeResult, error) {
+		vocab, err := r.ResolveGlossary(job.UserID, job.Glossary)
+		if err != nil {
+			return nil, err
+		}
+		// Frasa glosarium memengaruhi hasil Speech-to-Text, jadi transkrip
+		// dari glosarium lain tidak dipakai ulang.
+		vocabHash := vocab.hash()
+		if dedup {
+			if prev, ok := r.summaries.FindByContentHash(job.UserID, hash, vocabHash); ok {
+				log.Printf("Job %s: audio sama dengan ringkasan %s, transkrip dipakai ulang", job.ID, prev.ID)
+				// Dinormalisasi ulang agar ejaan istilah pasti mengikuti
+				// glosarium, juga untuk transkrip yang disimpan sebelum
+				// normalisasi glosarium ada.
+				result, err := r.summarizer.SummarizeTranscriptWith(ctx, vocab.normalize(prev.Transcript), vocab)
+				if err != nil {
+					return nil, err
+				}
+				result.LanguageCode = prev.LanguageCode
+				result.Keyframes = prev.Keyframes
+				result.ContentHash = hash
+				result.VocabularyHash = vocabHash
+				result.TranscriptFrom = prev.ID
+				return result, nil
+			}
+		}
+		result, err := r.summarizer.TranscribeAndSummarizeWith(ctx, data, job.FileName, vocab)
+		if err != nil {
+			return nil, err
+		}
+		result.ContentHash = hash
+		result.VocabularyHash = vocabHash
+		return result, nil
+	})
+}
+
+// acquire menandai audio hash milik user job sedang diproses. Jika job lain
+// sedang memproses audio yang sama, acquire menunggu sampai job itu selesai
+// (berhasil atau gagal) atau ctx berakhir.
+func (r *JobRunner) acquire(ctx context.Context, job Job, hash string) (release func(), err error) {
+	key := job.UserID + ":" + hash
+	for {
+		r.mu.Lock()
+		running, busy := r.inflight[key]
+		if !busy {
+			done := make(chan struct{})
+			r.inflight[key] = done
+			r.mu.Unlock()
+			return func() {
+				r.mu.Lock()
+				delete(r.inflight, key)
+				r.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		r.mu.Unlock()
+
+		log.Printf("Job %s: audio yang sama sedang diproses job lain, menunggu", job.ID)
+		select {
+		case <-running:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// ContentHash menghitung SHA-256 (hex) isi file untuk deduplikasi.
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// RunTranscript seperti Run, tetapi untuk transkrip yang sudah jadi (mis.
+// hasil sesi live) sehingga Speech-to-Text dilewati.
+func (r *JobRunner) RunTranscript(ctx context.Context, job Job, text string) (*SummarizeResult, Summary, error) {
+	return r.run(job, func() (*SummarizeResult, error) {
+		vocab, err := r.ResolveGlossary(job.UserID, job.Glossary)
+		if err != nil {
+			return nil, err
+		}
+		return r.summarizer.SummarizeTranscriptWith(ctx, text, vocab)
+	})
+}
+
+func (r *JobRunner) run(job Job, summarize func() (*SummarizeResult, error)) (*SummarizeResult, Summary, error) {
+	result, err := summarize()
+	if err != nil {
+		r.finish(job, JobOutcome{Err: err})
+		return nil, Summary{}, err
+	}
+
+	summary, err := r.summaries.CreateSummary(job, result)
+	if err != nil {
+		log.Printf("WARN: Gagal mencatat ringkasan untuk job %s: %v", job.ID, err)
+	}
+	r.finish(job, JobOutcome{Result: result, SummaryID: summary.ID})
+	return result, summary, nil
+}
+
+// Enqueue mencatat job berstatus queued dan memasukkannya ke antrean.
+func (r *JobRunner) Enqueue(job Job, data []byte) (Job, error) {
+	return r.enqueue(queuedJob{job: job, data: data})
+}
+
+// EnqueueFetch seperti Enqueue, tetapi input diambil worker lewat fetch.
+// Nama dan ukuran file job diperbarui setelah fetch berhasil.
+func (r *JobRunner) EnqueueFetch(job Job, fetch FetchFunc) (Job, error) {
+	return r.enqueue(queuedJob{job: job, fetch: fetch})
+}
+
+func (r *JobRunner) enqueue(q queuedJob) (Job, error) {
+	q.job.Status = JobQueued
+	job, err := r.jobs.StartJob(q.job)
+	if err != nil {
+		return job, fmt.Errorf("gagal mencatat job: %w", err)
+	}
+	q.job = job
+	select {
+	case r.queue <- q:
+		return job, nil
+	default:
+		r.finish(job, JobOutcome{Err: ErrQueueFull})
+		return job, ErrQueueFull
+	}
+}
+
+func (r *JobRunner) worker() {
+	for q := range r.queue {
+		job, err := r.jobs.MarkProcessing(q.job.ID)
+		if err != nil {
+			log.Printf("WARN: Gagal menandai job %s diproses: %v", q.job.ID, err)
+			job = q.job
+		}
+		log.Printf("Worker memproses job %s (%s)", job.ID, job.FileName)
+		r.process(job, q)
+	}
+}
+
+func (r *JobRunner) process(job Job, q queuedJob) {
+	ctx := context.Background()
+	data := q.data
+	if q.fetch != nil {
+		input, err := q.fetch(ctx)
+		if err != nil {
+			log.Printf("ERROR: Gagal mengambil input job %s: %v", job.ID, err)
+			r.finish(job, JobOutcome{Err: err})
+			return
+		}
+		if updated, err := r.jobs.SetInput(job.ID, input.Name, len(input.Data)); err == nil {
+			job = updated
+		}
+		data = input.Data
+	}
+	if _, _, err := r.Run(ctx, job, data); err != nil {
+		log.Printf("ERROR: Job %s gagal: %v", job.ID, err)
+	}
+}
+
+// finish memperbarui status job; kegagalan hanya dicatat di log.
+func (r *JobRunner) finish(job Job, outcome JobOutcome) {
+	if _, err := r.jobs.FinishJob(job.ID, outcome); err != nil {
+		log.Printf("WARN: Gagal memperbarui status job %s: %v", job.ID, err)
+	}
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"summarize-me-api/internal/embedding"
+	"summarize-me-api/internal/store"
+	"summarize-me-api/internal/vector"
+	"sync"
+	"time"
+)
+
+// ErrInvalidQuery dikembalikan jika query pencarian kosong atau terlalu panjang.
+var ErrInvalidQuery = errors.New("query pencarian tidak valid")
+
+// Jenis potongan yang diindeks untuk pencarian.
+const (
+	ChunkSummary    = "summary"
+	ChunkTranscript = "transcript"
+)
+
+const (
+	// searchChunkSize adalah panjang target satu potongan yang di-embed.
+	searchChunkSize = 1000
+	// maxQueryLength membatasi panjang query pencarian (karakter).
+	maxQueryLength = 500
+	// indexTimeout membatasi waktu embedding satu ringkasan.
+	indexTimeout = 5 * time.Minute
+)
+
+// SearchDocument adalah semua potongan terindeks dari satu ringkasan.
+// Disimpan per ringkasan (bukan per potongan) agar satu ringkasan cukup
+// satu kali tulis ke store. Vektor potongannya disimpan terpisah di
+// vector.Store dengan ID dan urutan yang sama dengan Chunks.
+type SearchDocument struct {
+	// ID sama dengan ID ringkasan.
+	ID     string `json:"id"`
+	UserID string `json:"userId"`
+	// Model adalah nama embedder; dokumen dari model lain diindeks ulang.
+	Model     string        `json:"model"`
+	Chunks    []SearchChunk `json:"chunks"`
+	CreatedAt time.Time     `json:"createdAt"`
+	// Fingerprint adalah hash teks yang di-embed; jika berbeda dengan isi
+	// ringkasan saat ini, dokumen diindeks ulang.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// SearchChunk adalah potongan ringkasan atau transkrip yang diindeks.
+type SearchChunk struct {
+	ID        string `json:"id"`
+	SummaryID string `json:"summaryId"`
+	FileName  string `json:"fileName"`
+	Kind      string `json:"kind"`
+	Text      string `json:"text"`
+	// SegmentStart dan SegmentEnd adalah rentang nomor segmen transkrip
+	// (sama dengan kutipan tanya-jawab); nol untuk potongan ringkasan.
+	SegmentStart     int       `json:"segmentStart,omitempty"`
+	SegmentEnd       int       `json:"segmentEnd,omitempty"`
+	Timestamp        string    `json:"timestamp,omitempty"`
+	SummaryCreatedAt time.Time `json:"summaryCreatedAt"`
+	// Vector hanya terisi pada dokumen dari versi lama yang menyimpan
+	// vektor di JSON; saat start vektornya dipindah ke vector.Store.
+	Vector []float32 `json:"vector,omitempty"`
+}
+
+// SearchResult adalah satu hasil pencarian, terurut dari skor tertinggi.
+type SearchResult struct {
+	SummaryID    string  `json:"summaryId"`
+	SummaryURL   string  `json:"summaryUrl"`
+	FileName     string  `json:"fileName"`
+	Kind         string  `json:"kind"`
+	Text         string  `json:"text"`
+	Score        float32 `json:"score"`
+	SegmentStart int     `json:"segmentStart,omitempty"`
+	SegmentEnd   int     `json:"segmentEnd,omitempty"`
+	// Timestamp adalah posisi potongan dalam rekaman ("hh:mm:ss") jika ada.
+	Timestamp string `json:"timestamp,omitempty"`
+	// MeetingAt adalah waktu ringkasan rapat dibuat.
+	MeetingAt time.Time `json:"meetingAt"`
+}
+
+// SearchService mengindeks ringkasan dan transkrip sebagai vektor lalu
+// menjawab pencarian semantik atas riwayat rapat seorang user. Setiap user
+// memiliki index HNSW sendiri di memori.
+type SearchService struct {
+	embedder  embedding.Embedder
+	summaries *SummaryService
+	documents *store.Collection[SearchDocument]
+	vectors   *vector.Store
+
+	mu       sync.RWMutex
+	indexes  map[string]*vector.Index
+	chunks   map[string]SearchChunk // tanpa Vector; vektor ada di index
+	inFlight map[string]bool
+	// rerun menandai ringkasan yang berubah lagi selagi sedang diindeks.
+	rerun map[string]bool
+}
+
+// NewSearchService membuat SearchService dan membangun index dari dokumen
+// yang tersimpan. Dokumen dari embedder lain, atau yang vektornya hilang,
+// dihapus agar diindeks ulang oleh Backfill.
+func NewSearchService(embedder embedding.Embedder, summaries *SummaryService, documents *store.Collection[SearchDocument], vectors *vector.Store) *SearchService {
+	s := &SearchService{
+		embedder:  embedder,
+		summaries: summaries,
+		documents: documents,
+		vectors:   vectors,
+		indexes:   make(map[string]*vector.Index),
+		chunks:    make(map[string]SearchChunk),
+		inFlight:  make(map[string]bool),
+		rerun:     make(map[string]bool),
+	}
+
+	stale := 0
+	for _, doc := range documents.List(nil) {
+		if err := s.load(doc); err != nil {
+			log.Printf("WARN: Dokumen pencarian %s akan diindeks ulang: %v", doc.ID, err)
+			s.remove(doc)
+			if documents.Delete(doc.ID) == nil {
+				vectors.Delete(doc.ID)
+				stale++
+			}
+		}
+	}
+	if stale > 0 {
+		log.Printf("Menghapus %d dokumen pencarian yang harus diindeks ulang", stale)
+	}
+	return s
+}
+
+// load memasukkan dokumen tersimpan beserta vektornya ke index. Dokumen
+// versi lama yang masih menyimpan vektor di JSON dipindah dulu ke
+// s.vectors.
+func (s *SearchService) load(doc SearchDocument) error {
+	if doc.Model != s.embedder.Name() {
+		return fmt.Errorf("dibuat oleh embedder lain (%s)", doc.Model)
+	}
+	var vecs [][]float32
+	if len(doc.Chunks) > 0 && doc.Chunks[0].Vector != nil {
+		vecs = make([][]float32, len(doc.Chunks))
+		for i := range doc.Chunks {
+			vecs[i] = doc.Chunks[i].Vector
+			doc.Chunks[i].Vector = nil
+		}
+		if err := s.vectors.Put(doc.ID, vecs); err != nil {
+			return err
+		}
+		if err := s.documents.Put(doc.ID, doc); err != nil {
+			return err
+		}
+	} else {
+		var err error
+		if vecs, err = s.vectors.Get(doc.ID); err != nil {
+			return err
+		}
+		if len(vecs) != len(doc.Chunks) {
+			return fmt.Errorf("jumlah vektor %d, potongan %d", len(vecs), len(doc.Chunks))
+		}
+	}
+	return s.add(doc, vecs)
+}
+
+// add memasukkan potongan dokumen beserta vektornya (urutan sama dengan
+// doc.Chunks) ke index user. Pemanggil harus memegang s.mu untuk ditulis,
+// kecuali saat konstruksi.
+func (s *SearchService) add(doc SearchDocument, vecs [][]float32) error {
+	idx := s.index(doc.UserID)
+	for i, c := range doc.Chunks {
+		if err := idx.Add(c.ID, vecs[i]); err != nil {
+			return err
+		}
+		s.chunks[c.ID] = c
+	}
+	return nil
+}
+
+// remove mengeluarkan potongan dokumen dari index user. Pemanggil harus
+// memegang s.mu untuk ditulis.
+func (s *SearchService) remove(doc SearchDocument) {
+	idx := s.index(doc.UserID)
+	for _, c := range doc.Chunks {
+		idx.Remove(c.ID)
+		delete(s.chunks, c.ID)
+	}
+}
+
+// index mengembalikan index milik user, membuatnya jika belum ada.
+// Pemanggil harus memegang s.mu untuk ditulis, kecuali saat konstruksi.
+func (s *SearchService) index(userID string) *vector.Index {
+	idx, ok := s.indexes[userID]
+	if !ok {
+		idx = vector.New(vector.Options{})
+		s.indexes[userID] = idx
+	}
+	return idx
+}
+
+// isIndexed mengecek apakah ringkasan sudah punya dokumen pencarian.
+func (s *SearchService) isIndexed(summaryID string) bool {
+	_, err := s.documents.Get(summaryID)
+	return err == nil
+}
+
+// HandleSummarySaved adalah SummaryListener yang mengindeks ringkasan baru
+// dan mengindeks ulang ringkasan yang isinya berubah (PATCH, aktivasi varian).
+func (s *SearchService) HandleSummarySaved(summary Summary) {
+	if err := s.IndexSummary(context.Background(), summary); err != nil {
+		log.Printf("ERROR: Gagal mengindeks ringkasan %s untuk pencarian: %v", summary.ID, err)
+	}
+}
+
+// Backfill mengindeks ringkasan yang belum punya potongan pencarian, mis.
+// yang dibuat sebelum fitur ini ada atau sebelum embedder diganti.
+func (s *SearchService) Backfill(ctx context.Context) {
+	pending := s.summaries.ListSummaries(func(sum Summary) bool { return !s.isIndexed(sum.ID) })
+	if len(pending) == 0 {
+		return
+	}
+	log.Printf("Mengindeks %d ringkasan lama untuk pencarian...", len(pending))
+	done := 0
+	for _, summary := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.IndexSummary(ctx, summary); err != nil {
+			log.Printf("WARN: Gagal mengindeks ringkasan %s: %v", summary.ID, err)
+			continue
+		}
+		done++
+	}
+	log.Printf("Backfill pencarian selesai: %d dari %d ringkasan diindeks", done, len(pending))
+}
+
+// IndexSummary membuat embedding untuk ringkasan dan transkripnya. Ringkasan
+// yang isinya tidak berubah sejak diindeks dilewati. Jika ringkasan yang
+// sama sedang diindeks, pengindeksan diulang setelahnya dengan isi terbaru.
+func (s *SearchService) IndexSummary(ctx context.Context, summary Summary) error {
+	s.mu.Lock()
+	if s.inFlight[summary.ID] {
+		s.rerun[summary.ID] = true
+		s.mu.Unlock()
+		return nil
+	}
+	s.inFlight[summary.ID] = true
+	s.mu.Unlock()
+
+	for {
+		// Baca ulang agar perubahan beruntun tidak tertukar urutannya.
+		latest, err := s.summaries.GetSummary(summary.UserID, summary.ID)
+		if err == nil {
+			err = s.indexSummary(ctx, latest)
+		}
+
+		s.mu.Lock()
+		if err == nil && s.rerun[summary.ID] {
+			delete(s.rerun, summary.ID)
+			s.mu.Unlock()
+			continue
+		}
+		delete(s.rerun, summary.ID)
+		delete(s.inFlight, summary.ID)
+		s.mu.Unlock()
+		return err
+	}
+}
+
+// indexSummary meng-embed satu versi ringkasan dan mengganti dokumen lamanya.
+func (s *SearchService) indexSummary(ctx context.Context, summary Summary) error {
+	fingerprint := searchFingerprint(summary)
+	old, err := s.documents.Get(summary.ID)
+	hasOld := err == nil
+	if hasOld && old.Fingerprint == fingerprint {
+		return nil
+	}
+
+	chunks := buildSearchChunks(summary)
+	if len(chunks) == 0 {
+		return nil
+	}
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Text
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, indexTimeout)
+	defer cancel()
+	vectors, err := s.embedder.Embed(ctx, embedding.TaskDocument, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(chunks) {
+		return fmt.Errorf("jumlah embedding tidak sesuai: %d dari %d", len(vectors), len(chunks))
+	}
+
+	doc := SearchDocument{
+		ID:          summary.ID,
+		UserID:      summary.UserID,
+		Model:       s.embedder.Name(),
+		Chunks:      chunks,
+		CreatedAt:   time.Now(),
+		Fingerprint: fingerprint,
+	}
+	// Vektor ditulis lebih dulu: dokumen tanpa vektor yang cocok dihapus
+	// dan diindeks ulang saat start.
+	if err := s.vectors.Put(doc.ID, vectors); err != nil {
+		return fmt.Errorf("gagal menyimpan vektor pencarian: %w", err)
+	}
+	if err := s.documents.Put(doc.ID, doc); err != nil {
+		return fmt.Errorf("gagal menyimpan dokumen pencarian: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if hasOld {
+		s.remove(old)
+	}
+	if err := s.add(doc, vectors); err != nil {
+		return fmt.Errorf("gagal menambah potongan ke index: %w", err)
+	}
+	log.Printf("Ringkasan %s diindeks untuk pencarian (%d potongan)", summary.ID, len(chunks))
+	return nil
+}
+
+// Search mencari potongan ringkasan/transkrip milik user yang paling mirip
+// maknanya dengan query.
+func (s *SearchService) Search(ctx context.Context, userID, query string, limit int) ([]SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: query kosong", ErrInvalidQuery)
+	}
+	if len(query) > maxQueryLength {
+		return nil, fmt.Errorf("%w: maksimal %d karakter", ErrInvalidQuery, maxQueryLength)
+	}
+
+	s.mu.RLock()
+	idx, ok := s.indexes[userID]
+	s.mu.RUnlock()
+	if !ok || idx.Len() == 0 {
+		return []SearchResult{}, nil
+	}
+
+	vectors, err := s.embedder.Embed(ctx, embedding.TaskQuery, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := idx.Search(vectors[0], limit)
+	results := make([]SearchResult, 0, len(hits))
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, hit := range hits {
+		c, ok := s.chunks[hit.ID]
+		if !ok || hit.Score <= 0 {
+			continue
+		}
+		results = append(results, SearchResult{
+			SummaryID:    c.SummaryID,
+			SummaryURL:   "/api/summaries/" + c.SummaryID,
+			FileName:     c.FileName,
+			Kind:         c.Kind,
+			Text:         c.Text,
+			Score:        hit.Score,
+			SegmentStart: c.SegmentStart,
+			SegmentEnd:   c.SegmentEnd,
+			Timestamp:    c.Timestamp,
+			MeetingAt:    c.SummaryCreatedAt,
+		})
+	}
+	return results, nil
+}
+
+// searchFingerprint meng-hash bagian ringkasan yang ikut di-embed.
+func searchFingerprint(summary Summary) string {
+	h := sha256.New()
+	h.Write([]byte(summary.FileName))
+	h.Write([]byte{0})
+	h.Write([]byte(summary.Text))
+	h.Write([]byte{0})
+	h.Write([]byte(summary.Transcript))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// buildSearchChunks memecah ringkasan (per paragraf) dan transkrip (per
+// kelompok segmen) menjadi potongan sekitar searchChunkSize karakter.
+func buildSearchChunks(summary Summary) []SearchChunk {
+	base := SearchChunk{
+		SummaryID:        summary.ID,
+		FileName:         summary.FileName,
+		SummaryCreatedAt: summary.CreatedAt,
+	}
+	var chunks []SearchChunk
+	add := func(c SearchChunk) {
+		c.ID = fmt.Sprintf("%s:%s:%d", summary.ID, c.Kind, len(chunks))
+		chunks = append(chunks, c)
+	}
+
+	var b strings.Builder
+	for _, para := range strings.Split(summary.Text, "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		if b.Len() > 0 && b.Len()+len(para) > searchChunkSize {
+			c := base
+			c.Kind, c.Text = ChunkSummary, b.String()
+			add(c)
+			b.Reset()
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(para)
+	}
+	if b.Len() > 0 {
+		c := base
+		c.Kind, c.Text = ChunkSummary, b.String()
+		add(c)
+	}
+
+	var group []TranscriptSegment
+	size := 0
+	flush := func() {
+		if len(group) == 0 {
+			return
+		}
+		texts := make([]string, len(group))
+		for i, seg := range group {
+			texts[i] = seg.Text
+		}
+		c := base
+		c.Kind = ChunkTranscript
+		c.Text = strings.Join(texts, "\n")
+		c.SegmentStart = group[0].Index
+		c.SegmentEnd = group[len(group)-1].Index
+		c.Timestamp = group[0].Timestamp
+		add(c)
+		group, size = nil, 0
+	}
+	for _, seg := range SegmentTranscript(summary.Transcript) {
+		if size > 0 && size+len(seg.Text) > searchChunkSize {
+			flush()
+		}
+		group = append(group, seg)
+		size += len(seg.Text)
+	}
+	flush()
+	return chunks
+}
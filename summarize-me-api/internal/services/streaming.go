@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	speech "cloud.google.com/go/speech/apiv1"
+	"cloud.google.com/go/speech/apiv1/speechpb"
+)
+
+// maxStreamDuration sedikit di bawah batas ~5 menit per stream
+// StreamingRecognize; setelahnya stream dibuka ulang secara transparan.
+const maxStreamDuration = 280 * time.Second
+
+// StreamConfig adalah format audio yang dikirim klien.
+type StreamConfig struct {
+	// Encoding: "webm_opus", "ogg_opus" atau "linear16".
+	Encoding        string
+	SampleRateHertz int32
+	LanguageCode    string
+	// Vocabulary dikirim sebagai SpeechContexts; boleh nil.
+	Vocabulary *Vocabulary
+}
+
+// StreamSegment adalah potongan transkrip dari stream. Segmen interim bisa
+// berubah; segmen final tidak.
+type StreamSegment struct {
+	Text   string
+	Final  bool
+	Offset time.Duration
+}
+
+// TranscriptStream adalah satu sesi transkripsi streaming.
+type TranscriptStream interface {
+	// Send mengirim potongan audio berikutnya.
+	Send(audio []byte) error
+	// Recv mengembalikan segmen berikutnya, atau io.EOF setelah CloseSend
+	// dan semua hasil terbaca.
+	Recv() (StreamSegment, error)
+	// CloseSend menandai audio sudah habis.
+	CloseSend() error
+}
+
+// StreamTranscriber membuka sesi transkripsi streaming. Implementasi bawaan
+// memakai Speech-to-Text StreamingRecognize.
+type StreamTranscriber interface {
+	Start(ctx context.Context, cfg StreamConfig) (TranscriptStream, error)
+}
+
+// SpeechStreamTranscriber adalah StreamTranscriber berbasis Google Speech-to-Text.
+type SpeechStreamTranscriber struct {
+	client *speech.Client
+}
+
+// NewSpeechStreamTranscriber membuat instance baru dari SpeechStreamTranscriber.
+func NewSpeechStreamTranscriber(client *speech.Client) *SpeechStreamTranscriber {
+	return &SpeechStreamTranscriber{client: client}
+}
+
+// Start membuka stream pertama dan mengirim konfigurasi pengenalan.
+func (t *SpeechStreamTranscriber) Start(ctx context.Context, cfg StreamConfig) (TranscriptStream, error) {
+	encoding, err := streamEncoding(cfg.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	s := &speechStream{
+		ctx:    ctx,
+		client: t.client,
+		config: &speechpb.StreamingRecognitionConfig{
+			Config: &speechpb.RecognitionConfig{
+				Encoding:                   encoding,
+				SampleRateHertz:            cfg.SampleRateHertz,
+				LanguageCode:               cfg.LanguageCode,
+				EnableAutomaticPunctuation: true,
+				SpeechContexts:             cfg.Vocabulary.speechContexts(),
+			},
+			InterimResults: true,
+		},
+		segments: make(chan StreamSegment, 32),
+		errs:     make(chan error, 1),
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func streamEncoding(name string) (speechpb.RecognitionConfig_AudioEncoding, error) {
+	switch strings.ToLower(name) {
+	case "", "webm_opus":
+		return speechpb.RecognitionConfig_WEBM_OPUS, nil
+	case "ogg_opus":
+		return speechpb.RecognitionConfig_OGG_OPUS, nil
+	case "linear16":
+		return speechpb.RecognitionConfig_LINEAR16, nil
+	}
+	return 0, fmt.Errorf("encoding streaming tidak didukung: %q", name)
+}
+
+// speechStream membungkus beberapa StreamingRecognize berurutan sebagai satu
+// stream. Offset segmen dihitung dari awal sesi, bukan dari awal stream.
+type speechStream struct {
+	ctx    context.Context
+	client *speech.Client
+	config *speechpb.StreamingRecognitionConfig
+
+	mu      sync.Mutex
+	current speechpb.Speech_StreamingRecognizeClient
+	// header adalah potongan audio pertama. Untuk WebM/Ogg potongan ini
+	// berisi header container yang harus dikirim ulang ke stream baru.
+	header  []byte
+	started time.Time
+	base    time.Duration
+	closed  bool
+
+	readers  sync.WaitGroup
+	segments chan StreamSegment
+	errs     chan error
+}
+
+// open membuka stream baru; dipanggil dengan mu terkunci (atau saat Start).
+func (s *speechStream) open() error {
+	stream, err := s.client.StreamingRecognize(s.ctx)
+	if err != nil {
+		return fmt.Errorf("gagal membuka StreamingRecognize: %w", err)
+	}
+	err = stream.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_StreamingConfig{StreamingConfig: s.config},
+	})
+	if err != nil {
+		return fmt.Errorf("gagal mengirim konfigurasi streaming: %w", err)
+	}
+	if s.current != nil {
+		s.base += time.Since(s.started)
+	}
+	s.current = stream
+	s.started = time.Now()
+
+	s.readers.Add(1)
+	go s.read(stream, s.base)
+	return nil
+}
+
+func (s *speechStream) read(stream speechpb.Speech_StreamingRecognizeClient, base time.Duration) {
+	defer s.readers.Done()
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			select {
+			case s.errs <- fmt.Errorf("streaming transkripsi gagal: %w", err):
+			default:
+			}
+			return
+		}
+		for _, result := range resp.Results {
+			if len(result.Alternatives) == 0 {
+				continue
+			}
+			seg := StreamSegment{
+				Text:   strings.TrimSpace(result.Alternatives[0].Transcript),
+				Final:  result.IsFinal,
+				Offset: base + result.GetResultEndTime().AsDuration(),
+			}
+			select {
+			case s.segments <- seg:
+			case <-s.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (s *speechStream) Send(audio []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errors.New("stream sudah ditutup")
+	}
+	if time.Since(s.started) > maxStreamDuration {
+		if err := s.current.CloseSend(); err != nil {
+			return err
+		}
+		if err := s.open(); err != nil {
+			return err
+		}
+		if err := s.sendAudio(s.header); err != nil {
+			return err
+		}
+	}
+	if s.header == nil {
+		s.header = audio
+	}
+	return s.sendAudio(audio)
+}
+
+func (s *speechStream) sendAudio(audio []byte) error {
+	return s.current.Send(&speechpb.StreamingRecognizeRequest{
+		StreamingRequest: &speechpb.StreamingRecognizeRequest_AudioContent{AudioContent: audio},
+	})
+}
+
+func (s *speechStream) CloseSend() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	err := s.current.CloseSend()
+	go func() {
+		s.readers.Wait()
+		close(s.segments)
+	}()
+	return err
+}
+
+func (s *speechStream) Recv() (StreamSegment, error) {
+	select {
+	case err := <-s.errs:
+		return StreamSegment{}, err
+	case seg, ok := <-s.segments:
+		if !ok {
+			return StreamSegment{}, io.EOF
+		}
+		return seg, nil
+	}
+}
@@ -0,0 +1,450 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"summarize-me-api/internal/store"
+	"sync"
+	"time"
+)
+
+// ErrInvalidRating dikembalikan jika input rating tidak valid.
+var ErrInvalidRating = errors.New("rating tidak valid")
+
+// ErrInvalidSummaryUpdate dikembalikan jika perubahan ringkasan tidak valid.
+var ErrInvalidSummaryUpdate = errors.New("perubahan ringkasan tidak valid")
+
+// ErrVariantNotFound dikembalikan jika varian ringkasan tidak ada.
+var ErrVariantNotFound = errors.New("varian ringkasan tidak ditemukan")
+
+// ErrTooManyVariants dikembalikan jika batas varian per ringkasan tercapai.
+var ErrTooManyVariants = errors.New("jumlah varian ringkasan sudah maksimal")
+
+const (
+	maxTags      = 20
+	maxTagLength = 40
+	// maxVariants membatasi varian per ringkasan, termasuk versi asli.
+	maxVariants = 20
+	// OriginalVariantID adalah ID varian untuk ringkasan hasil job aslinya.
+	OriginalVariantID = "original"
+)
+
+// Thumb adalah penilaian singkat jempol atas/bawah.
+type Thumb string
+
+const (
+	ThumbUp   Thumb = "up"
+	ThumbDown Thumb = "down"
+)
+
+// Tag masalah yang bisa dilaporkan pada sebuah ringkasan.
+const (
+	IssueMissingActionItems = "missing-action-items"
+	IssueWrongSpeaker       = "wrong-speaker"
+	IssueHallucination      = "hallucination"
+	IssueOther              = "other"
+)
+
+// AllIssues adalah daftar tag masalah yang valid.
+var AllIssues = []string{IssueMissingActionItems, IssueWrongSpeaker, IssueHallucination, IssueOther}
+
+// Summary adalah metadata satu ringkasan yang dihasilkan server, beserta
+// isi ringkasannya (Markdown) untuk keperluan ekspor.
+type Summary struct {
+	ID       string `json:"id"`
+	JobID    string `json:"jobId"`
+	UserID   string `json:"userId"`
+	FileName string `json:"fileName"`
+	// Model adalah model yang menghasilkan ringkasan; FallbackFrom model
+	// awal yang gagal jika ringkasan dibuat oleh model fallback.
+	Model         string `json:"model"`
+	FallbackFrom  string `json:"fallbackFrom,omitempty"`
+	PromptVersion string `json:"promptVersion"`
+	LanguageCode  string `json:"languageCode"`
+	Text          string `json:"text,omitempty"`
+	// Transcript disimpan agar ringkasan bisa ditanyai lewat QAService.
+	Transcript string `json:"transcript,omitempty"`
+	// Tags adalah label bebas dari user (huruf kecil), untuk filter pencarian.
+	Tags []string `json:"tags,omitempty"`
+	// Keyframes adalah timestamp video (detik), lihat SummarizeResult.Keyframes.
+	Keyframes []float64 `json:"keyframes,omitempty"`
+	// ContentHash adalah SHA-256 audio asal, untuk deduplikasi upload ulang.
+	ContentHash string `json:"contentHash,omitempty"`
+	// VocabularyHash adalah sidik jari glosarium yang dipakai saat audio
+	// ditranskrip; transkrip hanya dipakai ulang dengan glosarium yang sama.
+	VocabularyHash string `json:"vocabularyHash,omitempty"`
+	// TranscriptFrom adalah ID ringkasan asal transkrip jika dipakai ulang.
+	TranscriptFrom string `json:"transcriptFrom,omitempty"`
+	// Variants berisi semua versi ringkasan yang dibuat ulang dari transkrip
+	// yang sama. Text, Model dan PromptVersion mengikuti varian aktif.
+	Variants      []SummaryVariant `json:"variants,omitempty"`
+	ActiveVariant string           `json:"activeVariant,omitempty"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     *time.Time       `json:"updatedAt,omitempty"`
+}
+
+// SummaryVariant adalah satu versi ringkasan dengan opsi peringkasannya.
+// SummaryOptions.Model berisi model yang benar-benar dipakai.
+type SummaryVariant struct {
+	ID string `json:"id"`
+	SummaryOptions
+	FallbackFrom  string    `json:"fallbackFrom,omitempty"`
+	PromptVersion string    `json:"promptVersion"`
+	Text          string    `json:"text"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// Rating adalah penilaian user terhadap satu ringkasan. Model, versi prompt
+// dan bahasa disalin dari Summary agar agregasi tidak perlu join.
+type Rating struct {
+	ID            string    `json:"id"`
+	SummaryID     string    `json:"summaryId"`
+	UserID        string    `json:"userId"`
+	Thumb         Thumb     `json:"thumb,omitempty"`
+	Score         int       `json:"score,omitempty"`
+	Issues        []string  `json:"issues,omitempty"`
+	Comment       string    `json:"comment,omitempty"`
+	Model         string    `json:"model"`
+	PromptVersion string    `json:"promptVersion"`
+	LanguageCode  string    `json:"languageCode"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// RatingInput adalah data rating dari user.
+type RatingInput struct {
+	Thumb   Thumb
+	Score   int
+	Issues  []string
+	Comment string
+}
+
+// SummaryUpdate adalah perubahan ringkasan dari user. Field nil tidak diubah.
+type SummaryUpdate struct {
+	Text *string
+	Tags *[]string
+}
+
+// SummaryListener dipanggil setiap kali ringkasan dibuat atau diubah.
+type SummaryListener func(summary Summary)
+
+// SummaryService menyimpan metadata ringkasan dan rating-nya.
+type SummaryService struct {
+	summaries *store.Collection[Summary]
+	ratings   *store.Collection[Rating]
+
+	mu        sync.RWMutex
+	listeners []SummaryListener
+}
+
+// NewSummaryService membuat instance baru dari SummaryService.
+func NewSummaryService(summaries *store.Collection[Summary], ratings *store.Collection[Rating]) *SummaryService {
+	return &SummaryService{summaries: summaries, ratings: ratings}
+}
+
+// CreateSummary mencatat ringkasan baru dari hasil sebuah job.
+func (s *SummaryService) CreateSummary(job Job, result *SummarizeResult) (Summary, error) {
+	summary := Summary{
+		ID:             store.NewID(),
+		JobID:          job.ID,
+		UserID:         job.UserID,
+		FileName:       job.FileName,
+		Model:          result.Model,
+		FallbackFrom:   result.FallbackFrom,
+		PromptVersion:  result.PromptVersion,
+		LanguageCode:   result.LanguageCode,
+		Text:           result.Summary,
+		Transcript:     result.Transcript,
+		Keyframes:      result.Keyframes,
+		ContentHash:    result.ContentHash,
+		VocabularyHash: result.VocabularyHash,
+		TranscriptFrom: result.TranscriptFrom,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.summaries.Put(summary.ID, summary); err != nil {
+		return summary, err
+	}
+	s.notify(summary)
+	return summary, nil
+}
+
+// Subscribe mendaftarkan listener untuk ringkasan yang dibuat atau diubah.
+func (s *SummaryService) Subscribe(listener SummaryListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *SummaryService) notify(summary Summary) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, listener := range s.listeners {
+		go listener(summary)
+	}
+}
+
+// UpdateSummary mengubah isi dan/atau tag ringkasan milik user.
+func (s *SummaryService) UpdateSummary(userID, summaryID string, update SummaryUpdate) (Summary, error) {
+	var tags []string
+	if update.Tags != nil {
+		var err error
+		if tags, err = normalizeTags(*update.Tags); err != nil {
+			return Summary{}, err
+		}
+	}
+	if update.Text != nil && strings.TrimSpace(*update.Text) == "" {
+		return Summary{}, fmt.Errorf("%w: text tidak boleh kosong", ErrInvalidSummaryUpdate)
+	}
+	if _, err := s.GetSummary(userID, summaryID); err != nil {
+		return Summary{}, err
+	}
+
+	summary, err := s.summaries.Update(summaryID, func(sum *Summary) error {
+		if update.Text != nil {
+			sum.Text = *update.Text
+		}
+		if update.Tags != nil {
+			sum.Tags = tags
+		}
+		now := time.Now()
+		sum.UpdatedAt = &now
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	s.notify(summary)
+	return summary, nil
+}
+
+// AddVariant menyimpan varian baru untuk ringkasan milik user. Pada varian
+// pertama, ringkasan asli ikut dicatat sebagai varian "original" agar bisa
+// dipilih kembali. Jika activate, varian baru langsung menjadi isi ringkasan.
+func (s *SummaryService) AddVariant(userID, summaryID string, variant SummaryVariant, activate bool) (Summary, error) {
+	if _, err := s.GetSummary(userID, summaryID); err != nil {
+		return Summary{}, err
+	}
+	summary, err := s.summaries.Update(summaryID, func(sum *Summary) error {
+		if len(sum.Variants) == 0 {
+			sum.Variants = append(sum.Variants, SummaryVariant{
+				ID:             OriginalVariantID,
+				SummaryOptions: SummaryOptions{Model: sum.Model},
+				FallbackFrom:   sum.FallbackFrom,
+				PromptVersion:  sum.PromptVersion,
+				Text:           sum.Text,
+				CreatedAt:      sum.CreatedAt,
+			})
+			sum.ActiveVariant = OriginalVariantID
+		}
+		if len(sum.Variants) >= maxVariants {
+			return fmt.Errorf("%w (%d)", ErrTooManyVariants, maxVariants)
+		}
+		sum.Variants = append(sum.Variants, variant)
+		if activate {
+			sum.applyVariant(variant)
+		}
+		return nil
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	if activate {
+		s.notify(summary)
+	}
+	return summary, nil
+}
+
+// ActivateVariant menjadikan varian sebagai isi ringkasan.
+func (s *SummaryService) ActivateVariant(userID, summaryID, variantID string) (Summary, error) {
+	if _, err := s.GetSummary(userID, summaryID); err != nil {
+		return Summary{}, err
+	}
+	summary, err := s.summaries.Update(summaryID, func(sum *Summary) error {
+		for _, v := range sum.Variants {
+			if v.ID == variantID {
+				sum.applyVariant(v)
+				return nil
+			}
+		}
+		return ErrVariantNotFound
+	})
+	if err != nil {
+		return Summary{}, err
+	}
+	s.notify(summary)
+	return summary, nil
+}
+
+func (sum *Summary) applyVariant(v SummaryVariant) {
+	sum.Text = v.Text
+	sum.Model = v.Model
+	sum.FallbackFrom = v.FallbackFrom
+	sum.PromptVersion = v.PromptVersion
+	sum.ActiveVariant = v.ID
+	now := time.Now()
+	sum.UpdatedAt = &now
+}
+
+// normalizeTags merapikan tag menjadi huruf kecil tanpa duplikat.
+func normalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag maksimal %d karakter", ErrInvalidSummaryUpdate, maxTagLength)
+		}
+		out = append(out, tag)
+	}
+	out = dedupe(out)
+	if len(out) > maxTags {
+		return nil, fmt.Errorf("%w: maksimal %d tag", ErrInvalidSummaryUpdate, maxTags)
+	}
+	return out, nil
+}
+
+// GetSummary mengambil ringkasan milik user. Ringkasan milik user lain
+// diperlakukan sebagai tidak ditemukan.
+func (s *SummaryService) GetSummary(userID, summaryID string) (Summary, error) {
+	summary, err := s.summaries.Get(summaryID)
+	if err != nil {
+		return Summary{}, err
+	}
+	if summary.UserID != userID {
+		return Summary{}, store.ErrNotFound
+	}
+	return summary, nil
+}
+
+// FindByContentHash mencari ringkasan terbaru milik user dari audio dengan
+// hash yang sama, ditranskrip dengan glosarium vocabHash, dan transkrip yang
+// tersimpan. Pencarian dibatasi per user agar transkrip tidak bocor antar
+// akun.
+func (s *SummaryService) FindByContentHash(userID, hash, vocabHash string) (Summary, bool) {
+	if hash == "" {
+		return Summary{}, false
+	}
+	matches := s.summaries.List(func(sum Summary) bool {
+		return sum.UserID == userID && sum.ContentHash == hash && sum.VocabularyHash == vocabHash && strings.TrimSpace(sum.Transcript) != ""
+	})
+	if len(matches) == 0 {
+		return Summary{}, false
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].CreatedAt.After(matches[j].CreatedAt) })
+	return matches[0], true
+}
+
+// ListSummaries mengembalikan semua ringkasan yang lolos filter, terlama
+// lebih dulu.
+func (s *SummaryService) ListSummaries(filter func(Summary) bool) []Summary {
+	summaries := s.summaries.List(filter)
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].CreatedAt.Before(summaries[j].CreatedAt) })
+	return summaries
+}
+
+// RateSummary menyimpan rating user untuk ringkasan. Rating ulang dari user
+// yang sama menimpa rating sebelumnya.
+func (s *SummaryService) RateSummary(userID, summaryID string, input RatingInput) (Rating, error) {
+	if err := validateRating(input); err != nil {
+		return Rating{}, err
+	}
+	summary, err := s.GetSummary(userID, summaryID)
+	if err != nil {
+		return Rating{}, err
+	}
+
+	now := time.Now()
+	id := summaryID + ":" + userID
+	rating, err := s.ratings.Get(id)
+	if errors.Is(err, store.ErrNotFound) {
+		rating = Rating{ID: id, SummaryID: summaryID, UserID: userID, CreatedAt: now}
+	} else if err != nil {
+		return Rating{}, err
+	}
+	rating.Thumb = input.Thumb
+	rating.Score = input.Score
+	rating.Issues = dedupe(input.Issues)
+	rating.Comment = input.Comment
+	rating.Model = summary.Model
+	rating.PromptVersion = summary.PromptVersion
+	rating.LanguageCode = summary.LanguageCode
+	rating.UpdatedAt = now
+
+	return rating, s.ratings.Put(id, rating)
+}
+
+func validateRating(input RatingInput) error {
+	if input.Thumb == "" && input.Score == 0 && len(input.Issues) == 0 {
+		return fmt.Errorf("%w: isi minimal thumb, score atau issues", ErrInvalidRating)
+	}
+	if input.Thumb != "" && input.Thumb != ThumbUp && input.Thumb != ThumbDown {
+		return fmt.Errorf("%w: thumb harus 'up' atau 'down'", ErrInvalidRating)
+	}
+	if input.Score != 0 && (input.Score < 1 || input.Score > 5) {
+		return fmt.Errorf("%w: score harus 1 sampai 5", ErrInvalidRating)
+	}
+	for _, issue := range input.Issues {
+		if !contains(AllIssues, issue) {
+			return fmt.Errorf("%w: issue tidak dikenal: %s", ErrInvalidRating, issue)
+		}
+	}
+	return nil
+}
+
+// RatingStats adalah agregat rating untuk satu kombinasi prompt/model/bahasa.
+type RatingStats struct {
+	PromptVersion string         `json:"promptVersion"`
+	Model         string         `json:"model"`
+	LanguageCode  string         `json:"languageCode"`
+	Ratings       int            `json:"ratings"`
+	ThumbsUp      int            `json:"thumbsUp"`
+	ThumbsDown    int            `json:"thumbsDown"`
+	AverageScore  float64        `json:"averageScore"`
+	Issues        map[string]int `json:"issues"`
+}
+
+// RatingStats mengelompokkan rating per versi prompt, model dan bahasa.
+func (s *SummaryService) RatingStats(since time.Time) []RatingStats {
+	groups := make(map[string]*RatingStats)
+	scoreSum := make(map[string]int)
+	scoreCount := make(map[string]int)
+
+	for _, r := range s.ratings.List(func(r Rating) bool { return !r.UpdatedAt.Before(since) }) {
+		key := r.PromptVersion + "|" + r.Model + "|" + r.LanguageCode
+		g, ok := groups[key]
+		if !ok {
+			g = &RatingStats{PromptVersion: r.PromptVersion, Model: r.Model, LanguageCode: r.LanguageCode, Issues: map[string]int{}}
+			groups[key] = g
+		}
+		g.Ratings++
+		switch r.Thumb {
+		case ThumbUp:
+			g.ThumbsUp++
+		case ThumbDown:
+			g.ThumbsDown++
+		}
+		if r.Score > 0 {
+			scoreSum[key] += r.Score
+			scoreCount[key]++
+		}
+		for _, issue := range r.Issues {
+			g.Issues[issue]++
+		}
+	}
+
+	out := make([]RatingStats, 0, len(groups))
+	for key, g := range groups {
+		if scoreCount[key] > 0 {
+			g.AverageScore = float64(scoreSum[key]) / float64(scoreCount[key])
+		}
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].PromptVersion != out[j].PromptVersion {
+			return out[i].PromptVersion < out[j].PromptVersion
+		}
+		return out[i].Model < out[j].Model
+	})
+	return out
+}
@@ -2,10 +2,15 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"summarize-me-api/internal/media"
+	"summarize-me-api/internal/resilience"
+	"summarize-me-api/internal/transcript"
 	"time"
 
 	speech "cloud.google.com/go/speech/apiv1"
@@ -14,25 +19,81 @@ import (
 	"github.com/google/generative-ai-go/genai"
 )
 
+// PromptVersion menandai versi template prompt peringkasan. Naikkan setiap
+// kali prompt di summaryPrompt diubah agar rating bisa dibandingkan. Versi
+// yang dicatat di hasil juga memuat opsi peringkasan, lihat promptVersion.
+const PromptVersion = "v1"
+
+// SummarizeResult adalah hasil transkripsi dan peringkasan, beserta
+// parameter yang dipakai untuk menghasilkannya.
+type SummarizeResult struct {
+	Transcript string `json:"transcript"`
+	Summary    string `json:"summary"`
+	// Model adalah model yang benar-benar menghasilkan ringkasan.
+	// FallbackFrom diisi model pertama yang dicoba jika terjadi fallback.
+	Model         string `json:"model"`
+	FallbackFrom  string `json:"fallbackFrom,omitempty"`
+	PromptVersion string `json:"promptVersion"`
+	LanguageCode  string `json:"languageCode"`
+	// Keyframes berisi timestamp keyframe (detik) jika input berupa video.
+	// Daftar ini belum dipetakan ke bagian ringkasan: transkrip Speech tidak
+	// membawa offset waktu per kata, sehingga klien hanya bisa menawarkan
+	// titik lompat ke video, bukan tautan per bagian.
+	Keyframes []float64 `json:"keyframes,omitempty"`
+	// ContentHash, VocabularyHash dan TranscriptFrom diisi JobRunner, lihat
+	// Job dan Summary.
+	ContentHash    string `json:"contentHash,omitempty"`
+	VocabularyHash string `json:"vocabularyHash,omitempty"`
+	TranscriptFrom string `json:"transcriptFrom,omitempty"`
+	// Usage adalah pemakaian provider untuk menghasilkan ringkasan ini,
+	// termasuk percobaan ulang model yang akhirnya berhasil.
+	Usage Usage `json:"usage"`
+	// FailedUsage adalah token model yang gagal sebelum fallback.
+	FailedUsage []ModelUsage `json:"failedUsage,omitempty"`
+}
+
 type SummarizeService struct {
-	speechClient  *speech.Client
-	geminiModel   *genai.GenerativeModel
-	storageClient *storage.Client
-	bucketName    string
+	speechClient    *speech.Client
+	geminiModel     *genai.GenerativeModel
+	modelName       string
+	storageClient   *storage.Client
+	bucketName      string
+	languageCode    string
+	sampleRateHertz int32
+	transcoder      *media.Transcoder
+	// models berisi model selain geminiModel: yang boleh dipilih user
+	// (allowed), model fallback dan tujuan routing.
+	models    map[string]*genai.GenerativeModel
+	allowed   map[string]bool
+	fallbacks []string
+	routes    []ModelRoute
+	// gemini, speech dan storage menjalankan pemanggilan provider dengan
+	// retry dan circuit breaker, lihat UseResilience.
+	gemini  func(model string) *resilience.Executor
+	speech  *resilience.Executor
+	storage *resilience.Executor
 }
 
 // NewSummarizeService membuat instance baru dari SummarizeService.
 func NewSummarizeService(
 	speechClient *speech.Client,
 	geminiModel *genai.GenerativeModel,
+	modelName string,
 	storageClient *storage.Client,
 	bucketName string,
+	languageCode string,
+	sampleRateHertz int32,
+	transcoder *media.Transcoder,
 ) *SummarizeService {
 	return &SummarizeService{
-		speechClient:  speechClient,
-		geminiModel:   geminiModel,
-		storageClient: storageClient,
-		bucketName:    bucketName,
+		speechClient:    speechClient,
+		geminiModel:     geminiModel,
+		modelName:       modelName,
+		storageClient:   storageClient,
+		bucketName:      bucketName,
+		languageCode:    languageCode,
+		sampleRateHertz: sampleRateHertz,
+		transcoder:      transcoder,
 	}
 }
 
@@ -40,15 +101,20 @@ func NewSummarizeService(
 func (s *SummarizeService) uploadToGCS(ctx context.Context, fileData []byte, fileName string) (string, error) {
 	objectName := fmt.Sprintf("uploads/%d-%s", time.Now().UnixNano(), fileName)
 
-	uploadCtx, cancel := context.WithTimeout(ctx, time.Second*60)
-	defer cancel()
-
-	wc := s.storageClient.Bucket(s.bucketName).Object(objectName).NewWriter(uploadCtx)
-	if _, err := wc.Write(fileData); err != nil {
-		return "", fmt.Errorf("gagal menulis data ke GCS: %w", err)
-	}
-	if err := wc.Close(); err != nil {
-		return "", fmt.Errorf("gagal menutup GCS writer: %w", err)
+	// Timeout per percobaan diatur executor storage (resilience.Policy).
+	err := s.storage.Do(ctx, func(ctx context.Context) error {
+		wc := s.storageClient.Bucket(s.bucketName).Object(objectName).NewWriter(ctx)
+		if _, err := wc.Write(fileData); err != nil {
+			wc.Close()
+			return fmt.Errorf("gagal menulis data ke GCS: %w", err)
+		}
+		if err := wc.Close(); err != nil {
+			return fmt.Errorf("gagal menutup GCS writer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
 	gcsURI := fmt.Sprintf("gs://%s/%s", s.bucketName, objectName)
@@ -56,6 +122,9 @@ func (s *SummarizeService) uploadToGCS(ctx context.Context, fileData []byte, fil
 	return gcsURI, nil
 }
 
+// AudioExtensions adalah ekstensi file audio yang dikenali getAudioEncoding.
+var AudioExtensions = []string{".mp3", ".m4a", ".wav", ".flac", ".ogg"}
+
 // ✅ TAMBAHKAN HELPER FUNCTION INI
 // getAudioEncoding mendeteksi format encoding berdasarkan nama file
 func getAudioEncoding(fileName string) speechpb.RecognitionConfig_AudioEncoding {
@@ -83,13 +152,45 @@ func getAudioEncoding(fileName string) speechpb.RecognitionConfig_AudioEncoding
 }
 
 // TranscribeAndSummarize melakukan transkripsi dan peringkasan audio.
-func (s *SummarizeService) TranscribeAndSummarize(ctx context.Context, fileData []byte, fileName string) (map[string]string, error) {
+// File transkrip (txt, vtt, srt, docx) langsung diringkas tanpa Speech-to-Text.
+func (s *SummarizeService) TranscribeAndSummarize(ctx context.Context, fileData []byte, fileName string) (*SummarizeResult, error) {
+	return s.TranscribeAndSummarizeWith(ctx, fileData, fileName, nil)
+}
+
+// TranscribeAndSummarizeWith seperti TranscribeAndSummarize, dengan glosarium
+// vocab sebagai frasa Speech-to-Text dan acuan ejaan di ringkasan.
+func (s *SummarizeService) TranscribeAndSummarizeWith(ctx context.Context, fileData []byte, fileName string, vocab *Vocabulary) (*SummarizeResult, error) {
+	if transcript.IsTranscriptFile(fileName) {
+		text, err := transcript.Parse(fileData, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca transkrip %s: %w", fileName, err)
+		}
+		log.Printf("Input %s berupa transkrip, Speech-to-Text dilewati", fileName)
+		return s.SummarizeTranscriptWith(ctx, text, vocab)
+	}
+
+	// 0. Video: ambil track audionya dulu (FLAC mono)
+	var keyframes []float64
+	if media.IsVideo(fileName) {
+		if s.transcoder == nil {
+			return nil, fmt.Errorf("file video tidak didukung: ffmpeg tidak dikonfigurasi")
+		}
+		extracted, err := s.transcoder.ExtractAudio(ctx, fileData, fileName)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Audio diekstrak dari video %s (%d bytes, %d keyframe)", fileName, len(extracted.Audio), len(extracted.Keyframes))
+		fileData = extracted.Audio
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".flac"
+		keyframes = extracted.Keyframes
+	}
 
 	// 1. Upload file ke GCS dulu
 	gcsURI, err := s.uploadToGCS(ctx, fileData, fileName)
 	if err != nil {
 		return nil, fmt.Errorf("gagal upload ke GCS: %w", err)
 	}
+	usage := Usage{StorageBytes: int64(len(fileData))}
 
 	// 2. Jadwalkan penghapusan file dari GCS setelah selesai
 	defer func() {
@@ -106,36 +207,74 @@ func (s *SummarizeService) TranscribeAndSummarize(ctx context.Context, fileData
 
 	// 3. ✅ DETEKSI FORMAT AUDIO DAN PANGGIL TRANSKRIP
 	encoding := getAudioEncoding(fileName)
-	transcript, err := s.transcribeAudioAsync(ctx, gcsURI, encoding)
+	transcript, billed, err := s.transcribeAudioAsync(ctx, gcsURI, encoding, vocab)
+	usage.AudioSeconds = billed.Seconds()
 	if err != nil {
-		return nil, fmt.Errorf("gagal mentranskrip audio (async): %w", err)
+		return nil, withUsage(fmt.Errorf("gagal mentranskrip audio (async): %w", err), usage, nil)
 	}
 
 	// 4. Peringkasan
-	summary, err := s.summarizeText(ctx, transcript)
+	summary, err := s.summarizeText(ctx, transcript, vocab)
 	if err != nil {
-		return nil, fmt.Errorf("gagal membuat ringkasan: %w", err)
+		return nil, withUsage(fmt.Errorf("gagal membuat ringkasan: %w", err), usage, summary.Failed)
 	}
 
 	// 5. Kembalikan hasil
-	result := map[string]string{
-		"transcript": transcript,
-		"summary":    summary,
+	result := &SummarizeResult{
+		Transcript:    transcript,
+		Summary:       summary.Text,
+		Model:         summary.Model,
+		FallbackFrom:  summary.FallbackFrom,
+		PromptVersion: promptVersion(s.normalizeOptions(SummaryOptions{}), vocab),
+		LanguageCode:  s.languageCode,
+		Keyframes:     keyframes,
+		Usage: Usage{
+			AudioSeconds: usage.AudioSeconds,
+			InputTokens:  summary.Usage.InputTokens,
+			OutputTokens: summary.Usage.OutputTokens,
+			StorageBytes: usage.StorageBytes,
+		},
+		FailedUsage: summary.Failed,
 	}
 	return result, nil
 }
 
+// SummarizeTranscript meringkas transkrip yang sudah ada.
+func (s *SummarizeService) SummarizeTranscript(ctx context.Context, text string) (*SummarizeResult, error) {
+	return s.SummarizeTranscriptWith(ctx, text, nil)
+}
+
+// SummarizeTranscriptWith meringkas transkrip yang sudah ada dengan ejaan
+// istilah dari glosarium vocab.
+func (s *SummarizeService) SummarizeTranscriptWith(ctx context.Context, text string, vocab *Vocabulary) (*SummarizeResult, error) {
+	summary, err := s.summarizeText(ctx, text, vocab)
+	if err != nil {
+		return nil, withUsage(fmt.Errorf("gagal membuat ringkasan: %w", err), Usage{}, summary.Failed)
+	}
+	return &SummarizeResult{
+		Transcript:    text,
+		Summary:       summary.Text,
+		Model:         summary.Model,
+		FallbackFrom:  summary.FallbackFrom,
+		PromptVersion: promptVersion(s.normalizeOptions(SummaryOptions{}), vocab),
+		LanguageCode:  s.languageCode,
+		Usage:         summary.Usage,
+		FailedUsage:   summary.Failed,
+	}, nil
+}
+
 // ✅ UBAH SIGNATURE FUNGSI INI UNTUK MENERIMA ENCODING
 // transcribeAudioAsync menggantikan transcribeAudio lama
-func (s *SummarizeService) transcribeAudioAsync(ctx context.Context, gcsURI string, encoding speechpb.RecognitionConfig_AudioEncoding) (string, error) {
+func (s *SummarizeService) transcribeAudioAsync(ctx context.Context, gcsURI string, encoding speechpb.RecognitionConfig_AudioEncoding, vocab *Vocabulary) (string, time.Duration, error) {
 	log.Println("Mengirim audio ke Google Speech-to-Text API (Asynchronous)...")
 
 	// ✅ GUNAKAN ENCODING YANG TERDETEKSI
 	config := &speechpb.RecognitionConfig{
-		LanguageCode:               "id-ID",
+		LanguageCode:               s.languageCode,
 		EnableAutomaticPunctuation: true,
-		Encoding:                   encoding,      // ← GUNAKAN PARAMETER ENCODING
-		SampleRateHertz:            16000,         // ← TAMBAH SAMPLE RATE
+		Encoding:                   encoding,               // ← GUNAKAN PARAMETER ENCODING
+		SampleRateHertz:            s.sampleRateHertz,      // ← 0 berarti dideteksi otomatis dari header file
+		SpeechContexts:             vocab.speechContexts(), // ← frasa glosarium agar istilah lebih mudah dikenali
 	}
 
 	req := &speechpb.LongRunningRecognizeRequest{
@@ -145,16 +284,28 @@ func (s *SummarizeService) transcribeAudioAsync(ctx context.Context, gcsURI stri
 		},
 	}
 
-	op, err := s.speechClient.LongRunningRecognize(ctx, req)
+	// Hanya permintaan memulai operasi yang dicoba ulang lewat executor,
+	// agar audio tidak ditranskrip (dan ditagih) lebih dari sekali.
+	var op *speech.LongRunningRecognizeOperation
+	err := s.speech.Do(ctx, func(ctx context.Context) error {
+		var err error
+		op, err = s.speechClient.LongRunningRecognize(ctx, req)
+		if err != nil {
+			return fmt.Errorf("gagal memulai LongRunningRecognize: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("gagal memulai LongRunningRecognize: %w", err)
+		return "", 0, err
 	}
 
-	log.Println("Menunggu proses transkripsi asinkron selesai...")
-	resp, err := op.Wait(ctx)
+	log.Printf("Menunggu proses transkripsi asinkron %s selesai...", op.Name())
+	resp, err := s.waitTranscription(ctx, op)
 	if err != nil {
-		return "", fmt.Errorf("gagal menunggu operasi transkripsi: %w", err)
+		return "", 0, err
 	}
+	// Durasi yang ditagih Speech (dibulatkan ke atas per 15 detik).
+	billed := resp.GetTotalBilledTime().AsDuration()
 
 	// Proses hasil
 	var transcriptWithSpeakers strings.Builder
@@ -165,7 +316,7 @@ func (s *SummarizeService) transcribeAudioAsync(ctx context.Context, gcsURI stri
 		if len(result.Alternatives) == 0 || len(result.Alternatives[0].Words) == 0 {
 			continue
 		}
-		
+
 		hasDiarizationWords = true
 		for _, wordInfo := range result.Alternatives[0].Words {
 			if wordInfo.SpeakerTag != currentSpeakerTag {
@@ -184,7 +335,7 @@ func (s *SummarizeService) transcribeAudioAsync(ctx context.Context, gcsURI stri
 
 	if hasDiarizationWords && transcriptWithSpeakers.Len() > 0 {
 		log.Println("Transkrip dengan pembicara berhasil dibuat (async).")
-		return transcriptWithSpeakers.String(), nil
+		return vocab.normalize(transcriptWithSpeakers.String()), billed, nil
 	}
 
 	log.Println("Diarization gagal atau tidak ada info kata, membuat transkrip biasa (async).")
@@ -196,38 +347,88 @@ func (s *SummarizeService) transcribeAudioAsync(ctx context.Context, gcsURI stri
 	}
 	finalTranscript := strings.TrimSpace(fallbackTranscript.String())
 	if finalTranscript == "" {
-		return "", fmt.Errorf("tidak ada teks yang terdeteksi di audio")
+		return "", 0, fmt.Errorf("tidak ada teks yang terdeteksi di audio")
 	}
-	return finalTranscript, nil
+	return vocab.normalize(finalTranscript), billed, nil
 }
 
-// summarizeText membuat ringkasan dari transkrip
-func (s *SummarizeService) summarizeText(ctx context.Context, textToSummarize string) (string, error) {
-	log.Println("Mengirim transkrip ke Gemini API untuk diringkas...")
+// waitTranscription menunggu operasi LongRunningRecognize selesai. Timeout
+// tahap speech (resilience.Policy.Timeout) berlaku untuk total penantian.
+// Jika polling terputus sebelum operasi selesai, operasi yang sama dipantau
+// ulang lewat namanya, bukan dimulai dari awal.
+func (s *SummarizeService) waitTranscription(ctx context.Context, op *speech.LongRunningRecognizeOperation) (*speechpb.LongRunningRecognizeResponse, error) {
+	if timeout := s.speech.Timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	name := op.Name()
+	const pollBackoff = 5 * time.Second
+	for {
+		resp, err := op.Wait(ctx)
+		switch {
+		case err == nil:
+			return resp, nil
+		case op.Done():
+			// Operasinya sendiri gagal di server; menunggu ulang percuma.
+			return nil, fmt.Errorf("operasi transkripsi %s gagal: %w", name, err)
+		case ctx.Err() != nil, !resilience.Retryable(err):
+			return nil, fmt.Errorf("gagal menunggu operasi transkripsi %s: %w", name, err)
+		}
 
-	prompt := fmt.Sprintf(`Tolong buatkan ringkasan, poin-poin penting, dan action items (jika ada) dari transkrip rapat berikut. Perhatikan label "Pembicara X:" untuk mengidentifikasi siapa yang berbicara. Jika memungkinkan, sebutkan pembicara (misalnya "[Pembicara 1]") saat merangkum poin penting atau action item. Gunakan format Markdown yang rapi dan informatif.
+		log.Printf("WARN: Polling operasi transkripsi %s terputus, dipantau ulang dalam %v: %v", name, pollBackoff, err)
+		timer := time.NewTimer(pollBackoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("gagal menunggu operasi transkripsi %s: %w", name, errors.Join(err, ctx.Err()))
+		case <-timer.C:
+		}
+		op = s.speechClient.LongRunningRecognizeOperation(name)
+	}
+}
 
-	TRANSKRIP:
-	"%s"
-	`, textToSummarize)
+// summarizeText membuat ringkasan dari transkrip dengan opsi bawaan
+func (s *SummarizeService) summarizeText(ctx context.Context, textToSummarize string, vocab *Vocabulary) (generated, error) {
+	opts := s.normalizeOptions(SummaryOptions{})
+	return s.generate(ctx, s.modelChain(textToSummarize, opts), summaryPrompt(textToSummarize, opts, vocab))
+}
 
-	resp, err := s.geminiModel.GenerateContent(ctx, genai.Text(prompt))
-	if err != nil {
-		return "", fmt.Errorf("gagal GenerateContent Gemini: %w", err)
+// generateWith mengirim prompt peringkasan ke satu model dan mengambil
+// teksnya. Usage dikembalikan juga saat gagal. Jika isi prompt di
+// summaryPrompt diubah, naikkan juga PromptVersion.
+func (s *SummarizeService) generateWith(ctx context.Context, name string, prompt string) (string, Usage, error) {
+	model := s.geminiModel
+	if name != s.modelName {
+		model = s.models[name]
 	}
-
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		if len(resp.Candidates) > 0 && resp.Candidates[0].FinishReason != genai.FinishReasonStop {
-			return "", fmt.Errorf("gagal mendapatkan respons AI: %s", resp.Candidates[0].FinishReason)
-		}
-		return "", fmt.Errorf("gagal mendapatkan respons dari AI (kandidat/parts kosong)")
+	// Model yang tidak didaftarkan lewat AllowModels atau UseModelChain
+	// tidak bisa dipanggil; percobaan ulang juga tidak akan menolong.
+	if model == nil {
+		return "", Usage{}, resilience.Permanent(fmt.Errorf("model %q tidak terdaftar", name))
 	}
+	log.Printf("Mengirim transkrip ke Gemini API (%s) untuk diringkas...", name)
 
-	part := resp.Candidates[0].Content.Parts[0]
-	if txt, ok := part.(genai.Text); ok {
-		log.Println("Ringkasan berhasil dibuat oleh Gemini.")
-		return string(txt), nil
+	var summary string
+	var usage Usage
+	err := s.geminiExecutor(name).Do(ctx, func(ctx context.Context) error {
+		resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+		if err != nil {
+			return geminiError(err)
+		}
+		// Token percobaan yang gagal (mis. respons kosong) tetap ditagih,
+		// jadi dijumlahkan untuk semua percobaan.
+		if resp.UsageMetadata != nil {
+			usage.InputTokens += int64(resp.UsageMetadata.PromptTokenCount)
+			usage.OutputTokens += int64(resp.UsageMetadata.CandidatesTokenCount)
+		}
+		summary, err = summaryText(resp)
+		return err
+	})
+	if err != nil {
+		return "", usage, err
 	}
 
-	return "", fmt.Errorf("respons AI bukan format teks yang diharapkan")
-}
\ No newline at end of file
+	log.Println("Ringkasan berhasil dibuat oleh Gemini.")
+	return summary, usage, nil
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+)
+
+// ErrInvalidSummaryOptions dikembalikan jika opsi peringkasan tidak dikenal.
+var ErrInvalidSummaryOptions = errors.New("opsi ringkasan tidak valid")
+
+// Template ringkasan yang bisa dipilih.
+const (
+	TemplateMeeting   = "rapat"
+	TemplateMinutes   = "notulen"
+	TemplateExecutive = "eksekutif"
+	TemplateBullets   = "poin"
+)
+
+// Panjang ringkasan yang bisa dipilih.
+const (
+	LengthShort  = "singkat"
+	LengthMedium = "sedang"
+	LengthLong   = "panjang"
+)
+
+// Bahasa keluaran ringkasan yang bisa dipilih.
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// SummaryOptions mengatur cara transkrip diringkas. Field kosong berarti
+// nilai bawaan: template rapat, panjang sedang dan bahasa Indonesia. Model
+// kosong berarti model dipilih otomatis oleh aturan routing, lihat
+// UseModelChain.
+type SummaryOptions struct {
+	Template string `json:"template"`
+	Length   string `json:"length"`
+	Language string `json:"language"`
+	Model    string `json:"model"`
+}
+
+// templateInstructions adalah instruksi pembuka prompt per template.
+var templateInstructions = map[string]string{
+	TemplateMeeting:   "Tolong buatkan ringkasan, poin-poin penting, dan action items (jika ada) dari transkrip rapat berikut.",
+	TemplateMinutes:   "Tolong buatkan notulen rapat resmi dari transkrip berikut, dengan bagian: peserta (berdasarkan label pembicara), agenda, pembahasan per agenda, keputusan, dan action items (jika ada).",
+	TemplateExecutive: "Tolong buatkan ringkasan eksekutif dari transkrip rapat berikut untuk pimpinan yang tidak hadir: mulai dengan kesimpulan utama, lalu keputusan, risiko atau hal yang perlu perhatian, dan action items (jika ada).",
+	TemplateBullets:   "Tolong ringkas transkrip rapat berikut hanya dalam bentuk daftar poin (bullet points) yang padat, diakhiri bagian action items (jika ada).",
+}
+
+var lengthInstructions = map[string]string{
+	LengthShort:  " Buat ringkasan sesingkat mungkin, maksimal sekitar 150 kata.",
+	LengthMedium: "",
+	LengthLong:   " Buat ringkasan yang rinci dan lengkap, jangan lewatkan detail pembahasan yang penting.",
+}
+
+var languageInstructions = map[string]string{
+	LanguageIndonesian: "",
+	LanguageEnglish:    " Tulis seluruh ringkasan dalam bahasa Inggris, termasuk judul bagian (gunakan judul \"Action Items\" untuk action item).",
+}
+
+// SummaryOptionsCatalog adalah daftar pilihan yang valid, untuk ditampilkan
+// di UI.
+type SummaryOptionsCatalog struct {
+	Templates []string `json:"templates"`
+	Lengths   []string `json:"lengths"`
+	Languages []string `json:"languages"`
+	Models    []string `json:"models"`
+	// Defaults.Model kosong karena model bawaan dipilih oleh routing.
+	Defaults SummaryOptions `json:"defaults"`
+}
+
+// AllowModels mengizinkan model Gemini lain dipilih lewat SummaryOptions.Model.
+// Model utama selalu diizinkan.
+func (s *SummarizeService) AllowModels(client *genai.Client, names []string) {
+	if s.allowed == nil {
+		s.allowed = make(map[string]bool)
+	}
+	for _, name := range names {
+		if s.addModel(client, name) {
+			s.allowed[name] = true
+		}
+	}
+}
+
+// addModel menyiapkan model selain model utama. Mengembalikan false untuk
+// nama kosong atau model utama.
+func (s *SummarizeService) addModel(client *genai.Client, name string) bool {
+	if name == "" || name == s.modelName {
+		return false
+	}
+	if s.models == nil {
+		s.models = make(map[string]*genai.GenerativeModel)
+	}
+	if _, ok := s.models[name]; !ok {
+		s.models[name] = client.GenerativeModel(name)
+	}
+	return true
+}
+
+// Options mengembalikan pilihan opsi peringkasan yang valid.
+func (s *SummarizeService) Options() SummaryOptionsCatalog {
+	models := []string{s.modelName}
+	for name := range s.allowed {
+		models = append(models, name)
+	}
+	sort.Strings(models[1:])
+	return SummaryOptionsCatalog{
+		Templates: []string{TemplateMeeting, TemplateMinutes, TemplateExecutive, TemplateBullets},
+		Lengths:   []string{LengthShort, LengthMedium, LengthLong},
+		Languages: []string{LanguageIndonesian, LanguageEnglish},
+		Models:    models,
+		Defaults:  s.normalizeOptions(SummaryOptions{}),
+	}
+}
+
+// SummarizeWithOptions meringkas transkrip yang sudah ada dengan template,
+// panjang, bahasa dan model pilihan, tanpa transkripsi ulang. Model yang
+// benar-benar dipakai (bisa berbeda karena fallback) dicatat di hasil.
+// vocab boleh nil.
+func (s *SummarizeService) SummarizeWithOptions(ctx context.Context, text string, opts SummaryOptions, vocab *Vocabulary) (*SummarizeResult, error) {
+	opts, err := s.ValidateOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := s.generate(ctx, s.modelChain(text, opts), summaryPrompt(text, opts, vocab))
+	if err != nil {
+		return nil, withUsage(fmt.Errorf("gagal membuat ringkasan: %w", err), Usage{}, summary.Failed)
+	}
+	return &SummarizeResult{
+		Transcript:    text,
+		Summary:       summary.Text,
+		Model:         summary.Model,
+		FallbackFrom:  summary.FallbackFrom,
+		PromptVersion: promptVersion(opts, vocab),
+		LanguageCode:  s.languageCode,
+		Usage:         summary.Usage,
+		FailedUsage:   summary.Failed,
+	}, nil
+}
+
+// ValidateOptions memeriksa opsi tanpa memanggil model.
+func (s *SummarizeService) ValidateOptions(opts SummaryOptions) (SummaryOptions, error) {
+	opts = s.normalizeOptions(opts)
+	if _, ok := templateInstructions[opts.Template]; !ok {
+		return opts, fmt.Errorf("%w: template %q tidak dikenal", ErrInvalidSummaryOptions, opts.Template)
+	}
+	if _, ok := lengthInstructions[opts.Length]; !ok {
+		return opts, fmt.Errorf("%w: length %q tidak dikenal", ErrInvalidSummaryOptions, opts.Length)
+	}
+	if _, ok := languageInstructions[opts.Language]; !ok {
+		return opts, fmt.Errorf("%w: language %q tidak dikenal", ErrInvalidSummaryOptions, opts.Language)
+	}
+	if opts.Model != "" && opts.Model != s.modelName && !s.allowed[opts.Model] {
+		return opts, fmt.Errorf("%w: model %q tidak diizinkan", ErrInvalidSummaryOptions, opts.Model)
+	}
+	return opts, nil
+}
+
+func (s *SummarizeService) normalizeOptions(opts SummaryOptions) SummaryOptions {
+	opts.Template = strings.ToLower(strings.TrimSpace(opts.Template))
+	opts.Length = strings.ToLower(strings.TrimSpace(opts.Length))
+	opts.Language = strings.ToLower(strings.TrimSpace(opts.Language))
+	opts.Model = strings.TrimSpace(opts.Model)
+	if opts.Template == "" {
+		opts.Template = TemplateMeeting
+	}
+	if opts.Length == "" {
+		opts.Length = LengthMedium
+	}
+	if opts.Language == "" {
+		opts.Language = LanguageIndonesian
+	}
+	return opts
+}
+
+// promptVersion adalah versi prompt yang dicatat di hasil, mis.
+// "v1/rapat/sedang/id", agar rating per template, panjang dan bahasa bisa
+// dibedakan. Prompt dengan glosarium diberi akhiran "/g-<hash>" karena
+// istilahnya ikut masuk prompt. Model tidak ikut karena sudah dicatat
+// terpisah.
+func promptVersion(opts SummaryOptions, vocab *Vocabulary) string {
+	version := PromptVersion + "/" + opts.Template + "/" + opts.Length + "/" + opts.Language
+	if h := vocab.hash(); h != "" {
+		version += "/g-" + h
+	}
+	return version
+}
+
+// summaryPrompt menyusun prompt peringkasan. Dengan opsi bawaan dan tanpa
+// glosarium hasilnya sama persis dengan prompt PromptVersion v1.
+func summaryPrompt(text string, opts SummaryOptions, vocab *Vocabulary) string {
+	instruction := templateInstructions[opts.Template] +
+		` Perhatikan label "Pembicara X:" untuk mengidentifikasi siapa yang berbicara. Jika memungkinkan, sebutkan pembicara (misalnya "[Pembicara 1]") saat merangkum poin penting atau action item.` +
+		lengthInstructions[opts.Length] +
+		languageInstructions[opts.Language] +
+		vocab.promptInstruction() +
+		" Gunakan format Markdown yang rapi dan informatif."
+	return fmt.Sprintf("%s\n\n\tTRANSKRIP:\n\t\"%s\"\n\t", instruction, text)
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"summarize-me-api/internal/auth"
+	"summarize-me-api/internal/store"
+	"time"
+)
+
+// TokenPrefix menandai personal access token agar mudah dibedakan dari
+// ID token Firebase/OIDC (dan mudah dideteksi secret scanner).
+const TokenPrefix = "smk_"
+
+// lastUsedResolution membatasi seberapa sering LastUsedAt ditulis ke disk.
+const lastUsedResolution = time.Minute
+
+// ErrInvalidScope dikembalikan jika scope yang diminta tidak dikenal.
+var ErrInvalidScope = errors.New("scope tidak dikenal")
+
+// AccessToken adalah personal access token yang tersimpan. Token asli tidak
+// pernah disimpan, hanya hash SHA-256-nya.
+type AccessToken struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"userId"`
+	Email      string     `json:"email,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	TokenHash  string     `json:"tokenHash"`
+	Hint       string     `json:"hint"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+
+	// EmailVerified disalin dari principal pembuat token.
+	EmailVerified bool `json:"emailVerified,omitempty"`
+}
+
+// Active bernilai true jika token belum dicabut dan belum kedaluwarsa.
+func (t AccessToken) Active(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// TokenService mengelola personal access token dan sekaligus menjadi
+// auth.Authenticator untuk token berawalan TokenPrefix.
+type TokenService struct {
+	tokens *store.Collection[AccessToken]
+}
+
+// NewTokenService membuat instance baru dari TokenService.
+func NewTokenService(tokens *store.Collection[AccessToken]) *TokenService {
+	return &TokenService{tokens: tokens}
+}
+
+// CreateToken membuat token baru dan mengembalikan nilai aslinya.
+// Nilai asli hanya bisa dilihat sekali, saat dibuat.
+func (s *TokenService) CreateToken(principal *auth.Principal, name string, scopes []string, ttl time.Duration) (AccessToken, string, error) {
+	if len(scopes) == 0 {
+		return AccessToken{}, "", fmt.Errorf("%w: minimal satu scope wajib diisi", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !principal.HasScope(scope) || !validScope(scope) {
+			return AccessToken{}, "", fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return AccessToken{}, "", fmt.Errorf("gagal membuat token acak: %w", err)
+	}
+	plain := TokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	token := AccessToken{
+		ID:        store.NewID(),
+		UserID:    principal.UID,
+		Email:     principal.Email,
+		Name:      name,
+		Scopes:    scopes,
+		TokenHash: hashToken(plain),
+		Hint:      plain[len(plain)-4:],
+		CreatedAt: now,
+
+		EmailVerified: principal.EmailVerified,
+	}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.tokens.Put(token.ID, token); err != nil {
+		return AccessToken{}, "", err
+	}
+	log.Printf("Personal access token %s dibuat untuk userID: %s", token.ID, token.UserID)
+	return token, plain, nil
+}
+
+// ListTokens mengembalikan semua token milik user, termasuk yang sudah dicabut.
+func (s *TokenService) ListTokens(userID string) []AccessToken {
+	return s.tokens.List(func(t AccessToken) bool { return t.UserID == userID })
+}
+
+// RevokeToken mencabut token milik user.
+func (s *TokenService) RevokeToken(userID, tokenID string) error {
+	_, err := s.tokens.Update(tokenID, func(t *AccessToken) error {
+		if t.UserID != userID {
+			return store.ErrNotFound
+		}
+		if t.RevokedAt == nil {
+			now := time.Now()
+			t.RevokedAt = &now
+		}
+		return nil
+	})
+	return err
+}
+
+// Authenticate memverifikasi personal access token.
+func (s *TokenService) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return nil, auth.ErrInvalidToken
+	}
+	hash := hashToken(token)
+	now := time.Now()
+
+	matches := s.tokens.List(func(t AccessToken) bool { return t.TokenHash == hash })
+	if len(matches) == 0 || !matches[0].Active(now) {
+		return nil, auth.ErrInvalidToken
+	}
+	found := matches[0]
+
+	if found.LastUsedAt == nil || now.Sub(*found.LastUsedAt) > lastUsedResolution {
+		if _, err := s.tokens.Update(found.ID, func(t *AccessToken) error {
+			t.LastUsedAt = &now
+			return nil
+		}); err != nil {
+			log.Printf("WARN: Gagal mencatat lastUsedAt token %s: %v", found.ID, err)
+		}
+	}
+
+	return &auth.Principal{
+		UID:      found.UserID,
+		Email:    found.Email,
+		Provider: "pat",
+		Scopes:   found.Scopes,
+
+		EmailVerified: found.EmailVerified,
+	}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func validScope(scope string) bool {
+	return contains(auth.AllScopes, scope)
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"summarize-me-api/internal/store"
+	"time"
+)
+
+// ErrInvalidUsageQuery dikembalikan jika parameter laporan pemakaian tidak valid.
+var ErrInvalidUsageQuery = errors.New("parameter laporan pemakaian tidak valid")
+
+// Jenis pemakaian yang dicatat di ledger.
+const (
+	UsageKindJob        = "job"
+	UsageKindRegenerate = "regenerate"
+	// UsageKindLive adalah menit audio sesi live (Speech streaming);
+	// UsageKindLiveSummary ringkasan berjalan selama sesi.
+	UsageKindLive        = "live"
+	UsageKindLiveSummary = "live_summary"
+	UsageKindQA          = "qa"
+)
+
+// Pengelompokan laporan pemakaian.
+const (
+	UsageByDay   = "day"
+	UsageByUser  = "user"
+	UsageByModel = "model"
+)
+
+// Usage adalah pemakaian provider untuk satu ringkasan.
+type Usage struct {
+	// AudioSeconds adalah durasi yang ditagih Speech-to-Text.
+	AudioSeconds float64 `json:"audioSeconds"`
+	// InputTokens dan OutputTokens diambil dari UsageMetadata Gemini.
+	InputTokens  int64 `json:"inputTokens"`
+	OutputTokens int64 `json:"outputTokens"`
+	// StorageBytes adalah ukuran file yang diupload ke GCS.
+	StorageBytes int64 `json:"storageBytes"`
+}
+
+func (u Usage) isZero() bool {
+	return u == Usage{}
+}
+
+func (u *Usage) add(o Usage) {
+	u.AudioSeconds += o.AudioSeconds
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.StorageBytes += o.StorageBytes
+}
+
+// ModelUsage adalah token yang dipakai satu model Gemini.
+type ModelUsage struct {
+	Model string `json:"model"`
+	Usage Usage  `json:"usage"`
+}
+
+// UsageError membawa pemakaian yang sudah terjadi (dan ditagih provider)
+// sebelum proses gagal, mis. audio sudah ditranskrip tetapi semua model
+// gagal meringkas.
+type UsageError struct {
+	Err error
+	// Usage adalah pemakaian Speech dan GCS.
+	Usage Usage
+	// Failed adalah token model yang dicoba lalu gagal.
+	Failed []ModelUsage
+}
+
+func (e *UsageError) Error() string { return e.Err.Error() }
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// withUsage membungkus err dengan pemakaian parsial. err dikembalikan apa
+// adanya jika tidak ada pemakaian yang perlu dicatat.
+func withUsage(err error, usage Usage, failed []ModelUsage) error {
+	if err == nil || (usage.isZero() && len(failed) == 0) {
+		return err
+	}
+	return &UsageError{Err: err, Usage: usage, Failed: failed}
+}
+
+// Cost adalah biaya pemakaian per provider dalam mata uang PriceTable.
+type Cost struct {
+	Speech  float64 `json:"speech"`
+	Gemini  float64 `json:"gemini"`
+	Storage float64 `json:"storage"`
+	Total   float64 `json:"total"`
+}
+
+func (c *Cost) add(o Cost) {
+	c.Speech = roundCost(c.Speech + o.Speech)
+	c.Gemini = roundCost(c.Gemini + o.Gemini)
+	c.Storage = roundCost(c.Storage + o.Storage)
+	c.Total = roundCost(c.Total + o.Total)
+}
+
+// roundCost membulatkan ke 6 desimal agar penjumlahan float tetap rapi.
+func roundCost(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
+
+// ModelPrice adalah harga token satu model Gemini per satu juta token.
+type ModelPrice struct {
+	Model            string
+	InputPerMillion  float64
+	OutputPerMillion float64
+}
+
+// PriceTable adalah tabel harga untuk menghitung biaya pemakaian.
+type PriceTable struct {
+	Currency        string
+	SpeechPerMinute float64
+	StoragePerGB    float64
+	Models          []ModelPrice
+}
+
+// Cost menghitung biaya pemakaian u dengan model. ok bernilai false jika
+// model tidak ada di tabel harga; biaya Gemini-nya dihitung 0.
+func (p PriceTable) Cost(model string, u Usage) (cost Cost, ok bool) {
+	cost.Speech = roundCost(u.AudioSeconds / 60 * p.SpeechPerMinute)
+	cost.Storage = roundCost(float64(u.StorageBytes) / (1 << 30) * p.StoragePerGB)
+	for _, m := range p.Models {
+		if m.Model == model {
+			cost.Gemini = roundCost(float64(u.InputTokens)/1e6*m.InputPerMillion + float64(u.OutputTokens)/1e6*m.OutputPerMillion)
+			ok = true
+			break
+		}
+	}
+	cost.Total = roundCost(cost.Speech + cost.Gemini + cost.Storage)
+	return cost, ok || u.InputTokens+u.OutputTokens == 0
+}
+
+// UsageRecord adalah satu baris ledger pemakaian. Biaya dihitung saat
+// dicatat, sehingga perubahan harga tidak mengubah catatan lama.
+type UsageRecord struct {
+	ID        string `json:"id"`
+	UserID    string `json:"userId"`
+	Kind      string `json:"kind"`
+	JobID     string `json:"jobId,omitempty"`
+	SummaryID string `json:"summaryId,omitempty"`
+	Model     string `json:"model"`
+	Usage     Usage  `json:"usage"`
+	// Failed menandai pemakaian dari percobaan yang gagal: job gagal, atau
+	// model yang gagal sebelum fallback.
+	Failed    bool      `json:"failed,omitempty"`
+	Cost      Cost      `json:"cost"`
+	Currency  string    `json:"currency"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UsageFilter membatasi catatan ledger. UserID kosong berarti semua user;
+// To bersifat eksklusif.
+type UsageFilter struct {
+	UserID string
+	From   time.Time
+	To     time.Time
+}
+
+// UsageTotals adalah jumlah pemakaian dan biaya satu kelompok.
+type UsageTotals struct {
+	// Key adalah tanggal (YYYY-MM-DD), ID user atau nama model sesuai GroupBy.
+	Key     string `json:"key,omitempty"`
+	Records int    `json:"records"`
+	Usage   Usage  `json:"usage"`
+	Cost    Cost   `json:"cost"`
+}
+
+func (t *UsageTotals) add(r UsageRecord) {
+	t.Records++
+	t.Usage.add(r.Usage)
+	t.Cost.add(r.Cost)
+}
+
+// UsageReport adalah rekap pemakaian dalam satu periode.
+type UsageReport struct {
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	Currency string        `json:"currency"`
+	GroupBy  string        `json:"groupBy"`
+	Total    UsageTotals   `json:"total"`
+	Groups   []UsageTotals `json:"groups"`
+}
+
+// UsageService mencatat pemakaian provider dan biayanya di ledger: per job,
+// ringkasan ulang, sesi live dan tanya-jawab.
+type UsageService struct {
+	ledger *store.Collection[UsageRecord]
+	prices PriceTable
+}
+
+// NewUsageService membuat instance baru dari UsageService.
+func NewUsageService(ledger *store.Collection[UsageRecord], prices PriceTable) *UsageService {
+	return &UsageService{ledger: ledger, prices: prices}
+}
+
+// HandleJobFinished adalah JobListener yang mencatat pemakaian job, termasuk
+// pemakaian parsial job yang gagal. ID catatan sama dengan ID job agar
+// tidak tercatat dua kali.
+func (s *UsageService) HandleJobFinished(job Job, outcome JobOutcome) {
+	s.RecordOutcome(UsageRecord{
+		ID:        job.ID,
+		UserID:    job.UserID,
+		Kind:      UsageKindJob,
+		JobID:     job.ID,
+		SummaryID: outcome.SummaryID,
+	}, outcome.Result, outcome.Err)
+}
+
+// RecordOutcome mencatat pemakaian satu peringkasan berdasarkan rec: dari
+// result jika berhasil, atau dari UsageError di err jika gagal. Token model
+// yang gagal (termasuk sebelum fallback) dicatat sebagai baris terpisah
+// per model agar biayanya memakai harga model tersebut. Kegagalan mencatat
+// hanya masuk log.
+func (s *UsageService) RecordOutcome(rec UsageRecord, result *SummarizeResult, err error) {
+	if rec.ID == "" {
+		rec.ID = store.NewID()
+	}
+	var failed []ModelUsage
+	if result != nil {
+		rec.Model, rec.Usage = result.Model, result.Usage
+		failed = result.FailedUsage
+	} else {
+		var usageErr *UsageError
+		if !errors.As(err, &usageErr) {
+			return
+		}
+		rec.Usage, rec.Failed = usageErr.Usage, true
+		failed = usageErr.Failed
+	}
+
+	records := make([]UsageRecord, 0, len(failed)+1)
+	if result != nil || !rec.Usage.isZero() {
+		records = append(records, rec)
+	}
+	for i, m := range failed {
+		r := rec
+		r.ID = fmt.Sprintf("%s:failed-%d", rec.ID, i+1)
+		r.Model, r.Usage, r.Failed = m.Model, m.Usage, true
+		records = append(records, r)
+	}
+	for _, r := range records {
+		if _, err := s.Record(r); err != nil {
+			log.Printf("ERROR: Gagal mencatat pemakaian %s %s: %v", r.Kind, r.ID, err)
+		}
+	}
+}
+
+// Record menghitung biaya rec dengan tabel harga lalu menyimpannya.
+func (s *UsageService) Record(rec UsageRecord) (UsageRecord, error) {
+	if rec.ID == "" {
+		rec.ID = store.NewID()
+	}
+	cost, ok := s.prices.Cost(rec.Model, rec.Usage)
+	if !ok {
+		log.Printf("WARN: Model %s tidak ada di tabel harga, biaya Gemini dicatat 0", rec.Model)
+	}
+	rec.Cost = cost
+	rec.Currency = s.prices.Currency
+	rec.CreatedAt = time.Now()
+	if err := s.ledger.Put(rec.ID, rec); err != nil {
+		return rec, err
+	}
+	return rec, nil
+}
+
+// Records mengembalikan catatan ledger sesuai filter, terbaru dulu.
+func (s *UsageService) Records(filter UsageFilter) []UsageRecord {
+	records := s.ledger.List(func(r UsageRecord) bool {
+		return (filter.UserID == "" || r.UserID == filter.UserID) &&
+			!r.CreatedAt.Before(filter.From) && r.CreatedAt.Before(filter.To)
+	})
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.After(records[j].CreatedAt) })
+	return records
+}
+
+// Report merekap pemakaian sesuai filter, dikelompokkan per hari, user atau
+// model. Kelompok hari diurutkan menurut tanggal; lainnya menurut biaya
+// terbesar.
+func (s *UsageService) Report(filter UsageFilter, groupBy string) (UsageReport, error) {
+	var key func(UsageRecord) string
+	switch groupBy {
+	case UsageByDay:
+		key = func(r UsageRecord) string { return r.CreatedAt.UTC().Format(time.DateOnly) }
+	case UsageByUser:
+		key = func(r UsageRecord) string { return r.UserID }
+	case UsageByModel:
+		key = func(r UsageRecord) string { return r.Model }
+	default:
+		return UsageReport{}, fmt.Errorf("%w: groupBy harus '%s', '%s' atau '%s'", ErrInvalidUsageQuery, UsageByDay, UsageByUser, UsageByModel)
+	}
+	if !filter.From.Before(filter.To) {
+		return UsageReport{}, fmt.Errorf("%w: from harus sebelum to", ErrInvalidUsageQuery)
+	}
+
+	report := UsageReport{From: filter.From, To: filter.To, Currency: s.prices.Currency, GroupBy: groupBy}
+	groups := make(map[string]*UsageTotals)
+	for _, r := range s.Records(filter) {
+		report.Total.add(r)
+		k := key(r)
+		g, ok := groups[k]
+		if !ok {
+			g = &UsageTotals{Key: k}
+			groups[k] = g
+		}
+		g.add(r)
+	}
+	report.Groups = make([]UsageTotals, 0, len(groups))
+	for _, g := range groups {
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		a, b := report.Groups[i], report.Groups[j]
+		if groupBy == UsageByDay || a.Cost.Total == b.Cost.Total {
+			return a.Key < b.Key
+		}
+		return a.Cost.Total > b.Cost.Total
+	})
+	return report, nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"summarize-me-api/internal/auth"
+	"summarize-me-api/internal/store"
+	"time"
+)
+
+// lastSeenResolution membatasi seberapa sering LastSeenAt ditulis ke disk.
+const lastSeenResolution = time.Minute
+
+// ErrInvalidRole dikembalikan jika role yang diberikan tidak dikenal.
+var ErrInvalidRole = errors.New("role tidak dikenal")
+
+// User adalah catatan lokal untuk setiap user yang pernah login.
+// Roles di sini digabung dengan role dari custom claims provider.
+type User struct {
+	UID        string    `json:"uid"`
+	Email      string    `json:"email,omitempty"`
+	Provider   string    `json:"provider"`
+	Roles      []string  `json:"roles,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+
+	// ProviderRoles adalah role dari custom claims pada login interaktif
+	// terakhir. Personal access token tidak membawa claims, jadi role ini
+	// yang dipakai untuk principal PAT.
+	ProviderRoles []string `json:"providerRoles,omitempty"`
+
+	Preferences UserPreferences `json:"preferences"`
+}
+
+// UserPreferences adalah pengaturan yang bisa diubah user sendiri.
+type UserPreferences struct {
+	// EmailOnComplete mengirim ringkasan ke email user setiap job selesai.
+	EmailOnComplete bool `json:"emailOnComplete"`
+}
+
+// UserService mengelola user store lokal dan role-nya.
+type UserService struct {
+	users *store.Collection[User]
+	// admins berisi UID atau email yang selalu mendapat role admin,
+	// untuk bootstrap sebelum ada admin lain yang bisa memberi role.
+	// Email hanya dicocokkan jika provider menyatakan email terverifikasi.
+	admins []string
+}
+
+// NewUserService membuat instance baru dari UserService.
+func NewUserService(users *store.Collection[User], admins []string) *UserService {
+	return &UserService{users: users, admins: admins}
+}
+
+// Authenticator membungkus Authenticator lain agar setiap principal yang
+// lolos verifikasi tercatat di user store dan mendapat role lengkap.
+func (s *UserService) Authenticator(inner auth.Authenticator) auth.Authenticator {
+	return &userAuthenticator{inner: inner, users: s}
+}
+
+type userAuthenticator struct {
+	inner auth.Authenticator
+	users *UserService
+}
+
+func (a *userAuthenticator) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	principal, err := a.inner.Authenticate(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	a.users.resolve(principal)
+	return principal, nil
+}
+
+// resolve mencatat user dan menggabungkan role dari semua sumber ke principal.
+func (s *UserService) resolve(principal *auth.Principal) {
+	now := time.Now()
+	user, err := s.users.Get(principal.UID)
+	isNew := errors.Is(err, store.ErrNotFound)
+
+	isPAT := principal.Provider == "pat"
+	providerRoles := dedupe(principal.Roles)
+	rolesChanged := !isPAT && !slices.Equal(user.ProviderRoles, providerRoles)
+
+	if isNew || rolesChanged || now.Sub(user.LastSeenAt) > lastSeenResolution || (principal.Email != "" && user.Email != principal.Email) {
+		if isNew {
+			user = User{UID: principal.UID, CreatedAt: now}
+		}
+		if principal.Email != "" {
+			user.Email = principal.Email
+		}
+		if !isPAT {
+			user.Provider = principal.Provider
+			user.ProviderRoles = providerRoles
+		}
+		user.LastSeenAt = now
+		if err := s.users.Put(user.UID, user); err != nil {
+			log.Printf("WARN: Gagal menyimpan user %s: %v", user.UID, err)
+		}
+	}
+
+	roles := append([]string{auth.RoleUser}, principal.Roles...)
+	if isPAT {
+		roles = append(roles, user.ProviderRoles...)
+	}
+	roles = append(roles, user.Roles...)
+	if s.isBootstrapAdmin(principal) {
+		roles = append(roles, auth.RoleAdmin)
+	}
+	principal.Roles = dedupe(roles)
+}
+
+// isBootstrapAdmin mencocokkan UID, atau email yang sudah diverifikasi provider.
+// Tanpa syarat verifikasi, siapa pun bisa mendaftar dengan email admin di
+// provider yang mengizinkan email belum terverifikasi.
+func (s *UserService) isBootstrapAdmin(principal *auth.Principal) bool {
+	emailOK := principal.Email != "" && principal.EmailVerified
+	for _, admin := range s.admins {
+		if admin == principal.UID || (emailOK && strings.EqualFold(admin, principal.Email)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetUser mengambil user berdasarkan UID.
+func (s *UserService) GetUser(uid string) (User, error) {
+	return s.users.Get(uid)
+}
+
+// FindUsers mencari user berdasarkan potongan email atau UID.
+// Query kosong mengembalikan semua user.
+func (s *UserService) FindUsers(query string) []User {
+	query = strings.ToLower(query)
+	users := s.users.List(func(u User) bool {
+		return query == "" || strings.Contains(strings.ToLower(u.Email), query) || strings.Contains(strings.ToLower(u.UID), query)
+	})
+	sort.Slice(users, func(i, j int) bool { return users[i].LastSeenAt.After(users[j].LastSeenAt) })
+	return users
+}
+
+// SetRoles mengganti role lokal milik user.
+func (s *UserService) SetRoles(uid string, roles []string) (User, error) {
+	for _, role := range roles {
+		if !contains(auth.AllRoles, role) {
+			return User{}, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+		}
+	}
+	return s.users.Update(uid, func(u *User) error {
+		u.Roles = dedupe(roles)
+		return nil
+	})
+}
+
+// UpdatePreferences mengganti preferensi milik user.
+func (s *UserService) UpdatePreferences(uid string, prefs UserPreferences) (User, error) {
+	return s.users.Update(uid, func(u *User) error {
+		u.Preferences = prefs
+		return nil
+	})
+}
+
+func dedupe(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if item != "" && !seen[item] {
+			seen[item] = true
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func contains(list []string, v string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"slices"
+	"summarize-me-api/internal/auth"
+	"summarize-me-api/internal/store"
+	"testing"
+)
+
+func newUserService(t *testing.T, admins ...string) *UserService {
+	t.Helper()
+	users, err := store.OpenCollection[User](t.TempDir(), "users")
+	if err != nil {
+		t.Fatalf("OpenCollection: %v", err)
+	}
+	return NewUserService(users, admins)
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal auth.Principal
+		want      bool
+	}{
+		{"uid cocok", auth.Principal{UID: "uid-admin", Provider: "firebase"}, true},
+		{"email terverifikasi", auth.Principal{UID: "u1", Email: "admin@acme.id", EmailVerified: true, Provider: "firebase"}, true},
+		{"email beda huruf besar", auth.Principal{UID: "u2", Email: "Admin@ACME.id", EmailVerified: true, Provider: "oidc"}, true},
+		{"email belum terverifikasi", auth.Principal{UID: "u3", Email: "admin@acme.id", Provider: "oidc"}, false},
+		{"pat email belum terverifikasi", auth.Principal{UID: "u4", Email: "admin@acme.id", Provider: "pat"}, false},
+		{"email lain", auth.Principal{UID: "u5", Email: "budi@acme.id", EmailVerified: true, Provider: "firebase"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newUserService(t, "uid-admin", "admin@acme.id")
+			p := tt.principal
+			s.resolve(&p)
+			if got := p.HasRole(auth.RoleAdmin); got != tt.want {
+				t.Errorf("admin = %v, want %v (roles %v)", got, tt.want, p.Roles)
+			}
+			if !slices.Contains(p.Roles, auth.RoleUser) {
+				t.Errorf("roles %v tidak berisi %q", p.Roles, auth.RoleUser)
+			}
+		})
+	}
+}
+
+func TestResolvePATUsesStoredProviderRoles(t *testing.T) {
+	s := newUserService(t)
+	login := auth.Principal{UID: "u1", Email: "budi@acme.id", Provider: "oidc", Roles: []string{auth.RoleSupport}}
+	s.resolve(&login)
+	if _, err := s.SetRoles("u1", []string{auth.RoleTracker}); err != nil {
+		t.Fatalf("SetRoles: %v", err)
+	}
+
+	pat := auth.Principal{UID: "u1", Email: "budi@acme.id", Provider: "pat"}
+	s.resolve(&pat)
+	for _, want := range []string{auth.RoleUser, auth.RoleSupport, auth.RoleTracker} {
+		if !slices.Contains(pat.Roles, want) {
+			t.Errorf("roles PAT %v tidak berisi %q", pat.Roles, want)
+		}
+	}
+
+	// Role yang dicabut di provider ikut hilang dari PAT setelah login
+	// interaktif berikutnya.
+	login = auth.Principal{UID: "u1", Email: "budi@acme.id", Provider: "oidc"}
+	s.resolve(&login)
+	pat = auth.Principal{UID: "u1", Email: "budi@acme.id", Provider: "pat"}
+	s.resolve(&pat)
+	if slices.Contains(pat.Roles, auth.RoleSupport) {
+		t.Errorf("roles PAT %v masih berisi %q", pat.Roles, auth.RoleSupport)
+	}
+	user, err := s.GetUser("u1")
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if user.Provider != "oidc" {
+		t.Errorf("provider = %q, want oidc", user.Provider)
+	}
+}
@@ -0,0 +1,458 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"summarize-me-api/internal/remote"
+	"summarize-me-api/internal/resilience"
+	"summarize-me-api/internal/store"
+	"sync"
+	"time"
+)
+
+// webhookPollInterval adalah seberapa sering delivery yang jatuh tempo dicari.
+const webhookPollInterval = 5 * time.Second
+
+// deliveryRetention adalah lama log delivery yang sudah selesai disimpan
+// sebelum dihapus, dicek setiap webhookPruneInterval.
+const (
+	deliveryRetention    = 30 * 24 * time.Hour
+	webhookPruneInterval = time.Hour
+)
+
+// Event yang dikirim lewat webhook.
+const (
+	EventJobCompleted = "job.completed"
+	EventJobFailed    = "job.failed"
+	EventPing         = "ping"
+)
+
+// Header yang dikirim bersama setiap webhook.
+const (
+	SignatureHeader = "X-SummarizeMe-Signature"
+	EventHeader     = "X-SummarizeMe-Event"
+	DeliveryHeader  = "X-SummarizeMe-Delivery"
+)
+
+// ErrInvalidWebhook dikembalikan jika input webhook tidak valid.
+var ErrInvalidWebhook = errors.New("webhook tidak valid")
+
+// Webhook adalah URL milik user yang menerima event job.
+type Webhook struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"userId"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DeliveryStatus adalah status pengiriman webhook.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// DeliveryAttempt adalah satu percobaan pengiriman.
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+// WebhookDelivery adalah catatan pengiriman satu event ke satu webhook.
+type WebhookDelivery struct {
+	ID        string            `json:"id"`
+	WebhookID string            `json:"webhookId"`
+	UserID    string            `json:"userId"`
+	Event     string            `json:"event"`
+	Status    DeliveryStatus    `json:"status"`
+	Attempts  []DeliveryAttempt `json:"attempts"`
+	CreatedAt time.Time         `json:"createdAt"`
+	// Payload hanya disimpan selama delivery masih pending. Setelah
+	// berhasil atau gagal permanen payload dibuang, karena bisa berisi
+	// transkrip lengkap.
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// NextAttemptAt adalah jadwal percobaan berikutnya selama status masih
+	// pending, disimpan agar retry berlanjut setelah server restart.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+}
+
+// WebhookOptions mengatur kebijakan retry pengiriman.
+type WebhookOptions struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+	// AllowPrivate mengizinkan URL webhook ke alamat internal. Hanya untuk
+	// development, karena URL webhook diisi oleh user.
+	AllowPrivate bool
+}
+
+// WebhookService mengelola webhook user dan mengirim event job yang
+// ditandatangani HMAC-SHA256.
+type WebhookService struct {
+	hooks      *store.Collection[Webhook]
+	deliveries *store.Collection[WebhookDelivery]
+	opts       WebhookOptions
+	client     *http.Client
+
+	mu       sync.Mutex
+	inflight map[string]bool
+}
+
+// NewWebhookService membuat instance baru dari WebhookService.
+func NewWebhookService(hooks *store.Collection[Webhook], deliveries *store.Collection[WebhookDelivery], opts WebhookOptions) *WebhookService {
+	return &WebhookService{
+		hooks:      hooks,
+		deliveries: deliveries,
+		opts:       opts,
+		client:     remote.NewClient(opts.Timeout, opts.AllowPrivate),
+		inflight:   make(map[string]bool),
+	}
+}
+
+// CreateWebhook mendaftarkan URL baru. Events kosong berarti semua event job.
+// Host yang mengarah ke alamat internal langsung ditolak; pengecekan saat
+// dial tetap berlaku setiap kali webhook dikirim.
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID, rawURL string, events []string) (Webhook, error) {
+	u, err := remote.ValidateURL(rawURL)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("%w: URL harus http(s) yang lengkap", ErrInvalidWebhook)
+	}
+	if !s.opts.AllowPrivate {
+		if err := remote.CheckHost(ctx, u); err != nil {
+			return Webhook{}, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+		}
+	}
+	if len(events) == 0 {
+		events = []string{EventJobCompleted, EventJobFailed}
+	}
+	for _, e := range events {
+		if e != EventJobCompleted && e != EventJobFailed {
+			return Webhook{}, fmt.Errorf("%w: event tidak dikenal: %s", ErrInvalidWebhook, e)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Webhook{}, fmt.Errorf("gagal membuat secret: %w", err)
+	}
+	hook := Webhook{
+		ID:        store.NewID(),
+		UserID:    userID,
+		URL:       u.String(),
+		Secret:    "whsec_" + hex.EncodeToString(secret),
+		Events:    dedupe(events),
+		CreatedAt: time.Now(),
+	}
+	return hook, s.hooks.Put(hook.ID, hook)
+}
+
+// ListWebhooks mengembalikan webhook milik user.
+func (s *WebhookService) ListWebhooks(userID string) []Webhook {
+	return s.hooks.List(func(h Webhook) bool { return h.UserID == userID })
+}
+
+// GetWebhook mengambil webhook milik user.
+func (s *WebhookService) GetWebhook(userID, id string) (Webhook, error) {
+	hook, err := s.hooks.Get(id)
+	if err != nil {
+		return Webhook{}, err
+	}
+	if hook.UserID != userID {
+		return Webhook{}, store.ErrNotFound
+	}
+	return hook, nil
+}
+
+// DeleteWebhook menghapus webhook milik user beserta log delivery-nya.
+func (s *WebhookService) DeleteWebhook(userID, id string) error {
+	if _, err := s.GetWebhook(userID, id); err != nil {
+		return err
+	}
+	if err := s.hooks.Delete(id); err != nil {
+		return err
+	}
+	for _, d := range s.deliveries.List(func(d WebhookDelivery) bool { return d.WebhookID == id }) {
+		if err := s.deliveries.Delete(d.ID); err != nil {
+			log.Printf("WARN: Gagal menghapus log delivery %s: %v", d.ID, err)
+		}
+	}
+	return nil
+}
+
+// ListDeliveries mengembalikan log pengiriman sebuah webhook, terbaru dulu.
+func (s *WebhookService) ListDeliveries(userID, webhookID string, limit int) ([]WebhookDelivery, error) {
+	if _, err := s.GetWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	deliveries := s.deliveries.List(func(d WebhookDelivery) bool { return d.WebhookID == webhookID })
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt) })
+	if limit > 0 && len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// SendTest mengirim event ping satu kali dan menunggu hasilnya. Ping tidak
+// pernah diulang: jika gagal, delivery langsung ditandai failed agar
+// pemanggil melihat hasil akhirnya saat itu juga.
+func (s *WebhookService) SendTest(ctx context.Context, userID, webhookID string) (WebhookDelivery, error) {
+	hook, err := s.GetWebhook(userID, webhookID)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	delivery, err := s.newDelivery(hook, EventPing, map[string]any{"message": "Tes webhook dari SummarizeMe"})
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	delivery = s.attempt(ctx, hook, delivery)
+	if delivery.Status != DeliverySucceeded {
+		delivery.finish(DeliveryFailed)
+		if err := s.deliveries.Put(delivery.ID, delivery); err != nil {
+			log.Printf("WARN: Gagal menyimpan log delivery %s: %v", delivery.ID, err)
+		}
+	}
+	return delivery, nil
+}
+
+// HandleJobFinished adalah JobListener yang mengirim event ke semua
+// webhook user yang berlangganan.
+func (s *WebhookService) HandleJobFinished(job Job, outcome JobOutcome) {
+	event := EventJobCompleted
+	if outcome.Err != nil {
+		event = EventJobFailed
+	}
+
+	data := map[string]any{"job": job}
+	if outcome.Result != nil {
+		data["summary"] = map[string]any{
+			"id":            outcome.SummaryID,
+			"text":          outcome.Result.Summary,
+			"model":         outcome.Result.Model,
+			"fallbackFrom":  outcome.Result.FallbackFrom,
+			"promptVersion": outcome.Result.PromptVersion,
+			"languageCode":  outcome.Result.LanguageCode,
+		}
+		data["transcript"] = outcome.Result.Transcript
+	}
+
+	for _, hook := range s.ListWebhooks(job.UserID) {
+		if !contains(hook.Events, event) {
+			continue
+		}
+		delivery, err := s.newDelivery(hook, event, data)
+		if err != nil {
+			log.Printf("ERROR: Gagal membuat delivery webhook %s: %v", hook.ID, err)
+			continue
+		}
+		s.dispatch(delivery.ID)
+	}
+}
+
+// Run mengirim ulang delivery pending yang sudah jatuh tempo sampai ctx
+// selesai. Dipanggil sekali saat server start, sehingga delivery yang
+// tertunda sebelum restart ikut dilanjutkan. Log delivery yang sudah
+// selesai dan lebih tua dari deliveryRetention ikut dihapus.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	for {
+		now := time.Now()
+		for _, d := range s.deliveries.List(func(d WebhookDelivery) bool { return d.due(now) }) {
+			s.dispatch(d.ID)
+		}
+		if now.Sub(lastPrune) >= webhookPruneInterval {
+			s.prune(now)
+			lastPrune = now
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// prune menghapus log delivery yang sudah selesai dan dibuat sebelum
+// now - deliveryRetention.
+func (s *WebhookService) prune(now time.Time) {
+	cutoff := now.Add(-deliveryRetention)
+	old := s.deliveries.List(func(d WebhookDelivery) bool {
+		return d.Status != DeliveryPending && d.CreatedAt.Before(cutoff)
+	})
+	for _, d := range old {
+		if err := s.deliveries.Delete(d.ID); err != nil {
+			log.Printf("WARN: Gagal menghapus log delivery %s: %v", d.ID, err)
+		}
+	}
+}
+
+// finish menandai delivery selesai dengan status akhir dan membuang payload.
+func (d *WebhookDelivery) finish(status DeliveryStatus) {
+	d.Status = status
+	d.NextAttemptAt = nil
+	d.Payload = nil
+}
+
+// due bernilai true jika delivery masih pending dan jadwalnya sudah lewat.
+// Ping dari SendTest tidak pernah diulang.
+func (d WebhookDelivery) due(now time.Time) bool {
+	return d.Status == DeliveryPending && d.Event != EventPing &&
+		(d.NextAttemptAt == nil || !d.NextAttemptAt.After(now))
+}
+
+// dispatch menjalankan satu percobaan di goroutine terpisah, kecuali
+// delivery yang sama sedang dikirim.
+func (s *WebhookService) dispatch(id string) {
+	s.mu.Lock()
+	if s.inflight[id] {
+		s.mu.Unlock()
+		return
+	}
+	s.inflight[id] = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.inflight, id)
+			s.mu.Unlock()
+		}()
+		s.deliver(id)
+	}()
+}
+
+func (s *WebhookService) newDelivery(hook Webhook, event string, data any) (WebhookDelivery, error) {
+	delivery := WebhookDelivery{
+		ID:        store.NewID(),
+		WebhookID: hook.ID,
+		UserID:    hook.UserID,
+		Event:     event,
+		Status:    DeliveryPending,
+		CreatedAt: time.Now(),
+	}
+	payload, err := json.Marshal(map[string]any{
+		"id":        delivery.ID,
+		"event":     event,
+		"createdAt": delivery.CreatedAt,
+		"data":      data,
+	})
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+	delivery.Payload = payload
+	return delivery, s.deliveries.Put(delivery.ID, delivery)
+}
+
+// deliver melakukan satu percobaan untuk delivery yang jatuh tempo. Jika
+// gagal, percobaan berikutnya dijadwalkan dengan exponential backoff +
+// jitter sampai MaxAttempts tercapai.
+func (s *WebhookService) deliver(id string) {
+	// Baca ulang dari store: poller bisa melihat snapshot lama dari
+	// delivery yang baru saja selesai dikirim.
+	delivery, err := s.deliveries.Get(id)
+	if err != nil || !delivery.due(time.Now()) {
+		return
+	}
+	hook, err := s.hooks.Get(delivery.WebhookID)
+	if err != nil {
+		delivery.finish(DeliveryFailed)
+		if err := s.deliveries.Put(delivery.ID, delivery); err != nil {
+			log.Printf("WARN: Gagal menyimpan log delivery %s: %v", delivery.ID, err)
+		}
+		return
+	}
+
+	delivery = s.attempt(context.Background(), hook, delivery)
+	if delivery.Status == DeliverySucceeded {
+		return
+	}
+	if len(delivery.Attempts) < s.opts.MaxAttempts {
+		next := time.Now().Add(resilience.Backoff(s.opts.InitialBackoff, s.opts.MaxBackoff, len(delivery.Attempts)))
+		delivery.NextAttemptAt = &next
+	} else {
+		delivery.finish(DeliveryFailed)
+		log.Printf("WARN: Webhook %s gagal dikirim setelah %d percobaan", hook.ID, len(delivery.Attempts))
+	}
+	if err := s.deliveries.Put(delivery.ID, delivery); err != nil {
+		log.Printf("WARN: Gagal menyimpan log delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// attempt mengirim satu kali dan mencatat hasilnya di log delivery.
+func (s *WebhookService) attempt(ctx context.Context, hook Webhook, delivery WebhookDelivery) WebhookDelivery {
+	start := time.Now()
+	result := DeliveryAttempt{At: start}
+
+	statusCode, err := s.post(ctx, hook, delivery)
+	result.StatusCode = statusCode
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	delivery.Attempts = append(delivery.Attempts, result)
+	if err == nil {
+		delivery.finish(DeliverySucceeded)
+	}
+	if putErr := s.deliveries.Put(delivery.ID, delivery); putErr != nil {
+		log.Printf("WARN: Gagal menyimpan log delivery %s: %v", delivery.ID, putErr)
+	}
+	return delivery
+}
+
+func (s *WebhookService) post(ctx context.Context, hook Webhook, delivery WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SummarizeMe-Webhook/1")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, "t="+timestamp+",v1="+Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// Jangan tampilkan alamat hasil resolve ke user lewat log delivery.
+		if errors.Is(err, remote.ErrBlockedAddress) {
+			return 0, remote.ErrBlockedAddress
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("penerima mengembalikan status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign menghitung HMAC-SHA256 atas "<timestamp>.<body>" dalam hex.
+// Penerima memverifikasi dengan menghitung ulang dan membandingkan nilai v1,
+// serta menolak timestamp yang terlalu lama untuk mencegah replay.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
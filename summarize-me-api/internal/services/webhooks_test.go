@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"summarize-me-api/internal/store"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// webhookReceiver adalah server uji yang mencatat request terakhir dan
+// membalas dengan status dari statuses berurutan (elemen terakhir diulang).
+type webhookReceiver struct {
+	*httptest.Server
+	hits   atomic.Int32
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	t.Helper()
+	r := &webhookReceiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := int(r.hits.Add(1))
+		r.header = req.Header.Clone()
+		r.body, _ = io.ReadAll(req.Body)
+		w.WriteHeader(statuses[min(n, len(statuses))-1])
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func newWebhookService(t *testing.T, opts WebhookOptions) *WebhookService {
+	t.Helper()
+	dir := t.TempDir()
+	hooks, err := store.OpenCollection[Webhook](dir, "webhooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliveries, err := store.OpenCollection[WebhookDelivery](dir, "webhook_deliveries")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.AllowPrivate = true
+	if opts.Timeout == 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	return NewWebhookService(hooks, deliveries, opts)
+}
+
+var signaturePattern = regexp.MustCompile(`^t=(\d+),v1=([0-9a-f]{64})$`)
+
+func TestWebhookSignatureHeader(t *testing.T) {
+	recv := newWebhookReceiver(t, http.StatusNoContent)
+	s := newWebhookService(t, WebhookOptions{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute})
+	hook, err := s.CreateWebhook(context.Background(), "u1", recv.URL+"/hook", nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	delivery, err := s.SendTest(context.Background(), "u1", hook.ID)
+	if err != nil {
+		t.Fatalf("SendTest: %v", err)
+	}
+	if delivery.Status != DeliverySucceeded || delivery.Payload != nil {
+		t.Errorf("delivery = %s, payload %s; want succeeded tanpa payload", delivery.Status, delivery.Payload)
+	}
+
+	sig := recv.header.Get(SignatureHeader)
+	m := signaturePattern.FindStringSubmatch(sig)
+	if m == nil {
+		t.Fatalf("%s = %q, want format t=<unix>,v1=<hex>", SignatureHeader, sig)
+	}
+	if want := Sign(hook.Secret, m[1], recv.body); m[2] != want {
+		t.Errorf("v1 = %s, want %s", m[2], want)
+	}
+	if ts, _ := strconv.ParseInt(m[1], 10, 64); time.Since(time.Unix(ts, 0)) > time.Minute {
+		t.Errorf("timestamp %s terlalu lama", m[1])
+	}
+	if h := recv.header.Get(EventHeader); h != EventPing {
+		t.Errorf("%s = %q", EventHeader, h)
+	}
+	if h := recv.header.Get(DeliveryHeader); h != delivery.ID {
+		t.Errorf("%s = %q, want %q", DeliveryHeader, h, delivery.ID)
+	}
+	var payload struct {
+		ID    string `json:"id"`
+		Event string `json:"event"`
+	}
+	if err := json.Unmarshal(recv.body, &payload); err != nil || payload.ID != delivery.ID || payload.Event != EventPing {
+		t.Errorf("payload = %s (%v)", recv.body, err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Nilai acuan dari:
+	// printf '1700000000.{"a":1}' | openssl dgst -sha256 -hmac whsec_test
+	want := "38877139021993b830af32feea6e18a8da83eb2f6e49ee50bd9e4cf4ca4d3789"
+	if got := Sign("whsec_test", "1700000000", []byte(`{"a":1}`)); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookRetrySchedule(t *testing.T) {
+	recv := newWebhookReceiver(t, http.StatusInternalServerError)
+	s := newWebhookService(t, WebhookOptions{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: 90 * time.Minute})
+	hook, err := s.CreateWebhook(context.Background(), "u1", recv.URL, []string{EventJobFailed})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	delivery, err := s.newDelivery(hook, EventJobFailed, map[string]any{"job": "j1"})
+	if err != nil {
+		t.Fatalf("newDelivery: %v", err)
+	}
+
+	// Rentang jeda sebelum percobaan ke-2 dan ke-3: [d/2, d).
+	windows := []time.Duration{time.Hour, 90 * time.Minute}
+	for i, window := range windows {
+		before := time.Now()
+		s.deliver(delivery.ID)
+		got, _ := s.deliveries.Get(delivery.ID)
+		if got.Status != DeliveryPending || len(got.Attempts) != i+1 || got.NextAttemptAt == nil {
+			t.Fatalf("percobaan %d: status %s, %d attempt, next %v", i+1, got.Status, len(got.Attempts), got.NextAttemptAt)
+		}
+		if wait := got.NextAttemptAt.Sub(before); wait < window/2 || wait > window {
+			t.Errorf("percobaan %d: jeda %v, want [%v, %v)", i+1, wait, window/2, window)
+		}
+		if got.Attempts[i].StatusCode != http.StatusInternalServerError || got.Attempts[i].Error == "" {
+			t.Errorf("attempt = %+v", got.Attempts[i])
+		}
+
+		// Belum jatuh tempo: tidak ada percobaan baru.
+		s.deliver(delivery.ID)
+		if n := recv.hits.Load(); n != int32(i+1) {
+			t.Fatalf("server dihubungi %d kali sebelum jatuh tempo, want %d", n, i+1)
+		}
+		if _, err := s.deliveries.Update(delivery.ID, func(d *WebhookDelivery) error {
+			past := time.Now().Add(-time.Second)
+			d.NextAttemptAt = &past
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.deliver(delivery.ID)
+	got, _ := s.deliveries.Get(delivery.ID)
+	if got.Status != DeliveryFailed || len(got.Attempts) != 3 || got.NextAttemptAt != nil || got.Payload != nil {
+		t.Errorf("setelah MaxAttempts: status %s, %d attempt, next %v, payload %s", got.Status, len(got.Attempts), got.NextAttemptAt, got.Payload)
+	}
+	if got.due(time.Now().Add(24 * time.Hour)) {
+		t.Error("delivery yang gagal permanen tidak boleh jatuh tempo lagi")
+	}
+}
+
+func TestWebhookPingIsNeverRetried(t *testing.T) {
+	recv := newWebhookReceiver(t, http.StatusBadGateway)
+	s := newWebhookService(t, WebhookOptions{MaxAttempts: 5, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+	hook, err := s.CreateWebhook(context.Background(), "u1", recv.URL, nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	delivery, err := s.SendTest(context.Background(), "u1", hook.ID)
+	if err != nil {
+		t.Fatalf("SendTest: %v", err)
+	}
+	if delivery.Status != DeliveryFailed || len(delivery.Attempts) != 1 {
+		t.Errorf("ping: status %s, %d attempt; want failed setelah 1 attempt", delivery.Status, len(delivery.Attempts))
+	}
+	s.deliver(delivery.ID)
+	if n := recv.hits.Load(); n != 1 {
+		t.Errorf("ping dikirim %d kali, want 1", n)
+	}
+}
+
+func TestWebhookPrune(t *testing.T) {
+	s := newWebhookService(t, WebhookOptions{MaxAttempts: 1, InitialBackoff: time.Second, MaxBackoff: time.Second})
+	now := time.Now()
+	old := now.Add(-deliveryRetention - time.Hour)
+	for id, d := range map[string]WebhookDelivery{
+		"lama-berhasil": {Status: DeliverySucceeded, CreatedAt: old},
+		"lama-gagal":    {Status: DeliveryFailed, CreatedAt: old},
+		"lama-pending":  {Status: DeliveryPending, CreatedAt: old},
+		"baru-berhasil": {Status: DeliverySucceeded, CreatedAt: now},
+	} {
+		d.ID = id
+		if err := s.deliveries.Put(id, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.prune(now)
+	for id, want := range map[string]bool{"lama-berhasil": false, "lama-gagal": false, "lama-pending": true, "baru-berhasil": true} {
+		if _, err := s.deliveries.Get(id); (err == nil) != want {
+			t.Errorf("%s: ada = %v, want %v", id, err == nil, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ErrNotFound dikembalikan jika record dengan ID tertentu tidak ada.
+var ErrNotFound = errors.New("data tidak ditemukan")
+
+// Collection menyimpan record bertipe T di memori dan menulis ulang seluruh
+// isinya ke satu file JSON setiap kali berubah. Cukup untuk volume kecil
+// (token, pengaturan user, metadata job) tanpa perlu database terpisah.
+//
+// Jika dir kosong, Collection hanya hidup di memori.
+type Collection[T any] struct {
+	mu    sync.RWMutex
+	path  string
+	items map[string]T
+}
+
+// OpenCollection membuka (atau membuat) collection bernama name di dir.
+func OpenCollection[T any](dir, name string) (*Collection[T], error) {
+	c := &Collection[T]{items: make(map[string]T)}
+	if dir == "" {
+		return c, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori data %s: %w", dir, err)
+	}
+	c.path = filepath.Join(dir, name+".json")
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca %s: %w", c.path, err)
+	}
+	if err := json.Unmarshal(data, &c.items); err != nil {
+		return nil, fmt.Errorf("gagal parse %s: %w", c.path, err)
+	}
+	return c, nil
+}
+
+// Get mengambil record berdasarkan ID.
+func (c *Collection[T]) Get(id string) (T, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	item, ok := c.items[id]
+	if !ok {
+		var zero T
+		return zero, ErrNotFound
+	}
+	return item, nil
+}
+
+// Put menyimpan atau menimpa record dengan ID tertentu.
+func (c *Collection[T]) Put(id string, item T) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[id] = item
+	return c.flush()
+}
+
+// Update menjalankan fn terhadap record yang ada lalu menyimpannya.
+// Jika fn mengembalikan error, perubahan dibatalkan.
+func (c *Collection[T]) Update(id string, fn func(item *T) error) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, ok := c.items[id]
+	if !ok {
+		var zero T
+		return zero, ErrNotFound
+	}
+	if err := fn(&item); err != nil {
+		var zero T
+		return zero, err
+	}
+	c.items[id] = item
+	return item, c.flush()
+}
+
+// Delete menghapus record. Tidak error jika record sudah tidak ada.
+func (c *Collection[T]) Delete(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[id]; !ok {
+		return nil
+	}
+	delete(c.items, id)
+	return c.flush()
+}
+
+// List mengembalikan semua record yang lolos filter, diurutkan berdasarkan ID.
+// filter nil berarti semua record.
+func (c *Collection[T]) List(filter func(item T) bool) []T {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids := make([]string, 0, len(c.items))
+	for id, item := range c.items {
+		if filter == nil || filter(item) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	out := make([]T, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, c.items[id])
+	}
+	return out
+}
+
+// flush menulis isi collection ke file secara atomik. Dipanggil dengan lock.
+func (c *Collection[T]) flush() error {
+	if c.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(c.items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("gagal encode %s: %w", c.path, err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("gagal menyimpan %s: %w", c.path, err)
+	}
+	return nil
+}
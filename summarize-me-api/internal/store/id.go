@@ -0,0 +1,15 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewID membuat ID acak 16 byte dalam bentuk hex.
+func NewID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand tidak seharusnya gagal
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,80 @@
+package transcript
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDocumentXML membatasi ukuran word/document.xml yang dibaca.
+const maxDocumentXML = 50 << 20
+
+// parseDOCX mengambil teks dari word/document.xml; setiap paragraf Word
+// menjadi satu baris.
+func parseDOCX(data []byte) (string, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("file DOCX tidak bisa dibaca: %w", err)
+	}
+	var doc *zip.File
+	for _, f := range r.File {
+		if f.Name == "word/document.xml" {
+			doc = f
+			break
+		}
+	}
+	if doc == nil {
+		return "", fmt.Errorf("file DOCX tidak berisi word/document.xml")
+	}
+	rc, err := doc.Open()
+	if err != nil {
+		return "", fmt.Errorf("file DOCX tidak bisa dibaca: %w", err)
+	}
+	defer rc.Close()
+
+	var (
+		b         strings.Builder
+		paragraph strings.Builder
+		inText    bool
+	)
+	dec := xml.NewDecoder(io.LimitReader(rc, maxDocumentXML))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("isi DOCX tidak valid: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				paragraph.WriteString("\t")
+			case "br", "cr":
+				paragraph.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if line := strings.TrimSpace(paragraph.String()); line != "" {
+					b.WriteString(line)
+					b.WriteString("\n")
+				}
+				paragraph.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,75 @@
+package transcript
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+const wordNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+// buildDOCX membuat arsip zip minimal berisi files (nama -> isi).
+func buildDOCX(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseDOCX(t *testing.T) {
+	data := buildDOCX(t, map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0"?><Types/>`,
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document ` + wordNS + `><w:body>
+<w:p><w:r><w:t>Notulen rapat</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Budi: </w:t></w:r><w:r><w:t>halo</w:t><w:tab/><w:t>semua</w:t></w:r></w:p>
+<w:p><w:pPr><w:pStyle w:val="Kosong"/></w:pPr></w:p>
+<w:p><w:r><w:t>baris</w:t><w:br/><w:t>baru</w:t></w:r></w:p>
+<w:p><w:r><w:instrText>PAGE</w:instrText><w:t>&amp; selesai</w:t></w:r></w:p>
+</w:body></w:document>`,
+	})
+
+	got, err := Parse(data, "Notulen.DOCX")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := "Notulen rapat\nBudi: halo\tsemua\nbaris\nbaru\n& selesai"
+	if got != want {
+		t.Errorf("Parse =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestParseDOCXErrors(t *testing.T) {
+	valid := buildDOCX(t, map[string]string{"word/document.xml": `<w:document ` + wordNS + `><w:body><w:p><w:r><w:t>isi</w:t></w:r></w:p></w:body></w:document>`})
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"bukan zip", []byte("ini dokumen teks biasa")},
+		{"header zip saja", []byte("PK\x03\x04\x14\x00\x00\x00")},
+		{"arsip terpotong", valid[:len(valid)/2]},
+		{"tanpa document.xml", buildDOCX(t, map[string]string{"word/styles.xml": "<w:styles/>"})},
+		{"xml rusak", buildDOCX(t, map[string]string{"word/document.xml": `<w:document ` + wordNS + `><w:p><w:t>isi</w:p>`})},
+		{"kosong", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.data, "rapat.docx"); err == nil {
+				t.Error("Parse seharusnya error")
+			}
+		})
+	}
+}
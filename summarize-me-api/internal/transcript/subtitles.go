@@ -0,0 +1,71 @@
+package transcript
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// timingPattern cocok untuk "00:01:02.345 --> ..." (VTT) dan
+	// "00:01:02,345 --> ..." (SRT); jam boleh tidak ada di VTT.
+	timingPattern = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s+-->\s+`)
+	voicePattern  = regexp.MustCompile(`^<v(?:\.[^ >]*)?\s+([^>]+)>`)
+	tagPattern    = regexp.MustCompile(`</?[^>]+>`)
+	// namePattern mengenali "Nama: teks" seperti di transkrip Zoom.
+	namePattern = regexp.MustCompile(`^([\p{L}][\p{L}\p{N} .'\-]{0,40}):\s+(.+)$`)
+)
+
+// parseCues membaca blok subtitle. Nama pembicara diambil dari tag <v Nama>
+// (Teams) atau awalan "Nama:" (Zoom). Blok tanpa timing (header WEBVTT,
+// NOTE, STYLE) dilewati.
+func parseCues(text string, vtt bool) []cue {
+	var cues []cue
+	for _, block := range strings.Split(text, "\n\n") {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		timing := -1
+		for i, line := range lines {
+			if timingPattern.MatchString(strings.TrimSpace(line)) {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 || timing == len(lines)-1 {
+			continue
+		}
+
+		start := parseTimestamp(timingPattern.FindStringSubmatch(strings.TrimSpace(lines[timing]))[1])
+		body := strings.Join(lines[timing+1:], " ")
+
+		speaker := ""
+		if vtt {
+			if m := voicePattern.FindStringSubmatch(body); m != nil {
+				speaker = strings.TrimSpace(m[1])
+			}
+		}
+		body = strings.TrimSpace(tagPattern.ReplaceAllString(body, ""))
+		if speaker == "" {
+			if m := namePattern.FindStringSubmatch(body); m != nil {
+				speaker, body = strings.TrimSpace(m[1]), m[2]
+			}
+		}
+		if body == "" {
+			continue
+		}
+		cues = append(cues, cue{Start: start, Speaker: speaker, Text: body})
+	}
+	return cues
+}
+
+// parseTimestamp membaca "hh:mm:ss.mmm", "mm:ss.mmm" atau versi koma (SRT).
+func parseTimestamp(raw string) time.Duration {
+	raw = strings.Replace(raw, ",", ".", 1)
+	parts := strings.Split(raw, ":")
+	var total float64
+	for _, p := range parts {
+		v, _ := strconv.ParseFloat(p, 64)
+		total = total*60 + v
+	}
+	return time.Duration(total * float64(time.Second))
+}
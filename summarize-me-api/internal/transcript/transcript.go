@@ -0,0 +1,119 @@
+// Package transcript membaca transkrip yang sudah ada (teks biasa, subtitle
+// VTT/SRT, dokumen DOCX) menjadi teks yang siap diringkas, tanpa
+// Speech-to-Text.
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Extensions adalah format transkrip yang didukung.
+var Extensions = []string{".txt", ".vtt", ".srt", ".docx"}
+
+// ErrEmpty dikembalikan jika file tidak berisi teks.
+var ErrEmpty = errors.New("transkrip kosong")
+
+// paragraphSpan membatasi panjang paragraf untuk cue tanpa nama pembicara.
+const paragraphSpan = time.Minute
+
+// IsTranscriptFile mengecek ekstensi file terhadap Extensions.
+func IsTranscriptFile(fileName string) bool {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, e := range Extensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse mengubah isi file menjadi teks transkrip. Untuk subtitle, nama
+// pembicara dan timestamp dipertahankan dalam bentuk "[00:01:23] Nama: teks".
+func Parse(data []byte, fileName string) (string, error) {
+	var (
+		text string
+		err  error
+	)
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".vtt":
+		text = render(parseCues(decodeText(data), true))
+	case ".srt":
+		text = render(parseCues(decodeText(data), false))
+	case ".docx":
+		text, err = parseDOCX(data)
+	default:
+		text = decodeText(data)
+	}
+	if err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmpty
+	}
+	return text, nil
+}
+
+// decodeText menormalkan baris baru dan membuang BOM. Byte yang bukan UTF-8
+// diganti agar tidak merusak request ke Gemini.
+func decodeText(data []byte) string {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "\ufffd")
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\r", "\n")
+}
+
+// cue adalah satu potongan subtitle.
+type cue struct {
+	Start   time.Duration
+	Speaker string
+	Text    string
+}
+
+// render menggabungkan cue berurutan dari pembicara yang sama menjadi satu
+// paragraf yang diawali timestamp cue pertamanya.
+func render(cues []cue) string {
+	var (
+		b       strings.Builder
+		current *cue
+		parts   []string
+	)
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "[%s] ", formatTimestamp(current.Start))
+		if current.Speaker != "" {
+			b.WriteString(current.Speaker + ": ")
+		}
+		b.WriteString(strings.Join(parts, " "))
+		current, parts = nil, nil
+	}
+
+	for i := range cues {
+		c := cues[i]
+		sameSpeaker := current != nil && c.Speaker == current.Speaker
+		if !sameSpeaker || (c.Speaker == "" && c.Start-current.Start > paragraphSpan) {
+			flush()
+			current = &c
+		}
+		parts = append(parts, c.Text)
+	}
+	flush()
+	return b.String()
+}
+
+func formatTimestamp(d time.Duration) string {
+	s := int(d.Seconds())
+	return fmt.Sprintf("%02d:%02d:%02d", s/3600, s/60%60, s%60)
+}
@@ -0,0 +1,132 @@
+package transcript
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     string
+		want     string
+	}{
+		{
+			name:     "vtt teams dengan tag voice dan cue multi-baris",
+			fileName: "rapat.vtt",
+			data: "WEBVTT\n\nNOTE dibuat oleh Teams\n\n" +
+				"1\n00:00:01.000 --> 00:00:04.000\n<v Budi Santoso>Selamat pagi semua.</v>\n\n" +
+				"2\n00:00:05.500 --> 00:00:08.000 align:start\n<v Budi Santoso>Kita mulai rapatnya.</v>\n\n" +
+				"00:01:02.000 --> 00:01:05.000\n<v.loud Siti>Baik, saya\nlanjutkan laporan.</v>\n",
+			want: "[00:00:01] Budi Santoso: Selamat pagi semua. Kita mulai rapatnya.\n\n" +
+				"[00:01:02] Siti: Baik, saya lanjutkan laporan.",
+		},
+		{
+			name:     "vtt tanpa jam dengan nama gaya zoom",
+			fileName: "zoom.VTT",
+			data:     "WEBVTT\n\n01:02.500 --> 01:05.000\nAndi: Ada pertanyaan?\n\n01:06.000 --> 01:08.000\n<b>Rina</b>: Tidak ada.\n",
+			want:     "[00:01:02] Andi: Ada pertanyaan?\n\n[00:01:06] Rina: Tidak ada.",
+		},
+		{
+			name:     "srt dengan BOM dan CRLF",
+			fileName: "rapat.srt",
+			data: "\ufeff1\r\n00:00:01,000 --> 00:00:03,000\r\nAndi: Halo\r\n\r\n" +
+				"2\r\n00:00:03,500 --> 00:00:05,000\r\nAndi: apa kabar\r\n\r\n" +
+				"3\r\n01:00:00,000 --> 01:00:02,000\r\nbaris satu\r\nbaris dua\r\n\r\n" +
+				"4\r\n01:00:30,000 --> 01:00:32,000\r\nlanjut\r\n\r\n" +
+				"5\r\n01:02:00,000 --> 01:02:02,000\r\nbagian baru\r\n",
+			want: "[00:00:01] Andi: Halo apa kabar\n\n" +
+				"[01:00:00] baris satu baris dua lanjut\n\n" +
+				"[01:02:00] bagian baru",
+		},
+		{
+			name:     "cue tanpa isi dilewati",
+			fileName: "rapat.srt",
+			data:     "1\n00:00:01,000 --> 00:00:02,000\n\n2\n00:00:03,000 --> 00:00:04,000\n<i></i>\n\n3\n00:00:05,000 --> 00:00:06,000\nSatu-satunya\n",
+			want:     "[00:00:05] Satu-satunya",
+		},
+		{
+			name:     "teks biasa dengan BOM, CR dan byte bukan UTF-8",
+			fileName: "catatan.txt",
+			data:     "\ufeffbaris 1\r\nbaris 2\rbaris 3\xff\n",
+			want:     "baris 1\nbaris 2\nbaris 3\ufffd",
+		},
+		{
+			name:     "ekstensi lain dianggap teks",
+			fileName: "catatan.md",
+			data:     "  # Rapat\n",
+			want:     "# Rapat",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data), tt.fileName)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	tests := []struct {
+		fileName string
+		data     string
+	}{
+		{"kosong.txt", ""},
+		{"bom.txt", "\ufeff \r\n\t"},
+		{"header.vtt", "WEBVTT\n\nNOTE tidak ada cue\n"},
+		{"tanpa-isi.srt", "1\n00:00:01,000 --> 00:00:02,000\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data), tt.fileName); !errors.Is(err, ErrEmpty) {
+				t.Errorf("Parse error = %v, want ErrEmpty", err)
+			}
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want time.Duration
+	}{
+		{"00:01:02.500", time.Minute + 2500*time.Millisecond},
+		{"00:01:02,250", time.Minute + 2250*time.Millisecond},
+		{"01:02.500", time.Minute + 2500*time.Millisecond},
+		{"1:00:00.000", time.Hour},
+		{"12:34:56,000", 12*time.Hour + 34*time.Minute + 56*time.Second},
+	}
+	for _, tt := range tests {
+		if got := parseTimestamp(tt.raw); got != tt.want {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestIsTranscriptFile(t *testing.T) {
+	tests := []struct {
+		fileName string
+		want     bool
+	}{
+		{"rapat.txt", true},
+		{"Rapat.VTT", true},
+		{"rapat.en.srt", true},
+		{"notulen.docx", true},
+		{"rapat.mp3", false},
+		{"notulen.doc", false},
+		{"vtt", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsTranscriptFile(tt.fileName); got != tt.want {
+			t.Errorf("IsTranscriptFile(%q) = %v, want %v", tt.fileName, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,397 @@
+// Package vector menyediakan index vektor in-process berbasis HNSW
+// (Hierarchical Navigable Small World) untuk pencarian kemiripan kosinus.
+package vector
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// ErrDimension dikembalikan jika dimensi vektor tidak sama dengan vektor
+// pertama yang dimasukkan ke index.
+var ErrDimension = errors.New("dimensi vektor tidak cocok")
+
+// compactRatio adalah porsi tombstone dari seluruh node yang memicu
+// pembangunan ulang graf. Tombstone tetap dilewati saat pencarian, jadi
+// terlalu banyak tombstone memperlambat query dan menurunkan recall.
+const compactRatio = 0.3
+
+// Options mengatur parameter graf HNSW. Nilai nol memakai default.
+type Options struct {
+	// M adalah jumlah tetangga per node di layer atas (default 16); layer 0
+	// memakai 2*M.
+	M int
+	// EfConstruction adalah lebar pencarian saat menyisipkan (default 200).
+	EfConstruction int
+	// EfSearch adalah lebar pencarian minimum saat query (default 64).
+	EfSearch int
+}
+
+// Result adalah satu hasil pencarian. Score adalah kemiripan kosinus
+// (1 berarti identik).
+type Result struct {
+	ID    string
+	Score float32
+}
+
+type node struct {
+	id      string
+	vec     []float32
+	links   [][]int32
+	deleted bool
+}
+
+// Index adalah graf HNSW yang aman dipakai bersamaan dari beberapa goroutine.
+// Penghapusan (termasuk id yang diganti lewat Add) memakai tombstone: node
+// tetap dipakai untuk navigasi tetapi tidak muncul di hasil. Setelah
+// tombstone melewati compactRatio, graf dibangun ulang dari node aktif.
+type Index struct {
+	mu             sync.RWMutex
+	m, m0          int
+	efConstruction int
+	efSearch       int
+	levelMult      float64
+	rng            *rand.Rand
+
+	nodes    []node
+	ids      map[string]int32
+	entry    int32
+	maxLevel int
+	dim      int
+	live     int
+}
+
+// New membuat index kosong.
+func New(opts Options) *Index {
+	if opts.M <= 1 {
+		opts.M = 16
+	}
+	if opts.EfConstruction <= 0 {
+		opts.EfConstruction = 200
+	}
+	if opts.EfSearch <= 0 {
+		opts.EfSearch = 64
+	}
+	return &Index{
+		m:              opts.M,
+		m0:             2 * opts.M,
+		efConstruction: opts.EfConstruction,
+		efSearch:       opts.EfSearch,
+		levelMult:      1 / math.Log(float64(opts.M)),
+		rng:            rand.New(rand.NewSource(1)),
+		ids:            make(map[string]int32),
+		entry:          -1,
+	}
+}
+
+// Len mengembalikan jumlah vektor aktif di index.
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.live
+}
+
+// Add menyisipkan vektor dengan id tertentu. Id yang sudah ada diganti.
+func (x *Index) Add(id string, vec []float32) error {
+	q := normalize(vec)
+	if q == nil {
+		return fmt.Errorf("vektor %s kosong atau nol", id)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if x.dim == 0 {
+		x.dim = len(q)
+	} else if len(q) != x.dim {
+		return fmt.Errorf("%w: %d, index berdimensi %d", ErrDimension, len(q), x.dim)
+	}
+	if old, ok := x.ids[id]; ok {
+		x.nodes[old].deleted = true
+		x.live--
+	}
+	x.insert(id, q)
+	x.compactIfNeeded()
+	return nil
+}
+
+// insert menyisipkan vektor q yang sudah dinormalisasi. Pemanggil harus
+// memegang x.mu untuk ditulis.
+func (x *Index) insert(id string, q []float32) {
+	level := int(-math.Log(1-x.rng.Float64()) * x.levelMult)
+	idx := int32(len(x.nodes))
+	x.nodes = append(x.nodes, node{id: id, vec: q, links: make([][]int32, level+1)})
+	x.ids[id] = idx
+	x.live++
+
+	if x.entry < 0 {
+		x.entry = idx
+		x.maxLevel = level
+		return
+	}
+
+	ep := []int32{x.entry}
+	for l := x.maxLevel; l > level; l-- {
+		ep = []int32{x.searchLayer(q, ep, 1, l)[0].idx}
+	}
+	for l := min(level, x.maxLevel); l >= 0; l-- {
+		found := x.searchLayer(q, ep, x.efConstruction, l)
+		neighbors := x.selectNeighbors(found, x.maxLinks(l))
+		x.nodes[idx].links[l] = neighbors
+		for _, n := range neighbors {
+			x.connect(n, idx, l)
+		}
+		ep = ep[:0]
+		for _, c := range found {
+			ep = append(ep, c.idx)
+		}
+	}
+	if level > x.maxLevel {
+		x.entry = idx
+		x.maxLevel = level
+	}
+}
+
+// Remove menandai vektor id sebagai terhapus. Id yang tidak ada diabaikan.
+func (x *Index) Remove(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if idx, ok := x.ids[id]; ok {
+		x.nodes[idx].deleted = true
+		delete(x.ids, id)
+		x.live--
+		x.compactIfNeeded()
+	}
+}
+
+// compactIfNeeded membangun ulang graf dari node aktif jika tombstone
+// melebihi compactRatio. Biayanya sebanding dengan jumlah node aktif, dan
+// baru terjadi lagi setelah tombstone baru menumpuk, jadi rata-rata per
+// Add/Remove tetap kecil. Pemanggil harus memegang x.mu untuk ditulis.
+func (x *Index) compactIfNeeded() {
+	deleted := len(x.nodes) - x.live
+	if deleted == 0 || float64(deleted) < compactRatio*float64(len(x.nodes)) {
+		return
+	}
+	old := x.nodes
+	x.nodes = make([]node, 0, x.live)
+	x.ids = make(map[string]int32, x.live)
+	x.entry = -1
+	x.maxLevel = 0
+	x.live = 0
+	for _, n := range old {
+		if !n.deleted {
+			x.insert(n.id, n.vec)
+		}
+	}
+}
+
+// Search mengembalikan maksimal k vektor yang paling mirip dengan vec,
+// terurut dari skor tertinggi.
+func (x *Index) Search(vec []float32, k int) []Result {
+	q := normalize(vec)
+	if q == nil || k <= 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	if x.entry < 0 || len(q) != x.dim {
+		return nil
+	}
+
+	ep := []int32{x.entry}
+	for l := x.maxLevel; l > 0; l-- {
+		ep = []int32{x.searchLayer(q, ep, 1, l)[0].idx}
+	}
+	ef := max(x.efSearch, k)
+	if deleted := len(x.nodes) - x.live; deleted > 0 {
+		ef += min(deleted, ef)
+	}
+
+	out := make([]Result, 0, k)
+	for _, c := range x.searchLayer(q, ep, ef, 0) {
+		n := x.nodes[c.idx]
+		if n.deleted {
+			continue
+		}
+		out = append(out, Result{ID: n.id, Score: 1 - c.dist})
+		if len(out) == k {
+			break
+		}
+	}
+	return out
+}
+
+func (x *Index) maxLinks(level int) int {
+	if level == 0 {
+		return x.m0
+	}
+	return x.m
+}
+
+// connect menambahkan sisi from->to di layer level, lalu memangkas
+// tetangga from dengan selectNeighbors jika melebihi batas.
+func (x *Index) connect(from, to int32, level int) {
+	links := append(x.nodes[from].links[level], to)
+	limit := x.maxLinks(level)
+	if len(links) > limit {
+		base := x.nodes[from].vec
+		cands := make([]candidate, len(links))
+		for i, n := range links {
+			cands[i] = candidate{idx: n, dist: distance(base, x.nodes[n].vec)}
+		}
+		sort.Slice(cands, func(i, j int) bool { return cands[i].dist < cands[j].dist })
+		links = x.selectNeighbors(cands, limit)
+	}
+	x.nodes[from].links[level] = links
+}
+
+// selectNeighbors memilih maksimal limit tetangga dari cands (terurut dari
+// jarak terdekat ke node asal) dengan heuristik HNSW: kandidat dilewati
+// jika lebih dekat ke tetangga yang sudah terpilih daripada ke node asal.
+// Sisi jadi menyebar ke beberapa arah, sehingga node di pinggiran tidak
+// terputus dari graf. Slot yang tersisa diisi kandidat yang dilewati.
+func (x *Index) selectNeighbors(cands []candidate, limit int) []int32 {
+	out := make([]int32, 0, limit)
+	var skipped []int32
+	for _, c := range cands {
+		if len(out) == limit {
+			break
+		}
+		vec := x.nodes[c.idx].vec
+		diverse := true
+		for _, r := range out {
+			if distance(vec, x.nodes[r].vec) < c.dist {
+				diverse = false
+				break
+			}
+		}
+		if diverse {
+			out = append(out, c.idx)
+		} else {
+			skipped = append(skipped, c.idx)
+		}
+	}
+	for _, n := range skipped {
+		if len(out) == limit {
+			break
+		}
+		out = append(out, n)
+	}
+	return out
+}
+
+type candidate struct {
+	idx  int32
+	dist float32
+}
+
+// searchLayer adalah pencarian greedy beam di satu layer. Hasil terurut
+// dari jarak terdekat.
+func (x *Index) searchLayer(q []float32, entry []int32, ef, level int) []candidate {
+	visited := make(map[int32]bool, ef*4)
+	cands := &minHeap{}
+	results := &maxHeap{}
+	for _, e := range entry {
+		if visited[e] {
+			continue
+		}
+		visited[e] = true
+		c := candidate{idx: e, dist: distance(q, x.nodes[e].vec)}
+		heap.Push(cands, c)
+		heap.Push(results, c)
+	}
+	for results.Len() > ef {
+		heap.Pop(results)
+	}
+
+	for cands.Len() > 0 {
+		c := heap.Pop(cands).(candidate)
+		if results.Len() >= ef && c.dist > (*results)[0].dist {
+			break
+		}
+		links := x.nodes[c.idx].links
+		if level >= len(links) {
+			continue
+		}
+		for _, n := range links[level] {
+			if visited[n] {
+				continue
+			}
+			visited[n] = true
+			d := distance(q, x.nodes[n].vec)
+			if results.Len() < ef || d < (*results)[0].dist {
+				heap.Push(cands, candidate{idx: n, dist: d})
+				heap.Push(results, candidate{idx: n, dist: d})
+				if results.Len() > ef {
+					heap.Pop(results)
+				}
+			}
+		}
+	}
+
+	out := make([]candidate, results.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(results).(candidate)
+	}
+	return out
+}
+
+// distance adalah 1 - kemiripan kosinus untuk vektor yang sudah dinormalisasi.
+func distance(a, b []float32) float32 {
+	var dot float32
+	for i := range a {
+		dot += a[i] * b[i]
+	}
+	return 1 - dot
+}
+
+// normalize mengembalikan salinan vec dengan panjang 1, atau nil jika vec
+// kosong atau vektor nol.
+func normalize(vec []float32) []float32 {
+	var sum float64
+	for _, v := range vec {
+		sum += float64(v) * float64(v)
+	}
+	if len(vec) == 0 || sum == 0 {
+		return nil
+	}
+	norm := float32(1 / math.Sqrt(sum))
+	out := make([]float32, len(vec))
+	for i, v := range vec {
+		out[i] = v * norm
+	}
+	return out
+}
+
+type minHeap []candidate
+
+func (h minHeap) Len() int           { return len(h) }
+func (h minHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h minHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *minHeap) Push(v any)        { *h = append(*h, v.(candidate)) }
+func (h *minHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+type maxHeap []candidate
+
+func (h maxHeap) Len() int           { return len(h) }
+func (h maxHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h maxHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *maxHeap) Push(v any)        { *h = append(*h, v.(candidate)) }
+func (h *maxHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
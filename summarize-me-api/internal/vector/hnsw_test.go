@@ -0,0 +1,283 @@
+package vector
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randomVectors(rng *rand.Rand, n, dim int) [][]float32 {
+	vecs := make([][]float32, n)
+	for i := range vecs {
+		v := make([]float32, dim)
+		for j := range v {
+			v[j] = float32(rng.NormFloat64())
+		}
+		vecs[i] = v
+	}
+	return vecs
+}
+
+// bruteForce mengembalikan id k vektor aktif paling mirip dengan q.
+func bruteForce(vecs map[string][]float32, q []float32, k int) []string {
+	nq := normalize(q)
+	type scored struct {
+		id   string
+		dist float32
+	}
+	all := make([]scored, 0, len(vecs))
+	for id, v := range vecs {
+		all = append(all, scored{id, distance(nq, normalize(v))})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].dist < all[j].dist })
+	out := make([]string, 0, k)
+	for _, s := range all[:min(k, len(all))] {
+		out = append(out, s.id)
+	}
+	return out
+}
+
+// recall mengukur rata-rata porsi hasil brute force yang ditemukan Search.
+func recall(t *testing.T, x *Index, vecs map[string][]float32, queries [][]float32, k int) float64 {
+	t.Helper()
+	found, total := 0, 0
+	for _, q := range queries {
+		got := make(map[string]bool)
+		for _, r := range x.Search(q, k) {
+			if _, ok := vecs[r.ID]; !ok {
+				t.Fatalf("Search mengembalikan id %s yang tidak aktif", r.ID)
+			}
+			got[r.ID] = true
+		}
+		for _, id := range bruteForce(vecs, q, k) {
+			total++
+			if got[id] {
+				found++
+			}
+		}
+	}
+	return float64(found) / float64(total)
+}
+
+func TestSearchRecall(t *testing.T) {
+	tests := []struct {
+		name    string
+		n, dim  int
+		opts    Options
+		minRate float64
+	}{
+		{"default", 2000, 32, Options{}, 0.95},
+		{"M kecil", 1000, 16, Options{M: 4, EfConstruction: 50, EfSearch: 32}, 0.85},
+		{"lebih sedikit dari k", 5, 8, Options{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(42))
+			x := New(tt.opts)
+			vecs := make(map[string][]float32, tt.n)
+			for i, v := range randomVectors(rng, tt.n, tt.dim) {
+				id := fmt.Sprintf("v%d", i)
+				vecs[id] = v
+				if err := x.Add(id, v); err != nil {
+					t.Fatalf("Add: %v", err)
+				}
+			}
+			if x.Len() != tt.n {
+				t.Fatalf("Len = %d, want %d", x.Len(), tt.n)
+			}
+			if got := recall(t, x, vecs, randomVectors(rng, 50, tt.dim), 10); got < tt.minRate {
+				t.Errorf("recall@10 = %.3f, want >= %.2f", got, tt.minRate)
+			}
+		})
+	}
+}
+
+func TestSearchOrderAndScore(t *testing.T) {
+	x := New(Options{})
+	for id, v := range map[string][]float32{
+		"sama":       {1, 0, 0},
+		"dekat":      {1, 0.2, 0},
+		"tegak":      {0, 1, 0},
+		"berlawan":   {-1, 0, 0},
+		"skala-beda": {10, 0, 0.1},
+	} {
+		if err := x.Add(id, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := x.Search([]float32{2, 0, 0}, 5)
+	if len(got) != 5 {
+		t.Fatalf("Search = %v, want 5 hasil", got)
+	}
+	if got[0].ID != "sama" || got[0].Score < 0.999 {
+		t.Errorf("hasil pertama = %+v, want sama dengan skor ~1", got[0])
+	}
+	if last := got[len(got)-1]; last.ID != "berlawan" || last.Score > -0.999 {
+		t.Errorf("hasil terakhir = %+v, want berlawan dengan skor ~-1", last)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Score > got[i-1].Score {
+			t.Errorf("hasil tidak terurut: %v", got)
+		}
+	}
+}
+
+func TestAddReplacesExistingID(t *testing.T) {
+	x := New(Options{})
+	for _, id := range []string{"a", "b", "c"} {
+		if err := x.Add(id, []float32{0, 0, 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := x.Add("a", []float32{1, 0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if x.Len() != 3 {
+		t.Errorf("Len = %d, want 3", x.Len())
+	}
+
+	got := x.Search([]float32{1, 0, 0}, 3)
+	if len(got) == 0 || got[0].ID != "a" || got[0].Score < 0.999 {
+		t.Fatalf("Search vektor baru = %v, want a di urutan pertama", got)
+	}
+	seen := make(map[string]int)
+	for _, r := range x.Search([]float32{0, 0, 1}, 10) {
+		seen[r.ID]++
+	}
+	if seen["a"] != 1 {
+		t.Errorf("id a muncul %d kali, want 1 (vektor lama tidak boleh ikut)", seen["a"])
+	}
+}
+
+func TestRemoveThenSearch(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	x := New(Options{})
+	vecs := make(map[string][]float32)
+	for i, v := range randomVectors(rng, 500, 16) {
+		id := fmt.Sprintf("v%d", i)
+		vecs[id] = v
+		if err := x.Add(id, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Hapus di bawah ambang compaction dulu, lalu melewatinya.
+	for _, n := range []int{100, 250} {
+		for i := 0; len(vecs) > 500-n; i++ {
+			id := fmt.Sprintf("v%d", i)
+			x.Remove(id)
+			delete(vecs, id)
+		}
+		x.Remove("tidak-ada")
+		if x.Len() != len(vecs) {
+			t.Fatalf("Len = %d, want %d", x.Len(), len(vecs))
+		}
+		// Vektor yang dihapus tidak boleh muncul meski dicari persis.
+		for i := 0; i < 10; i++ {
+			id := fmt.Sprintf("v%d", i)
+			for _, r := range x.Search(randomVectors(rng, 1, 16)[0], 20) {
+				if r.ID == id {
+					t.Fatalf("id %s yang dihapus muncul di hasil", id)
+				}
+			}
+		}
+		if got := recall(t, x, vecs, randomVectors(rng, 30, 16), 10); got < 0.9 {
+			t.Errorf("recall@10 setelah %d dihapus = %.3f", n, got)
+		}
+	}
+
+	for id := range vecs {
+		x.Remove(id)
+	}
+	if x.Len() != 0 || len(x.Search([]float32{1}, 1)) != 0 {
+		t.Errorf("index seharusnya kosong: Len %d", x.Len())
+	}
+	if err := x.Add("baru", make16(1)); err != nil {
+		t.Fatalf("Add setelah semua dihapus: %v", err)
+	}
+	if got := x.Search(make16(1), 1); len(got) != 1 || got[0].ID != "baru" {
+		t.Errorf("Search = %v", got)
+	}
+}
+
+func make16(v float32) []float32 {
+	out := make([]float32, 16)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func TestCompaction(t *testing.T) {
+	x := New(Options{})
+	for i := 0; i < 100; i++ {
+		if err := x.Add(fmt.Sprintf("v%d", i), []float32{float32(i + 1), 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Mengganti id yang sama berulang kali tidak boleh menumpuk node lama.
+	for i := 0; i < 1000; i++ {
+		if err := x.Add("v0", []float32{1, float32(i + 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 50; i < 100; i++ {
+		x.Remove(fmt.Sprintf("v%d", i))
+	}
+
+	x.mu.RLock()
+	nodes, live := len(x.nodes), x.live
+	x.mu.RUnlock()
+	if live != 50 {
+		t.Errorf("live = %d, want 50", live)
+	}
+	if deleted := nodes - live; float64(deleted) >= compactRatio*float64(nodes) {
+		t.Errorf("%d tombstone dari %d node, want di bawah %.0f%%", deleted, nodes, compactRatio*100)
+	}
+	if got := x.Search([]float32{1, 1000}, 1); len(got) != 1 || got[0].ID != "v0" {
+		t.Errorf("Search = %v, want v0", got)
+	}
+}
+
+func TestDimensionAndInvalidVectors(t *testing.T) {
+	x := New(Options{})
+	if err := x.Add("a", []float32{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		vec     []float32
+		wantDim bool
+	}{
+		{"dimensi lebih kecil", []float32{1, 2}, true},
+		{"dimensi lebih besar", []float32{1, 2, 3, 4}, true},
+		{"vektor nol", []float32{0, 0, 0}, false},
+		{"vektor kosong", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := x.Add("b", tt.vec)
+			if err == nil {
+				t.Fatal("Add seharusnya error")
+			}
+			if errors.Is(err, ErrDimension) != tt.wantDim {
+				t.Errorf("Add error = %v, ErrDimension %v", err, tt.wantDim)
+			}
+			if got := x.Search(tt.vec, 1); got != nil {
+				t.Errorf("Search = %v, want nil", got)
+			}
+		})
+	}
+	if x.Len() != 1 {
+		t.Errorf("Len = %d, want 1 setelah Add yang gagal", x.Len())
+	}
+	if got := x.Search([]float32{1, 2, 3}, 0); got != nil {
+		t.Errorf("Search k=0 = %v, want nil", got)
+	}
+	if got := New(Options{}).Search([]float32{1}, 3); got != nil {
+		t.Errorf("Search index kosong = %v, want nil", got)
+	}
+}
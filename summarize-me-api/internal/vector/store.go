@@ -0,0 +1,195 @@
+package vector
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound dikembalikan Store.Get jika dokumen tidak punya vektor.
+var ErrNotFound = errors.New("vektor tidak ditemukan")
+
+// storeMagic mengawali setiap file vektor; angka di akhir adalah versi format.
+const storeMagic = "VEC1"
+
+// maxStoreDim membatasi dimensi yang dibaca dari file, agar header yang
+// rusak tidak memicu alokasi raksasa.
+const maxStoreDim = 1 << 16
+
+// Store menyimpan vektor per dokumen dalam file biner terpisah di satu
+// direktori. Menyimpan satu dokumen hanya menulis file miliknya, tidak
+// seperti store.Collection yang menulis ulang semua record.
+//
+// Format file: magic "VEC1", jumlah vektor dan dimensi (uint32), lalu semua
+// nilai float32, semuanya little-endian. Jika dir kosong, Store hanya hidup
+// di memori.
+type Store struct {
+	dir string
+
+	mu  sync.RWMutex
+	mem map[string][][]float32
+}
+
+// OpenStore membuka (atau membuat) direktori vektor dir.
+func OpenStore(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	if dir == "" {
+		s.mem = make(map[string][][]float32)
+		return s, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("gagal membuat direktori vektor %s: %w", dir, err)
+	}
+	return s, nil
+}
+
+// Put menyimpan atau menimpa semua vektor milik dokumen id. Semua vektor
+// harus berdimensi sama.
+func (s *Store) Put(id string, vecs [][]float32) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	dim := 0
+	if len(vecs) > 0 {
+		dim = len(vecs[0])
+	}
+	for _, v := range vecs {
+		if len(v) != dim {
+			return fmt.Errorf("%w: %d dan %d dalam dokumen %s", ErrDimension, dim, len(v), id)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mem != nil {
+		s.mem[id] = vecs
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", tmp, err)
+	}
+	w := bufio.NewWriter(f)
+	w.WriteString(storeMagic)
+	header := [8]byte{}
+	binary.LittleEndian.PutUint32(header[:4], uint32(len(vecs)))
+	binary.LittleEndian.PutUint32(header[4:], uint32(dim))
+	w.Write(header[:])
+	buf := [4]byte{}
+	for _, v := range vecs {
+		for _, val := range v {
+			binary.LittleEndian.PutUint32(buf[:], math.Float32bits(val))
+			w.Write(buf[:])
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("gagal menulis %s: %w", tmp, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("gagal menulis %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("gagal menyimpan %s: %w", path, err)
+	}
+	return nil
+}
+
+// Get membaca semua vektor milik dokumen id.
+func (s *Store) Get(id string) ([][]float32, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.mem != nil {
+		vecs, ok := s.mem[id]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return vecs, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca %s: %w", path, err)
+	}
+	defer f.Close()
+	vecs, err := readVectors(bufio.NewReader(f))
+	if err != nil {
+		return nil, fmt.Errorf("file vektor %s rusak: %w", path, err)
+	}
+	return vecs, nil
+}
+
+// Delete menghapus vektor dokumen id. Tidak error jika sudah tidak ada.
+func (s *Store) Delete(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mem != nil {
+		delete(s.mem, id)
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("gagal menghapus %s: %w", path, err)
+	}
+	return nil
+}
+
+// path mengembalikan lokasi file vektor id. Id dipakai langsung sebagai
+// nama file, jadi id yang bisa keluar dari dir ditolak.
+func (s *Store) path(id string) (string, error) {
+	if id == "" || id == "." || id == ".." || strings.ContainsAny(id, `/\`) {
+		return "", fmt.Errorf("id dokumen vektor tidak valid: %q", id)
+	}
+	return filepath.Join(s.dir, id+".vec"), nil
+}
+
+func readVectors(r io.Reader) ([][]float32, error) {
+	header := make([]byte, len(storeMagic)+8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:len(storeMagic)]) != storeMagic {
+		return nil, errors.New("format tidak dikenal")
+	}
+	count := binary.LittleEndian.Uint32(header[len(storeMagic):])
+	dim := binary.LittleEndian.Uint32(header[len(storeMagic)+4:])
+	if dim > maxStoreDim {
+		return nil, fmt.Errorf("dimensi %d terlalu besar", dim)
+	}
+
+	vecs := make([][]float32, 0, min(count, 1<<16))
+	buf := make([]byte, 4*int(dim))
+	for i := uint32(0); i < count; i++ {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		v := make([]float32, dim)
+		for j := range v {
+			v[j] = math.Float32frombits(binary.LittleEndian.Uint32(buf[4*j:]))
+		}
+		vecs = append(vecs, v)
+	}
+	return vecs, nil
+}
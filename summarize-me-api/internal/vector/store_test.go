@@ -0,0 +1,96 @@
+package vector
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		dir  string
+	}{
+		{"disk", dir},
+		{"memori", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := OpenStore(tt.dir)
+			if err != nil {
+				t.Fatalf("OpenStore: %v", err)
+			}
+			vecs := [][]float32{{1, -2.5, 3}, {0, 0.125, -1}}
+			if err := s.Put("doc-1", vecs); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			got, err := s.Get("doc-1")
+			if err != nil || !reflect.DeepEqual(got, vecs) {
+				t.Fatalf("Get = %v, %v; want %v", got, err, vecs)
+			}
+			if err := s.Delete("doc-1"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if _, err := s.Get("doc-1"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get setelah Delete = %v, want ErrNotFound", err)
+			}
+			if err := s.Delete("doc-1"); err != nil {
+				t.Errorf("Delete kedua = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestStoreReopen(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vecs := [][]float32{{0.5, 0.25}}
+	if err := s.Put("doc-1", vecs); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	reopened, err := OpenStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reopened.Get("doc-1"); err != nil || !reflect.DeepEqual(got, vecs) {
+		t.Errorf("Get setelah dibuka ulang = %v, %v; want %v", got, err, vecs)
+	}
+}
+
+func TestStoreRejects(t *testing.T) {
+	dir := t.TempDir()
+	s, err := OpenStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"", ".", "..", "../luar", `a\b`} {
+		if err := s.Put(id, [][]float32{{1}}); err == nil {
+			t.Errorf("Put(%q) seharusnya error", id)
+		}
+	}
+	if err := s.Put("campur", [][]float32{{1, 2}, {1}}); !errors.Is(err, ErrDimension) {
+		t.Errorf("Put dimensi campur = %v, want ErrDimension", err)
+	}
+
+	corrupt := map[string][]byte{
+		"magic-salah": []byte("XXXX\x01\x00\x00\x00\x01\x00\x00\x00\x00\x00\x80\x3f"),
+		"terpotong":   []byte("VEC1\x02\x00\x00\x00\x01\x00\x00\x00\x00\x00\x80\x3f"),
+		"dim-raksasa": []byte("VEC1\x01\x00\x00\x00\xff\xff\xff\xff"),
+	}
+	for id, data := range corrupt {
+		if err := os.WriteFile(filepath.Join(dir, id+".vec"), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Get(id); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%s) = %v, want error file rusak", id, err)
+		}
+	}
+}